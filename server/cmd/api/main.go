@@ -14,9 +14,16 @@ import (
 	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/middleware"
 	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/request"
 	v1 "github.com/ryl1k/INT20H-test-task-server/internal/controller/http/v1"
+	exchangerepo "github.com/ryl1k/INT20H-test-task-server/internal/repo/exchange"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/geocode"
 	"github.com/ryl1k/INT20H-test-task-server/internal/repo/persistent"
 	"github.com/ryl1k/INT20H-test-task-server/internal/repo/tax"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase/boundary"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase/customer"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase/exchange"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase/exemption"
 	"github.com/ryl1k/INT20H-test-task-server/internal/usecase/order"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase/override"
 	"github.com/ryl1k/INT20H-test-task-server/pkg/httpserver"
 	"github.com/ryl1k/INT20H-test-task-server/pkg/logger"
 	"github.com/ryl1k/INT20H-test-task-server/pkg/postgres"
@@ -28,9 +35,16 @@ import (
 const (
 	// shutdownCtxTimeout is the maximum duration allowed for graceful shutdown.
 	shutdownCtxTimeout = time.Second * 10
+
+	// purgeInterval is how often deleted orders past retention are purged.
+	purgeInterval = time.Hour
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == validateTaxDataCommand {
+		os.Exit(runValidateTaxData(os.Args[2:]))
+	}
+
 	app := MustCreateNewApp()
 
 	errCh := make(chan error, 1)
@@ -91,18 +105,56 @@ func MustCreateNewApp() *app {
 	})
 
 	orderRepo := persistent.NewOrderRepo(pool)
-	taxRepo := tax.New(cfg.GeoJSON.Features, cfg.TaxConfig.Jurisdictions)
+	orderEventRepo := persistent.NewOrderEventRepo(pool)
+	taxRecalculationRepo := persistent.NewTaxRecalculationRepo(pool)
+	boundarySetRepo := persistent.NewBoundarySetRepo(pool)
+	exemptionRepo := persistent.NewExemptionRepo(pool)
+	customerRepo := persistent.NewCustomerRepo(pool)
+	taxOverrideRepo := persistent.NewTaxOverrideRepo(pool)
+	exchangeRateRepo := persistent.NewExchangeRateRepo(pool)
+	taxRepo, stateRepos := newTaxRepos(&cfg.TaxDataConfig)
+	multiStateRepo := tax.NewMultiState(taxRepo, stateRepos...)
+	geocodeRepo := geocode.New(cfg.ZipCentroids, cfg.ZipJurisdictions)
+	exchangeTable := exchangerepo.New()
+
+	orderService := order.New(ctx, multiStateRepo, orderRepo, exemptionRepo, customerRepo, geocodeRepo, orderEventRepo, taxRecalculationRepo, exchangeTable, cfg.BatchOrderProcessingTimeout, cfg.OrdersBatchSize, cfg.DeletedOrdersRetention, cfg.RatesVersion(), logger)
+	boundaryService := boundary.New(multiStateRepo, boundarySetRepo, logger)
+	exemptionService := exemption.New(multiStateRepo, exemptionRepo, logger)
+	customerService := customer.New(customerRepo, orderRepo, logger)
+	overrideService := override.New(multiStateRepo, taxOverrideRepo, cfg.TaxConfig.Overrides, logger)
+	exchangeService := exchange.New(exchangeTable, exchangeRateRepo, cfg.ExchangeRates, logger)
+
+	if err := boundaryService.LoadActive(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("failed to load active boundary set")
+	}
+
+	if err := overrideService.Load(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("failed to load tax overrides")
+	}
+
+	if err := exchangeService.Load(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("failed to load exchange rates")
+	}
+
+	go orderService.RunPurge(ctx, purgeInterval)
 
-	orderService := order.New(ctx, taxRepo, orderRepo, cfg.BatchOrderProcessingTimeout, cfg.OrdersBatchSize, logger)
+	checkTaxData(ctx, logger, append([]*tax.Tax{taxRepo}, stateRepos...), cfg.AmbiguityPolicy, cfg.StrictTaxDataValidation)
 
 	httpServer := httpserver.NewHttpServer(cfg.HttpServerPort)
 
 	orderController := v1.NewOrdersController(orderService, int64(cfg.MaxFileSize), logger)
+	boundariesController := v1.NewBoundariesController(boundaryService, int64(cfg.MaxBoundaryFileSize), logger)
+	exemptionsController := v1.NewExemptionsController(exemptionService, logger)
+	overridesController := v1.NewTaxOverridesController(overrideService, logger)
+	taxController := v1.NewTaxController(orderService, logger)
+	recalcController := v1.NewTaxRecalculationsController(orderService, logger)
+	exchangeController := v1.NewExchangeRatesController(exchangeService, logger)
+	customersController := v1.NewCustomersController(customerService, logger)
 
 	requestValidator := request.NewCustomValidator()
-	middleware := middleware.NewMiddleware(cfg.ApiKey)
+	middleware := middleware.NewMiddleware(cfg.ApiKey, cfg.AdminApiKey)
 
-	router := httpcontroller.NewRouter(httpServer.GetInstance(), orderController, middleware, requestValidator)
+	router := httpcontroller.NewRouter(httpServer.GetInstance(), orderController, boundariesController, exemptionsController, overridesController, taxController, recalcController, exchangeController, customersController, middleware, requestValidator)
 	router.RegisterRoutes()
 
 	return &app{
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/config"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/tax"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// validateTaxDataCommand is the CLI subcommand that checks
+	// consistency of the boundary file and the tax config and exits.
+	validateTaxDataCommand = "validate-tax-data"
+)
+
+// runValidateTaxData validates the configured tax data files of every state,
+// prints the report as JSON to stdout and returns the process exit code.
+// The exit code is non-zero when a boundary set is invalid, or, with -strict,
+// when it is not fully consistent with the tax config.
+func runValidateTaxData(args []string) int {
+	fs := flag.NewFlagSet(validateTaxDataCommand, flag.ContinueOnError)
+	strict := fs.Bool("strict", false, "fail on unused tax config entries and duplicate feature names")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.MustCreateTaxDataConfig()
+	taxRepo, stateRepos := newTaxRepos(cfg)
+
+	ctx := context.Background()
+	report := taxRepo.ValidateFeatures(ctx, cfg.GeoJSON.Features)
+	layerReports := taxRepo.ValidateLayers(ctx)
+
+	stateReports := make(map[string]entity.BoundaryValidationReport, len(stateRepos))
+	stateLayerReports := make(map[string]map[string]entity.BoundaryValidationReport, len(stateRepos))
+	for _, stateRepo := range stateRepos {
+		stateReports[stateRepo.State()] = stateRepo.ValidateFeatures(ctx, stateRepo.GetActiveFeatures(ctx))
+		stateLayerReports[stateRepo.State()] = stateRepo.ValidateLayers(ctx)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(map[string]any{
+		"boundaries":   report,
+		"layers":       layerReports,
+		"states":       stateReports,
+		"state_layers": stateLayerReports,
+	})
+	if err != nil {
+		return 1
+	}
+
+	if !reportPasses(report, *strict) {
+		return 1
+	}
+	for _, stateReport := range stateReports {
+		if !reportPasses(stateReport, *strict) {
+			return 1
+		}
+	}
+	for _, layerReport := range layerReports {
+		if !reportPasses(layerReport, *strict) {
+			return 1
+		}
+	}
+	for _, reports := range stateLayerReports {
+		for _, layerReport := range reports {
+			if !reportPasses(layerReport, *strict) {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// newTaxRepos builds the tax engine of the primary state and the engines
+// of the additional states, each with its own tax layers.
+func newTaxRepos(cfg *config.TaxDataConfig) (*tax.Tax, []*tax.Tax) {
+	opts := tax.Options{
+		State:           cfg.PrimaryState,
+		ToleranceMeters: cfg.BoundaryToleranceMeters,
+		AmbiguityPolicy: cfg.AmbiguityPolicy,
+		CacheSize:       cfg.TaxLookupCacheSize,
+		CachePrecision:  cfg.TaxLookupCachePrecision,
+		Outline:         cfg.StateOutline.MultiPolygon(),
+	}
+	primary := tax.New(cfg.GeoJSON.Features, cfg.TaxConfig.Jurisdictions, cfg.TaxLayers.Layers, opts)
+
+	states := make([]*tax.Tax, 0, len(cfg.States))
+	for _, s := range cfg.States {
+		opts.State = s.Code
+		opts.PropertyKey = s.PropertyKey
+		opts.Outline = s.Outline.MultiPolygon()
+		states = append(states, tax.New(s.GeoJSON.Features, s.Jurisdictions, s.TaxLayers, opts))
+	}
+
+	return primary, states
+}
+
+func reportPasses(report entity.BoundaryValidationReport, strict bool) bool {
+	if strict {
+		return report.Consistent()
+	}
+	return report.Valid
+}
+
+// checkTaxData logs the consistency report of the active boundaries of every
+// state, including every pair of overlapping jurisdictions whose shared area
+// is resolved by the ambiguity policy. In strict mode any inconsistency is fatal.
+func checkTaxData(ctx context.Context, logger zerolog.Logger, taxRepos []*tax.Tax, policy entity.AmbiguityPolicy, strict bool) {
+	consistent := true
+
+	for _, taxRepo := range taxRepos {
+		l := logger.With().Str("state", taxRepo.State()).Logger()
+
+		features := taxRepo.GetActiveFeatures(ctx)
+		report := taxRepo.ValidateFeatures(ctx, features)
+
+		logReport(l, report)
+		if len(report.Overlaps) > 0 {
+			l.Warn().Int("count", len(report.Overlaps)).Str("policy", string(policy)).Msg("locations in overlapping jurisdictions are resolved by ambiguity policy")
+		}
+		consistent = consistent && report.Consistent()
+
+		for name, layerReport := range taxRepo.ValidateLayers(ctx) {
+			logReport(l.With().Str("layer", name).Logger(), layerReport)
+			consistent = consistent && layerReport.Consistent()
+		}
+	}
+
+	if strict && !consistent {
+		logger.Fatal().Msg("tax data is inconsistent and strict validation is enabled")
+	}
+}
+
+func logReport(logger zerolog.Logger, report entity.BoundaryValidationReport) {
+	for _, issue := range report.Errors {
+		logger.Warn().Int("feature_index", issue.FeatureIndex).Str("name", issue.Name).Msg(issue.Message)
+	}
+	if len(report.UncoveredJurisdictions) > 0 {
+		logger.Warn().Strs("jurisdictions", report.UncoveredJurisdictions).Msg("tax config entries without boundary feature")
+	}
+	if len(report.DuplicateNames) > 0 {
+		logger.Warn().Strs("names", report.DuplicateNames).Msg("duplicate boundary feature names")
+	}
+	for _, overlap := range report.Overlaps {
+		logger.Warn().
+			Int("first_index", overlap.FirstIndex).Str("first", overlap.First).
+			Int("second_index", overlap.SecondIndex).Str("second", overlap.Second).
+			Msg("overlapping boundary features")
+	}
+	for _, gap := range report.Gaps {
+		logger.Warn().
+			Int("feature_index", gap.FeatureIndex).Str("name", gap.Name).
+			Float64("latitude", gap.Latitude).Float64("longitude", gap.Longitude).
+			Msg("area of the state outline without boundary feature")
+	}
+}
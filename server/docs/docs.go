@@ -15,237 +15,232 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/orders": {
-            "get": {
+        "/v1/admin/boundaries": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve a paginated list of orders with optional filters for status, amount, and dates.",
+                "description": "Uploads a GeoJSON feature collection of a state, validates it against the tax config of the state and previews the diff against the active boundaries of the state. The set is staged until activated.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Get list of orders",
+                "summary": "Upload a boundary set",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Limit for pagination",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset for pagination",
-                        "name": "page",
-                        "in": "query",
+                        "type": "file",
+                        "description": "GeoJSON file containing boundary features",
+                        "name": "boundaries",
+                        "in": "formData",
                         "required": true
                     },
-                    {
-                        "enum": [
-                            "completed",
-                            "out_of_scope"
-                        ],
-                        "type": "string",
-                        "description": "Filter by order status",
-                        "name": "status",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by reporting code",
-                        "name": "reporting_code",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Minimum total amount",
-                        "name": "total_amount_min",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Maximum total amount",
-                        "name": "total_amount_max",
-                        "in": "query"
+                        "description": "Code of the state the boundaries belong to, the primary state by default",
+                        "name": "state",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BoundarySet"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "2023-01-01T00:00:00Z",
-                        "description": "Start date (ISO8601)",
-                        "name": "from_date",
-                        "in": "query"
+                    "400": {
+                        "description": "Invalid file format or unknown state",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "2023-12-31T23:59:59Z",
-                        "description": "End date (ISO8601)",
-                        "name": "to_date",
-                        "in": "query"
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/boundaries/{id}": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Sort by field (id, created_at, total_amount)",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the validation report and diff of a boundary set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get boundary set by ID",
+                "parameters": [
                     {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "Sort order (asc, desc)",
-                        "name": "sort_order",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Boundary set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.OrderList"
+                            "$ref": "#/definitions/entity.BoundarySet"
                         }
                     },
                     "400": {
-                        "description": "Invalid pagination query params",
+                        "description": "Invalid ID format",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Boundary set not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/admin/boundaries/{id}/activate": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Manually create a new order with tax rates and jurisdictions.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Re-validates a staged boundary set and makes it the active one used for tax lookups in its state.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Create a single order",
+                "summary": "Activate a boundary set",
                 "parameters": [
                     {
-                        "description": "Order data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.Order"
-                        }
+                        "type": "integer",
+                        "description": "Boundary set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Order"
+                            "$ref": "#/definitions/entity.BoundarySet"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation failed",
+                        "description": "Invalid ID format",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Boundary set not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Boundary set failed validation",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/admin/exchange-rates": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove all orders from the database.",
+                "description": "Returns the rates from the rate file and the admin API orders are converted with, sorted by currency and date.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Delete all orders",
+                "summary": "List exchange rates",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ExchangeRate"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/v1/orders/import": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Uploads a CSV file, validates format and size, and processes orders asynchronously.",
+                "description": "Sets the value in USD of one unit of each currency from the given date on, replacing any rate of the same currency and date. Rates set here take precedence over the rate file and take effect immediately for new orders.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Batch create orders from CSV",
+                "summary": "Set exchange rates",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "CSV file containing orders data",
-                        "name": "orders",
-                        "in": "formData",
-                        "required": true
+                        "description": "Exchange rates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExchangeRates"
+                        }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Successfully accepted for processing",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ExchangeRate"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid file format or file too large",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "File not found",
+                        "description": "Invalid request body or validation failed",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -253,48 +248,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/orders/{id}": {
-            "get": {
+        "/v1/admin/orders": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetch detailed information about a specific order using its unique identifier.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Removes all orders from the database for good, including deleted ones. Requires the token issued by the wipe confirmation request. The history of the orders is kept.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Get order by ID",
+                "summary": "Delete all orders",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Wipe confirmation token",
+                        "name": "confirmation_token",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.Order"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid ID format",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "404": {
-                        "description": "Order not found",
+                    "403": {
+                        "description": "Invalid or expired confirmation token",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -307,89 +290,3344 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.Order": {
-            "type": "object",
-            "required": [
-                "latitude",
-                "longitude",
-                "subtotal",
-                "timestamp"
-            ],
-            "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "latitude": {
-                    "type": "number"
-                },
-                "longitude": {
-                    "type": "number"
-                },
-                "subtotal": {
-                    "type": "number"
-                },
-                "timestamp": {
-                    "type": "string"
-                }
-            }
         },
-        "entity.Order": {
-            "type": "object",
-            "properties": {
-                "breakdown": {
-                    "$ref": "#/definitions/entity.TaxRateBreakdown"
-                },
-                "composite_tax_rate": {
-                    "type": "number"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "jurisdictions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "latitude": {
+        "/v1/admin/orders/out-of-scope/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Resolves the tax of the out-of-scope orders, of the orders whose tax failed to resolve and of the imported orders left pending, matching the filters again with the loaded tax data and boundaries, and stores the ones that now match a jurisdiction as completed with a full breakdown. Pending orders are stored with whatever status their tax resolves to. The filters take the same query params as listing orders, except status. Every resolved order is recorded in its history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Retry out-of-scope orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated order IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Import job ID",
+                        "name": "import_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code of the state the order was taxed in",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer reference",
+                        "name": "customer_ref",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ambiguous_jurisdiction"
+                        ],
+                        "type": "string",
+                        "description": "Order warning",
+                        "name": "warning",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "coordinates",
+                            "zip_jurisdiction",
+                            "zip_centroid",
+                            "unresolved"
+                        ],
+                        "type": "string",
+                        "description": "How the order location was obtained",
+                        "name": "geocoding_method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total amount",
+                        "name": "total_amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total amount",
+                        "name": "total_amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-01-01T00:00:00Z",
+                        "description": "Start date (ISO8601)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-12-31T23:59:59Z",
+                        "description": "End date (ISO8601)",
+                        "name": "to_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OutOfScopeResolution"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filters",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/orders/wipe-confirmations": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issues the token that confirms deleting all orders. Only the latest token is valid, once and for five minutes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Request a wipe of all orders",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WipeConfirmation"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/tax-overrides": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the overrides from the admin API and the tax config in the order of their precedence.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List tax overrides",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TaxOverride"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a time-bounded override applied to orders placed within [starts_at, ends_at) in the covered jurisdictions of its state (\"*\" covers all of them, an empty state means the primary one), optionally limited to a category. Without rate the listed components (or the whole tax when none are listed) are exempt; with rate the single listed component is replaced. Takes effect immediately.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a tax override",
+                "parameters": [
+                    {
+                        "description": "Override data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxOverride"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxOverride"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, validation failed or unknown state",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Override with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/tax-overrides/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes an override created through the admin API. Overrides from the tax config cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a tax override",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Override ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Override not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/tax-recalculations": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Resolves the tax of the orders matching the filter again with the loaded rates and stores the orders whose tax would change, without changing them. Voided and refunded orders are skipped. Only the loaded rates can be previewed, other versions cannot: when expected_rates_version is given it must match the loaded tax data, including the active boundaries and overrides. Tax amounts are compared in cents.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Preview a tax recalculation",
+                "parameters": [
+                    {
+                        "description": "Orders to recalculate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxRecalculation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRecalculation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Loaded rates differ from the expected version",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/tax-recalculations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the previewed changes of a tax recalculation and the progress of applying them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get tax recalculation by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax recalculation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRecalculation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax recalculation not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/tax-recalculations/{id}/apply": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Applies the previewed changes in batches in the background and records them in the order history. Orders changed since the preview, or whose tax no longer comes out as previewed, are left out as conflicts. A recalculation is applied once and only with the rates it was previewed with; poll it by ID for the progress.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Apply a tax recalculation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax recalculation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRecalculation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax recalculation not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Already applied or loaded rates differ from the preview",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a paginated list of customers ordered by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "List customers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit for pagination",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination query params",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registers a customer by its external reference. The address, ZIP code or coordinates are the default location of orders placed by the customer without one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Create a customer",
+                "parameters": [
+                    {
+                        "description": "Customer data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Customer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Customer with this external reference already exists",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the customer with its default location and whether it holds an exemption certificate valid now.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Get customer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the name and default location of the customer. Orders already placed keep the location they were taxed at. The external reference cannot be changed, as it links orders to the exemption certificates of the customer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Update a customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Customer data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Customer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "External reference differs from the stored one",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the customer together with its exemption certificates. Its orders are kept and no longer linked to it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Delete a customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers/{id}/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a paginated list of the orders placed by the customer. It takes the same query filters as listing orders.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "List orders of a customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit for pagination",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or query params",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers/{id}/totals": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sums up the orders placed by the customer. Amounts are net of refunds and leave out voided orders, which are only counted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Get order totals of a customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerTotals"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/exemption-certificates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns all exemption certificates registered for the customer reference.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exemptions"
+                ],
+                "summary": "List exemption certificates of a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer reference",
+                        "name": "customer_ref",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ExemptionCertificate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing customer reference",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registers a tax exemption certificate for a customer. The customer is created on first use. Orders referencing the customer have the listed tax components zeroed out in covered jurisdictions of the certificate state (\"*\" covers all of them, an empty state means the primary one) from valid_from up to, but not including, valid_to; an empty component list exempts the whole tax.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exemptions"
+                ],
+                "summary": "Register an exemption certificate",
+                "parameters": [
+                    {
+                        "description": "Certificate data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExemptionCertificate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExemptionCertificate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Certificate already exists",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of orders with optional filters for status, amount, and dates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get list of orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit for pagination",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by comma-separated order IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by import job ID",
+                        "name": "import_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by comma-separated order statuses (pending, completed, out_of_scope, failed_tax_resolution, voided, refunded, partially_refunded)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by code of the state the order was taxed in",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by reporting code",
+                        "name": "reporting_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by product category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by customer reference",
+                        "name": "customer_ref",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by ID of the customer",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name of the applied tax override",
+                        "name": "tax_override",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by orders resolved on a shared edge or snapped to a nearby boundary",
+                        "name": "boundary_resolved",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ambiguous_jurisdiction"
+                        ],
+                        "type": "string",
+                        "description": "Filter by order warning",
+                        "name": "warning",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "coordinates",
+                            "zip_jurisdiction",
+                            "zip_centroid",
+                            "unresolved"
+                        ],
+                        "type": "string",
+                        "description": "Filter by how the order location was obtained",
+                        "name": "geocoding_method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total amount",
+                        "name": "total_amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total amount",
+                        "name": "total_amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-01-01T00:00:00Z",
+                        "description": "Start date (ISO8601)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-12-31T23:59:59Z",
+                        "description": "End date (ISO8601)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by field (id, created_at, total_amount, net_total_amount, status)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (asc, desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filter by metadata value given as key=value, repeatable",
+                        "name": "metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by comma-separated tags the order must all have",
+                        "name": "tags",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination query params",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Manually create a new order with tax rates and jurisdictions. Orders without coordinates are geocoded offline by zip or the ZIP code found in address. Items, when given, replace the subtotal and are taxed one by one by their own category. Set tax_inclusive when the amounts already include the tax, which is then backed out of them. Set explain to store how the tax was derived on the order. Orders given a customer_id, or the customer_ref of a registered customer, are linked to the customer and placed at its default location when they have none.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create a single order",
+                "parameters": [
+                    {
+                        "description": "Order data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "No exchange rate for the currency at the order date",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the orders matching the filters, which take the same query params as listing orders. The ` + "`" + `ids` + "`" + `, ` + "`" + `import_id` + "`" + ` or ` + "`" + `customer_id` + "`" + ` filter is required, and the other filters narrow it down; wiping all orders is an admin operation. Deleted orders are hidden and can be restored until the retention period ends, when they are purged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Delete orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated order IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Import job ID",
+                        "name": "import_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated order statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code of the state the order was taxed in",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reporting code",
+                        "name": "reporting_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer reference",
+                        "name": "customer_ref",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the customer",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the applied tax override",
+                        "name": "tax_override",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Orders resolved on a shared edge or snapped to a nearby boundary",
+                        "name": "boundary_resolved",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ambiguous_jurisdiction"
+                        ],
+                        "type": "string",
+                        "description": "Order warning",
+                        "name": "warning",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "coordinates",
+                            "zip_jurisdiction",
+                            "zip_centroid",
+                            "unresolved"
+                        ],
+                        "type": "string",
+                        "description": "How the order location was obtained",
+                        "name": "geocoding_method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total amount",
+                        "name": "total_amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total amount",
+                        "name": "total_amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-01-01T00:00:00Z",
+                        "description": "Start date (ISO8601)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-12-31T23:59:59Z",
+                        "description": "End date (ISO8601)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Metadata value given as key=value, repeatable",
+                        "name": "metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags the order must all have",
+                        "name": "tags",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderDeletion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing filters",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Uploads a CSV file, validates format and size, and processes orders asynchronously. Columns: id, longitude, latitude, timestamp, subtotal, then optional product category, customer reference, address, shipping, handling, discount, a JSON array of items, which replaces the subtotal, the currency code, a JSON object of metadata and a JSON array of tags. Set tax_inclusive when the amounts of all rows already include the tax.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Batch create orders from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file containing orders data",
+                        "name": "orders",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Amounts already include the tax",
+                        "name": "tax_inclusive",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully accepted for processing, with the id of the import job",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file format or file too large",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restores the deleted orders matching the filters that were not purged yet. The filters take the same query params as listing orders. The ` + "`" + `ids` + "`" + `, ` + "`" + `import_id` + "`" + ` or ` + "`" + `customer_id` + "`" + ` filter is required, like for deletion.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Restore deleted orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated order IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Import job ID",
+                        "name": "import_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated order statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code of the state the order was taxed in",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reporting code",
+                        "name": "reporting_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer reference",
+                        "name": "customer_ref",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the customer",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the applied tax override",
+                        "name": "tax_override",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Orders resolved on a shared edge or snapped to a nearby boundary",
+                        "name": "boundary_resolved",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ambiguous_jurisdiction"
+                        ],
+                        "type": "string",
+                        "description": "Order warning",
+                        "name": "warning",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "coordinates",
+                            "zip_jurisdiction",
+                            "zip_centroid",
+                            "unresolved"
+                        ],
+                        "type": "string",
+                        "description": "How the order location was obtained",
+                        "name": "geocoding_method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total amount",
+                        "name": "total_amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total amount",
+                        "name": "total_amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-01-01T00:00:00Z",
+                        "description": "Start date (ISO8601)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-12-31T23:59:59Z",
+                        "description": "End date (ISO8601)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Metadata value given as key=value, repeatable",
+                        "name": "metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags the order must all have",
+                        "name": "tags",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderRestoration"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing filters",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetch detailed information about a specific order, including its items and refunds, using its unique identifier. The ETag header carries the order version for conditional updates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Corrects the coordinates, subtotal or timestamp of an order and resolves its tax again; omitted fields are kept. The subtotal of an order with items comes from the items and cannot be set. Send the ETag of the order in If-Match to reject the update when the order was changed meanwhile. Voided and refunded orders cannot be updated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Update an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order version being updated",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Corrected fields",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format, If-Match header or request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Order was changed meanwhile or can no longer be changed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists every change of an order from the oldest: creation, import, update, void and refund, with the actor (API key fingerprint and the user named in the x-user header), the source (API or import job id) and the order before and after the change. The history outlives the order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.OrderEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{id}/refunds": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Refunds a full or partial return. Lines refund the pre-tax amount of the subtotal, shipping or handling, or of an item line of the subtotal, everything left on it when their amount is omitted. The amount is spread over everything left on the order after the lines, and omitting both refunds everything left. The tax of every component and item line is reversed at its own rate, and the refund lists how it was allocated. Only completed and partially_refunded orders can be refunded. The order becomes partially_refunded, or refunded once nothing is left, and its net figures drop accordingly.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Refund an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderRefund"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderRefund"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Order was changed meanwhile or cannot be refunded in its status",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Refund exceeds the amount left on the order or a line of it, or names a line the order does not have",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Voids an order that was recorded by mistake. Send the ETag of the order in If-Match to reject voiding when the order was changed meanwhile. Voided and refunded orders cannot be voided.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Void an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order version being voided",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Order was changed meanwhile or can no longer be changed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tax/explain": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Calculates the tax of an order at the location without storing it and returns every R-tree candidate feature, which of them contain the point, the winner by index priority, the matched tax config entry and layers, the applied override and every computation step.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Explain tax of a location",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude, required unless zip is given",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, required unless zip is given",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ZIP or ZIP+4 code geocoded when coordinates are not given",
+                        "name": "zip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Order subtotal",
+                        "name": "subtotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Shipping charge",
+                        "name": "shipping",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Handling charge",
+                        "name": "handling",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Discount taken off the subtotal",
+                        "name": "discount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Amounts already include the tax",
+                        "name": "tax_inclusive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer reference",
+                        "name": "customer_ref",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-08-09T12:00:00Z",
+                        "description": "Order time (ISO8601), defaults to now",
+                        "name": "timestamp",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxExplanation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query params",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.Customer": {
+            "type": "object",
+            "required": [
+                "external_ref"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "external_ref": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "zip": {
+                    "type": "string",
+                    "maxLength": 10
+                }
+            }
+        },
+        "dto.ExchangeRate": {
+            "type": "object",
+            "required": [
+                "currency",
+                "date"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.ExchangeRates": {
+            "type": "object",
+            "required": [
+                "rates"
+            ],
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.ExchangeRate"
+                    }
+                }
+            }
+        },
+        "dto.ExemptionCertificate": {
+            "type": "object",
+            "required": [
+                "customer_ref",
+                "jurisdictions",
+                "number",
+                "type",
+                "valid_from"
+            ],
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TaxLayerLevel"
+                    }
+                },
+                "customer_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "customer_ref": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "jurisdictions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "number": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "state": {
+                    "description": "State is the code of the state the certificate was issued in.\nEmpty means the primary state.",
+                    "type": "string",
+                    "maxLength": 8
+                },
+                "type": {
+                    "enum": [
+                        "resale",
+                        "nonprofit",
+                        "government",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ExemptionType"
+                        }
+                    ]
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Order": {
+            "type": "object",
+            "required": [
+                "timestamp"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Address and Zip locate orders without coordinates. The ZIP code\nis taken from Zip or, when empty, found in the address line.",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "currency": {
+                    "description": "Currency is the ISO 4217 code of the currency of the amounts,\nUSD when empty. Other currencies are converted to USD at the\nexchange rate of the order date.",
+                    "type": "string"
+                },
+                "customer_id": {
+                    "description": "CustomerId links the order to a registered customer, who can be\ngiven by CustomerRef instead. Orders without a location of their\nown are placed at the default location of the customer.",
+                    "type": "integer"
+                },
+                "customer_ref": {
+                    "description": "CustomerRef references the customer whose exemption\ncertificates are applied to the order.",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "discount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "explain": {
+                    "description": "Explain requests storing how the tax was derived on the order.",
+                    "type": "boolean"
+                },
+                "handling": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Items are the order lines. When given, the subtotal is derived\nfrom them and every item is taxed by its own category.",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderItem"
+                    }
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "metadata": {
+                    "description": "Metadata and Tags label the order for reconciliation.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "shipping": {
+                    "description": "Shipping and Handling are charged on top of the subtotal,\nDiscount is taken off it. Each is taxed by its own rule.",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tax_inclusive": {
+                    "description": "TaxInclusive tells the amounts already include the tax,\nwhich is then backed out of them instead of added on top.",
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "zip": {
+                    "type": "string",
+                    "maxLength": 10
+                }
+            }
+        },
+        "dto.OrderImport": {
+            "type": "object",
+            "properties": {
+                "import_id": {
+                    "description": "Id identifies the import job in the history of its orders.",
+                    "type": "string"
+                },
+                "tax_inclusive": {
+                    "description": "TaxInclusive tells the amounts of every row already include the tax.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.OrderItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "discount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "unit_price": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.OrderRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is the pre-tax amount returned, spread over everything left\non the order after the lines. Zero without lines refunds everything\nleft on the order.",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "lines": {
+                    "description": "Lines are refunded from the given components or item lines.",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderRefundLine"
+                    }
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 256
+                }
+            }
+        },
+        "dto.OrderRefundLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "component": {
+                    "type": "string",
+                    "enum": [
+                        "subtotal",
+                        "shipping",
+                        "handling"
+                    ],
+                    "example": "shipping"
+                },
+                "line": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.OrderUpdate": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "subtotal": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TaxOverride": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "jurisdictions",
+                "name",
+                "starts_at"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TaxLayerLevel"
+                    }
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "jurisdictions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "description": "State is the code of the state the override applies to.\nEmpty means the primary state.",
+                    "type": "string",
+                    "maxLength": 8
+                }
+            }
+        },
+        "dto.TaxRecalculation": {
+            "type": "object",
+            "properties": {
+                "expected_rates_version": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "from_date": {
+                    "type": "string"
+                },
+                "reporting_code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "completed",
+                        "out_of_scope",
+                        "failed_tax_resolution"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.OrderStatus"
+                        }
+                    ]
+                },
+                "to_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Actor": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BoundaryDiff": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unchanged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BoundaryGap": {
+            "type": "object",
+            "properties": {
+                "feature_index": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BoundaryIssue": {
+            "type": "object",
+            "properties": {
+                "feature_index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BoundaryOverlap": {
+            "type": "object",
+            "properties": {
+                "first": {
+                    "type": "string"
+                },
+                "first_index": {
+                    "type": "integer"
+                },
+                "second": {
+                    "type": "string"
+                },
+                "second_index": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BoundarySet": {
+            "type": "object",
+            "properties": {
+                "activated_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "$ref": "#/definitions/entity.BoundaryDiff"
+                },
+                "feature_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "report": {
+                    "$ref": "#/definitions/entity.BoundaryValidationReport"
+                },
+                "state": {
+                    "description": "State is the code of the state the boundaries belong to.\nAn empty state means the primary state.",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.BoundarySetStatus"
+                }
+            }
+        },
+        "entity.BoundarySetStatus": {
+            "type": "string",
+            "enum": [
+                "staged",
+                "active",
+                "superseded"
+            ],
+            "x-enum-varnames": [
+                "BoundarySetStatusStaged",
+                "BoundarySetStatusActive",
+                "BoundarySetStatusSuperseded"
+            ]
+        },
+        "entity.BoundaryValidationReport": {
+            "type": "object",
+            "properties": {
+                "duplicate_names": {
+                    "description": "DuplicateNames lists names shared by more than one feature,\nwhich usually indicates a copy/paste error in the boundary file.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BoundaryIssue"
+                    }
+                },
+                "gaps": {
+                    "description": "Gaps lists areas inside the state outline covered by no feature.\nIt is empty when no outline is configured for the state.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BoundaryGap"
+                    }
+                },
+                "overlaps": {
+                    "description": "Overlaps lists feature pairs whose interiors intersect.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BoundaryOverlap"
+                    }
+                },
+                "uncovered_jurisdictions": {
+                    "description": "UncoveredJurisdictions lists tax config entries\nthat are not referenced by any feature. Areas without\na feature are reported as Gaps.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.ComputationStep": {
+            "type": "object",
+            "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/entity.JurisdictionTaxBreakdown"
+                },
+                "composite_rate": {
+                    "type": "number"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entity.ComputationStepKind"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ComputationStepKind": {
+            "type": "string",
+            "enum": [
+                "jurisdiction",
+                "layer",
+                "override",
+                "taxability",
+                "exemption",
+                "items"
+            ],
+            "x-enum-varnames": [
+                "ComputationStepJurisdiction",
+                "ComputationStepLayer",
+                "ComputationStepOverride",
+                "ComputationStepTaxability",
+                "ComputationStepExemption",
+                "ComputationStepItems"
+            ]
+        },
+        "entity.Customer": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address, Zip and the coordinates are the default location\nof orders placed by the customer without one.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "exemption_status": {
+                    "description": "ExemptionStatus tells whether the customer holds an exemption\ncertificate valid now. It is derived from the certificates.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.CustomerExemptionStatus"
+                        }
+                    ]
+                },
+                "external_ref": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "zip": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CustomerExemptionStatus": {
+            "type": "string",
+            "enum": [
+                "none",
+                "active",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "CustomerExemptionStatusNone",
+                "CustomerExemptionStatusActive",
+                "CustomerExemptionStatusExpired"
+            ]
+        },
+        "entity.CustomerList": {
+            "type": "object",
+            "properties": {
+                "customers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Customer"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CustomerTotals": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "first_order_at": {
+                    "type": "string"
+                },
+                "last_order_at": {
+                    "type": "string"
+                },
+                "net_tax_amount": {
+                    "type": "number"
+                },
+                "net_total_amount": {
+                    "type": "number"
+                },
+                "order_count": {
+                    "type": "integer"
+                },
+                "voided_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "source": {
+                    "$ref": "#/definitions/entity.ExchangeRateSource"
+                }
+            }
+        },
+        "entity.ExchangeRateSource": {
+            "type": "string",
+            "enum": [
+                "file",
+                "api"
+            ],
+            "x-enum-varnames": [
+                "ExchangeRateSourceFile",
+                "ExchangeRateSourceApi"
+            ]
+        },
+        "entity.ExemptionCertificate": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TaxLayerLevel"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "customer_ref": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jurisdictions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "number": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.ExemptionType"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ExemptionType": {
+            "type": "string",
+            "enum": [
+                "resale",
+                "nonprofit",
+                "government",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "ExemptionTypeResale",
+                "ExemptionTypeNonprofit",
+                "ExemptionTypeGovernment",
+                "ExemptionTypeOther"
+            ]
+        },
+        "entity.FeatureMatch": {
+            "type": "object",
+            "properties": {
+                "contains": {
+                    "type": "boolean"
+                },
+                "distance": {
+                    "description": "Distance is the distance in meters to a feature\nthe point was snapped to.",
+                    "type": "number"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Geocode": {
+            "type": "object",
+            "properties": {
+                "jurisdiction": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "method": {
+                    "$ref": "#/definitions/entity.GeocodingMethod"
+                },
+                "precision": {
+                    "$ref": "#/definitions/entity.GeocodingPrecision"
+                },
+                "state": {
+                    "description": "State and Jurisdiction are the state dataset and its\ntax config entry the ZIP code maps to.",
+                    "type": "string"
+                },
+                "zip": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.GeocodingMethod": {
+            "type": "string",
+            "enum": [
+                "coordinates",
+                "zip_jurisdiction",
+                "zip_centroid",
+                "unresolved"
+            ],
+            "x-enum-varnames": [
+                "GeocodingMethodCoordinates",
+                "GeocodingMethodZipJurisdiction",
+                "GeocodingMethodZipCentroid",
+                "GeocodingMethodUnresolved"
+            ]
+        },
+        "entity.GeocodingPrecision": {
+            "type": "string",
+            "enum": [
+                "exact",
+                "zip4",
+                "zip5",
+                "none"
+            ],
+            "x-enum-varnames": [
+                "GeocodingPrecisionExact",
+                "GeocodingPrecisionZip4",
+                "GeocodingPrecisionZip5",
+                "GeocodingPrecisionNone"
+            ]
+        },
+        "entity.JurisdictionTax": {
+            "type": "object",
+            "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/entity.JurisdictionTaxBreakdown"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "component_taxability": {
+                    "description": "ComponentTaxability maps shipping, handling and discount to the rule\napplied to them on top of the rates of the merchandise.\nComponents without a rule follow the merchandise.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/entity.TaxabilityRule"
+                    }
+                },
+                "composite_rate": {
+                    "type": "number"
+                },
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "taxability": {
+                    "description": "Taxability maps product categories to the rule applied to them.\nCategories without a rule are taxed at the full composite rate.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/entity.TaxabilityRule"
+                    }
+                }
+            }
+        },
+        "entity.JurisdictionTaxBreakdown": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "number"
+                },
+                "county": {
+                    "type": "number"
+                },
+                "special": {
+                    "type": "number"
+                },
+                "state": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.LayerMatch": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeatureMatch"
+                    }
+                },
+                "layer": {
+                    "type": "string"
+                },
+                "level": {
+                    "$ref": "#/definitions/entity.TaxLayerLevel"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "winner": {
+                    "$ref": "#/definitions/entity.FeatureMatch"
+                }
+            }
+        },
+        "entity.Order": {
+            "type": "object",
+            "properties": {
+                "ambiguous_jurisdictions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "boundary_distance": {
+                    "type": "number"
+                },
+                "boundary_resolved": {
+                    "description": "BoundaryResolved flags orders whose location lay on a shared\njurisdiction edge or was snapped to the nearest jurisdiction.\nBoundaryDistance is the snapping distance in meters.",
+                    "type": "boolean"
+                },
+                "breakdown": {
+                    "$ref": "#/definitions/entity.TaxRateBreakdown"
+                },
+                "category": {
+                    "description": "Category is the product category used to pick the taxability rule.",
+                    "type": "string"
+                },
+                "components": {
+                    "description": "Components holds the taxable base and tax of every component.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderComponentTax"
+                    }
+                },
+                "composite_tax_rate": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency is the ISO 4217 code of the currency the order was placed in.\nAll other amounts are in USD, converted at ExchangeRate, the value\nin USD of one unit of the currency at the order date. Original holds\nthe amounts as given, only for orders placed in another currency.",
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "customer_ref": {
+                    "description": "CustomerRef references the customer, and CustomerId links\nthe order to it when the customer is registered.",
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "exchange_rate": {
+                    "type": "number"
+                },
+                "exemption_certificate": {
+                    "description": "ExemptionCertificate is the number of the certificate\napplied to the order, if any.",
+                    "type": "string"
+                },
+                "explain": {
+                    "description": "Explain describes how the tax was derived.\nIt is only stored when requested on order creation.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.TaxExplanation"
+                        }
+                    ]
+                },
+                "geocoding_method": {
+                    "$ref": "#/definitions/entity.GeocodingMethod"
+                },
+                "geocoding_precision": {
+                    "$ref": "#/definitions/entity.GeocodingPrecision"
+                },
+                "handling": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "import_id": {
+                    "description": "ImportId identifies the import job of imported orders.",
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Items are the order lines. The subtotal of an order with items\nis the sum of their amounts and its tax the sum of their taxes.\nItems are only loaded for a single order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderItem"
+                    }
+                },
+                "jurisdictions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "metadata": {
+                    "description": "Metadata and Tags are free-form labels of the order, such as its\nsales channel or campaign, kept for reconciliation. They take no part\nin the tax calculation.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "net_tax_amount": {
+                    "type": "number"
+                },
+                "net_total_amount": {
+                    "type": "number"
+                },
+                "original": {
+                    "$ref": "#/definitions/entity.OrderAmounts"
+                },
+                "refunded_amount": {
+                    "description": "RefundedAmount and RefundedTaxAmount sum the pre-tax amount and the\ntax of all refunds. NetTotalAmount and NetTaxAmount are the total\nand the tax left on the order after them.",
+                    "type": "number"
+                },
+                "refunded_tax_amount": {
+                    "type": "number"
+                },
+                "refunds": {
+                    "description": "Refunds are the refunds of the order, only loaded for a single order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderRefund"
+                    }
+                },
+                "reporting_code": {
+                    "type": "string"
+                },
+                "shipping": {
+                    "type": "number"
+                },
+                "state": {
+                    "description": "State is the code of the state the order was taxed in.\nIt is empty for orders outside of every configured state.",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                },
+                "status_transitions": {
+                    "description": "StatusTransitions records every change of the status,\nstarting from pending when the order was created.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.StatusTransition"
+                    }
+                },
+                "subtotal": {
+                    "description": "Subtotal is the merchandise amount. Shipping and handling are\ncharged on top of it and Discount is taken off it.",
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "tax_inclusive": {
+                    "description": "TaxInclusive tells the order was reported with gross amounts.\nIts net amounts and tax are backed out of them and rounded to cents,\nso they are stored like those of tax-exclusive orders.",
+                    "type": "boolean"
+                },
+                "tax_override": {
+                    "description": "TaxOverride is the name of the temporary override,\ne.g. a sales tax holiday, applied to the order, if any.",
+                    "type": "string"
+                },
+                "total_amount": {
+                    "description": "TotalAmount is the grand total: the subtotal with shipping and\nhandling, less the discount, plus TaxAmount, the tax of all components.",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is incremented by every change of the order\nand guards updates against concurrent ones.",
+                    "type": "integer"
+                },
+                "warning": {
+                    "description": "Warning is set when the order location matched several overlapping\njurisdictions, listed in AmbiguousJurisdictions.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.OrderWarning"
+                        }
+                    ]
+                },
+                "zip": {
+                    "description": "Zip is the ZIP code of the order address, if given.\nGeocodingMethod and GeocodingPrecision describe how the location\nwas obtained: from the given coordinates or from the ZIP code.",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OrderAmounts": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "handling": {
+                    "type": "number"
+                },
+                "shipping": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.OrderComponent": {
+            "type": "string",
+            "enum": [
+                "subtotal",
+                "shipping",
+                "handling",
+                "discount"
+            ],
+            "x-enum-varnames": [
+                "OrderComponentSubtotal",
+                "OrderComponentShipping",
+                "OrderComponentHandling",
+                "OrderComponentDiscount"
+            ]
+        },
+        "entity.OrderComponentTax": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "component": {
+                    "$ref": "#/definitions/entity.OrderComponent"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "taxable_amount": {
+                    "description": "TaxableAmount is the part of the amount tax is charged on:\nthe whole amount, or zero when the component is not taxed.",
+                    "type": "number"
+                }
+            }
+        },
+        "entity.OrderDeletion": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "purge_at": {
+                    "description": "PurgeAt is when the deleted orders are removed for good\nunless they are restored before.",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OrderEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/entity.Actor"
+                },
+                "after": {
+                    "$ref": "#/definitions/entity.Order"
+                },
+                "before": {
+                    "description": "Before is nil for created orders. Deletions and restorations\ndo not change the order and keep no snapshots.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Order"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "SourceId identifies the import job of imported orders.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.OrderEventSource"
+                        }
+                    ]
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.OrderEventType"
+                }
+            }
+        },
+        "entity.OrderEventSource": {
+            "type": "string",
+            "enum": [
+                "api",
+                "import",
+                "recalculation"
+            ],
+            "x-enum-varnames": [
+                "OrderEventSourceApi",
+                "OrderEventSourceImport",
+                "OrderEventSourceRecalculation"
+            ]
+        },
+        "entity.OrderEventType": {
+            "type": "string",
+            "enum": [
+                "created",
+                "imported",
+                "updated",
+                "voided",
+                "refunded",
+                "deleted",
+                "restored",
+                "recalculated",
+                "resolved"
+            ],
+            "x-enum-varnames": [
+                "OrderEventCreated",
+                "OrderEventImported",
+                "OrderEventUpdated",
+                "OrderEventVoided",
+                "OrderEventRefunded",
+                "OrderEventDeleted",
+                "OrderEventRestored",
+                "OrderEventRecalculated",
+                "OrderEventResolved"
+            ]
+        },
+        "entity.OrderItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "description": "Discount is taken off the line, capped at its gross amount.\nAmount is the quantity times the unit price less the discount.\nFor tax-inclusive orders the unit price and discount are gross\nand Amount is the net amount left after backing out the tax.",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "tax_override": {
+                    "description": "TaxOverride is the name of the override applied to the item, if any.",
+                    "type": "string"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.OrderList": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Order"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.OrderRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is the pre-tax amount returned\nand TaxAmount the tax reversed with it.",
                     "type": "number"
                 },
-                "longitude": {
-                    "type": "number"
+                "created_at": {
+                    "type": "string"
                 },
-                "order": {
+                "id": {
                     "type": "integer"
                 },
-                "reporting_code": {
-                    "type": "string"
+                "lines": {
+                    "description": "Lines lists how the amount and the tax are allocated.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderRefundLine"
+                    }
                 },
-                "status": {
-                    "$ref": "#/definitions/entity.OrderStatus"
+                "order_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
                 },
                 "tax_amount": {
                     "type": "number"
+                }
+            }
+        },
+        "entity.OrderRefundLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
                 },
-                "total_amount": {
+                "component": {
+                    "$ref": "#/definitions/entity.OrderComponent"
+                },
+                "line": {
+                    "description": "Line is the item line the amount is refunded from,\nzero for components other than itemized subtotals.",
+                    "type": "integer"
+                },
+                "tax_amount": {
                     "type": "number"
                 },
-                "updated_at": {
-                    "type": "string"
+                "tax_rate": {
+                    "type": "number"
                 }
             }
         },
-        "entity.OrderList": {
+        "entity.OrderRestoration": {
             "type": "object",
             "properties": {
-                "orders": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.Order"
-                    }
-                },
-                "total": {
+                "restored": {
                     "type": "integer"
                 }
             }
@@ -397,14 +3635,84 @@ const docTemplate = `{
         "entity.OrderStatus": {
             "type": "string",
             "enum": [
+                "pending",
                 "completed",
-                "out_of_scope"
+                "out_of_scope",
+                "failed_tax_resolution",
+                "partially_refunded",
+                "refunded",
+                "voided"
             ],
             "x-enum-varnames": [
+                "OrderStatusPending",
                 "OrderStatusCompleted",
-                "OrderStatusOutOfScope"
+                "OrderStatusOutOfScope",
+                "OrderStatusFailedTaxResolution",
+                "OrderStatusPartiallyRefunded",
+                "OrderStatusRefunded",
+                "OrderStatusVoided"
+            ]
+        },
+        "entity.OrderTaxChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "$ref": "#/definitions/entity.OrderTaxFigures"
+                },
+                "before": {
+                    "$ref": "#/definitions/entity.OrderTaxFigures"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.OrderTaxFigures": {
+            "type": "object",
+            "properties": {
+                "composite_tax_rate": {
+                    "type": "number"
+                },
+                "reporting_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.OrderWarning": {
+            "type": "string",
+            "enum": [
+                "ambiguous_jurisdiction"
+            ],
+            "x-enum-varnames": [
+                "OrderWarningAmbiguousJurisdiction"
             ]
         },
+        "entity.OutOfScopeResolution": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "conflicts": {
+                    "type": "integer"
+                },
+                "resolved": {
+                    "type": "integer"
+                }
+            }
+        },
         "entity.ResponseCode": {
             "type": "integer",
             "enum": [
@@ -414,7 +3722,8 @@ const docTemplate = `{
                 1003,
                 1004,
                 1005,
-                1006
+                1006,
+                1007
             ],
             "x-enum-varnames": [
                 "SuccessCode",
@@ -423,7 +3732,176 @@ const docTemplate = `{
                 "FileIsToLarge",
                 "ForbiddenCode",
                 "NotFoundCode",
-                "InternalErrorCode"
+                "InternalErrorCode",
+                "ConflictCode"
+            ]
+        },
+        "entity.StatusTransition": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "from": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                },
+                "to": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                }
+            }
+        },
+        "entity.TaxExplanation": {
+            "type": "object",
+            "properties": {
+                "ambiguous": {
+                    "description": "Ambiguous is set when several overlapping features contain the point.",
+                    "type": "boolean"
+                },
+                "boundary_resolved": {
+                    "type": "boolean"
+                },
+                "candidates": {
+                    "description": "Candidates are the features whose bounding box contains the point,\nordered by feature index.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeatureMatch"
+                    }
+                },
+                "components": {
+                    "description": "Components is the tax of every order component.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderComponentTax"
+                    }
+                },
+                "composite_rate": {
+                    "type": "number"
+                },
+                "geocode": {
+                    "description": "Geocode describes how the location was obtained.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Geocode"
+                        }
+                    ]
+                },
+                "jurisdiction": {
+                    "description": "Jurisdiction is the tax config entry matched by the winner name.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.JurisdictionTax"
+                        }
+                    ]
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "layers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LayerMatch"
+                    }
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "override": {
+                    "$ref": "#/definitions/entity.TaxOverride"
+                },
+                "state": {
+                    "description": "State is the code of the state dataset the winner belongs to.",
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ComputationStep"
+                    }
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "winner": {
+                    "description": "Winner is the containing feature picked by the ambiguity policy,\nthe owner of a shared edge the point lies on, or the nearest feature\nthe point was snapped to.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.FeatureMatch"
+                        }
+                    ]
+                }
+            }
+        },
+        "entity.TaxLayerLevel": {
+            "type": "string",
+            "enum": [
+                "state",
+                "county",
+                "city",
+                "special"
+            ],
+            "x-enum-varnames": [
+                "TaxLayerLevelState",
+                "TaxLayerLevelCounty",
+                "TaxLayerLevelCity",
+                "TaxLayerLevelSpecial"
+            ]
+        },
+        "entity.TaxOverride": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TaxLayerLevel"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jurisdictions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "source": {
+                    "$ref": "#/definitions/entity.TaxOverrideSource"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TaxOverrideSource": {
+            "type": "string",
+            "enum": [
+                "config",
+                "api"
+            ],
+            "x-enum-varnames": [
+                "TaxOverrideSourceConfig",
+                "TaxOverrideSourceApi"
             ]
         },
         "entity.TaxRateBreakdown": {
@@ -443,6 +3921,128 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.TaxRecalculation": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "Applied counts the changes applied so far and Conflicts the ones\nleft out because the order was changed after the preview.",
+                    "type": "integer"
+                },
+                "applied_at": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderTaxChange"
+                    }
+                },
+                "conflicts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filter": {
+                    "$ref": "#/definitions/entity.TaxRecalculationFilter"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rates_version": {
+                    "description": "RatesVersion identifies the tax data, including the active boundaries\nand overrides, the preview was computed with. The recalculation can\nonly be applied with the same data loaded.",
+                    "type": "string"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.TaxRecalculationStatus"
+                },
+                "unchanged": {
+                    "description": "Unchanged counts the matched orders whose tax stays the same.\nSkipped counts the matched orders that can no longer be changed,\ne.g. voided or refunded ones.",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TaxRecalculationFilter": {
+            "type": "object",
+            "properties": {
+                "from_date": {
+                    "type": "string"
+                },
+                "reporting_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                },
+                "to_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TaxRecalculationStatus": {
+            "type": "string",
+            "enum": [
+                "previewed",
+                "applying",
+                "applied",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "TaxRecalculationStatusPreviewed",
+                "TaxRecalculationStatusApplying",
+                "TaxRecalculationStatusApplied",
+                "TaxRecalculationStatusFailed"
+            ]
+        },
+        "entity.TaxTreatment": {
+            "type": "string",
+            "enum": [
+                "taxable",
+                "exempt",
+                "reduced",
+                "state_only",
+                "local_only"
+            ],
+            "x-enum-varnames": [
+                "TaxTreatmentTaxable",
+                "TaxTreatmentExempt",
+                "TaxTreatmentReduced",
+                "TaxTreatmentStateOnly",
+                "TaxTreatmentLocalOnly"
+            ]
+        },
+        "entity.TaxabilityRule": {
+            "type": "object",
+            "properties": {
+                "below_threshold": {
+                    "$ref": "#/definitions/entity.TaxTreatment"
+                },
+                "factor": {
+                    "description": "Factor scales every component for the reduced treatment.",
+                    "type": "number"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "treatment": {
+                    "$ref": "#/definitions/entity.TaxTreatment"
+                }
+            }
+        },
+        "entity.WipeConfirmation": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "response.Metadata": {
             "type": "object",
             "properties": {
@@ -1,7 +1,13 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"maps"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
@@ -15,6 +21,11 @@ import (
 const (
 	jurisdictionsFilePath = "jurisdictions.json"
 	geoJsonFilePath       = "counties.geojson"
+	defaultPrimaryState   = "NY"
+
+	defaultMaxBoundaryFileSize = 100 << 20
+
+	defaultDeletedOrdersRetention = 30 * 24 * time.Hour
 )
 
 type Config struct {
@@ -33,15 +44,105 @@ type Config struct {
 	OrdersBatchSize             int           `env:"ORDERS_BATCH_SIZE,required"`
 	MaxFileSize                 int           `env:"MAX_FILE_SIZE,required"`
 	ApiKey                      string        `env:"API_KEY,required"`
-	JurisdictionsFilePath       string        `env:"JURISDICTIONS_FILE_PATH"`
-	GeoJSONFilePath             string        `env:"GEOJSON_FILE_PATH"`
+	AdminApiKey                 string        `env:"ADMIN_API_KEY"`
+	MaxBoundaryFileSize         int           `env:"MAX_BOUNDARY_FILE_SIZE"`
+
+	// DeletedOrdersRetention is how long deleted orders
+	// can be restored before they are purged.
+	DeletedOrdersRetention time.Duration `env:"DELETED_ORDERS_RETENTION"`
+
+	// ExchangeRatesFilePath points to an optional CSV file of exchange rates,
+	// "date,currency,rate", with the value in USD of one unit of the currency.
+	// Rates set through the admin API take precedence over them.
+	ExchangeRatesFilePath string `env:"EXCHANGE_RATES_FILE_PATH"`
+	ExchangeRates         []entity.ExchangeRate
+
+	TaxDataConfig
+}
+
+// TaxDataConfig holds the static tax data loaded from files:
+// the jurisdiction rate table and the boundary GeoJSON.
+// It can be loaded on its own, without database or server settings,
+// e.g. to validate the files from the command line.
+type TaxDataConfig struct {
+	JurisdictionsFilePath string `env:"JURISDICTIONS_FILE_PATH"`
+	GeoJSONFilePath       string `env:"GEOJSON_FILE_PATH"`
+
+	// PrimaryState is the code of the state served by the dataset loaded
+	// from JurisdictionsFilePath and GeoJSONFilePath.
+	PrimaryState string `env:"PRIMARY_STATE"`
+
+	// StateOutlineFilePath points to an optional GeoJSON file with the
+	// boundary of the primary state, against which gaps between boundary
+	// features are reported.
+	StateOutlineFilePath string `env:"STATE_OUTLINE_FILE_PATH"`
+
+	// StatesFilePath points to an optional file listing the datasets
+	// of additional states loaded side by side with the primary one.
+	StatesFilePath string `env:"STATES_FILE_PATH"`
+
+	// TaxLayersFilePath points to an optional file describing additional
+	// boundary layers (cities, special districts) and their rates.
+	TaxLayersFilePath string `env:"TAX_LAYERS_FILE_PATH"`
+
+	// BoundaryToleranceMeters is the maximum distance of an order location
+	// outside of every jurisdiction that is still snapped to the nearest one.
+	BoundaryToleranceMeters float64 `env:"BOUNDARY_TOLERANCE_METERS"`
+
+	// AmbiguityPolicy decides which jurisdiction wins for a location inside
+	// several overlapping jurisdictions: first_wins, highest_rate or fail.
+	AmbiguityPolicy entity.AmbiguityPolicy `env:"AMBIGUITY_POLICY"`
+
+	// TaxLookupCacheSize is the number of location lookups kept in an LRU
+	// cache keyed by coordinates rounded to TaxLookupCachePrecision decimal
+	// places. Zero disables the cache. The precision defaults to 6 and
+	// must be between 1 and 9. Lookups whose rounding cell is crossed by
+	// a boundary and snapped lookups are never cached.
+	TaxLookupCacheSize      int `env:"TAX_LOOKUP_CACHE_SIZE"`
+	TaxLookupCachePrecision int `env:"TAX_LOOKUP_CACHE_PRECISION" envDefault:"6"`
+
+	// ZipCentroidsFilePath and ZipJurisdictionsFilePath point to optional
+	// CSV datasets used to geocode orders given by ZIP code or address:
+	// "zip,latitude,longitude" and "zip,jurisdiction" respectively.
+	ZipCentroidsFilePath     string `env:"ZIP_CENTROIDS_FILE_PATH"`
+	ZipJurisdictionsFilePath string `env:"ZIP_JURISDICTIONS_FILE_PATH"`
+
+	// StrictTaxDataValidation makes startup fail when the boundary
+	// features and the tax config are not fully consistent.
+	StrictTaxDataValidation bool `env:"STRICT_TAX_DATA_VALIDATION"`
+
+	TaxConfig    *JurisdictionTaxConfig
+	GeoJSON      *entity.GeoJSON
+	StateOutline *entity.GeoJSON
+	TaxLayers    *TaxLayersConfig
+	States       []entity.StateDataset
 
-	TaxConfig *JurisdictionTaxConfig
-	GeoJSON   *entity.GeoJSON
+	ZipCentroids     []entity.ZipCentroid
+	ZipJurisdictions []entity.ZipJurisdiction
 }
 
 type JurisdictionTaxConfig struct {
 	Jurisdictions map[string]entity.JurisdictionTax `json:"jurisdictions"`
+
+	// Taxability holds default category rules applied to every jurisdiction
+	// unless the jurisdiction defines its own rule for the category.
+	Taxability map[string]entity.TaxabilityRule `json:"taxability"`
+
+	// ComponentTaxability holds default rules for shipping, handling and
+	// discounts applied to every jurisdiction unless it defines its own.
+	ComponentTaxability map[entity.OrderComponent]entity.TaxabilityRule `json:"component_taxability"`
+
+	// Overrides are time-bounded rate changes such as sales tax holidays.
+	// Overrides created through the admin API take precedence over them.
+	Overrides []entity.TaxOverride `json:"overrides"`
+}
+
+type TaxLayersConfig struct {
+	Layers []entity.TaxLayer `json:"layers"`
+}
+
+type StatesConfig struct {
+	States []entity.StateDataset `json:"states"`
 }
 
 func MustCreateConfig() *Config {
@@ -81,6 +182,58 @@ func MustCreateConfig() *Config {
 	if cfg.PostgresMaxConns < cfg.PostgresMinConns {
 		log.Fatal().Msg("POSTGRES_MAX_CONNS must be greater than or equal to POSTGRES_MIN_CONNS")
 	}
+	if cfg.MaxBoundaryFileSize < 0 {
+		log.Fatal().Msg("MAX_BOUNDARY_FILE_SIZE cannot be negative")
+	}
+	if cfg.MaxBoundaryFileSize == 0 {
+		cfg.MaxBoundaryFileSize = defaultMaxBoundaryFileSize
+	}
+	if cfg.DeletedOrdersRetention < 0 {
+		log.Fatal().Msg("DELETED_ORDERS_RETENTION cannot be negative")
+	}
+	if cfg.DeletedOrdersRetention == 0 {
+		cfg.DeletedOrdersRetention = defaultDeletedOrdersRetention
+	}
+	if cfg.AdminApiKey != "" && cfg.AdminApiKey == cfg.ApiKey {
+		log.Fatal().Msg("ADMIN_API_KEY must differ from API_KEY")
+	}
+	cfg.mustLoadExchangeRates()
+	cfg.TaxDataConfig.mustLoadFiles()
+
+	return &cfg
+}
+
+// MustCreateTaxDataConfig loads only the tax data files.
+func MustCreateTaxDataConfig() *TaxDataConfig {
+	godotenv.Load()
+	var cfg TaxDataConfig
+	if err := env.Parse(&cfg); err != nil {
+		log.Fatal().Err(err).Msg("failed to load config")
+	}
+
+	cfg.mustLoadFiles()
+
+	return &cfg
+}
+
+func (cfg *TaxDataConfig) mustLoadFiles() {
+	if cfg.BoundaryToleranceMeters < 0 {
+		log.Fatal().Msg("BOUNDARY_TOLERANCE_METERS cannot be negative")
+	}
+	if cfg.TaxLookupCacheSize < 0 {
+		log.Fatal().Msg("TAX_LOOKUP_CACHE_SIZE cannot be negative")
+	}
+	if cfg.TaxLookupCachePrecision < 1 || cfg.TaxLookupCachePrecision > 9 {
+		log.Fatal().Msg("TAX_LOOKUP_CACHE_PRECISION must be between 1 and 9")
+	}
+
+	if cfg.AmbiguityPolicy == "" {
+		cfg.AmbiguityPolicy = entity.AmbiguityPolicyFirstWins
+	}
+	if !cfg.AmbiguityPolicy.Valid() {
+		log.Fatal().Str("policy", string(cfg.AmbiguityPolicy)).Msg("AMBIGUITY_POLICY must be first_wins, highest_rate or fail")
+	}
+
 	if cfg.JurisdictionsFilePath == "" {
 		cfg.JurisdictionsFilePath = jurisdictionsFilePath
 	}
@@ -98,6 +251,9 @@ func MustCreateConfig() *Config {
 		log.Fatal().Err(err).Msg("failed to unmarshal jurisdiction json file")
 	}
 
+	cfg.TaxConfig.mustApplyTaxability()
+	cfg.TaxConfig.mustValidateOverrides()
+
 	geoJsonBytes, err := os.ReadFile(cfg.GeoJSONFilePath)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to read geojson file")
@@ -108,5 +264,298 @@ func MustCreateConfig() *Config {
 		log.Fatal().Err(err).Msg("failed to unmarshal geojson file")
 	}
 
-	return &cfg
+	if cfg.StateOutlineFilePath != "" {
+		mustReadJSON(cfg.StateOutlineFilePath, &cfg.StateOutline)
+	}
+
+	cfg.TaxLayers = mustLoadTaxLayers(cfg.TaxLayersFilePath)
+	cfg.mustLoadStates()
+	cfg.mustLoadZipFiles()
+}
+
+// mustLoadTaxLayers loads the optional tax layers file and the boundaries
+// of every layer. An empty path means no layers.
+func mustLoadTaxLayers(path string) *TaxLayersConfig {
+	layers := &TaxLayersConfig{}
+	if path == "" {
+		return layers
+	}
+
+	layersBytes, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to read tax layers file")
+	}
+
+	err = json.Unmarshal(layersBytes, layers)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to unmarshal tax layers file")
+	}
+
+	for i := range layers.Layers {
+		l := &layers.Layers[i]
+		if !l.Level.Valid() {
+			log.Fatal().Str("layer", l.Name).Str("level", string(l.Level)).Msg("unknown tax layer level")
+		}
+
+		layerBytes, err := os.ReadFile(l.GeoJSONFilePath)
+		if err != nil {
+			log.Fatal().Err(err).Str("layer", l.Name).Msg("failed to read tax layer geojson file")
+		}
+
+		err = json.Unmarshal(layerBytes, &l.GeoJSON)
+		if err != nil {
+			log.Fatal().Err(err).Str("layer", l.Name).Msg("failed to unmarshal tax layer geojson file")
+		}
+	}
+
+	return layers
+}
+
+// mustLoadStates loads the datasets of additional states together with
+// their tax layers. Their overrides are added to the primary ones keyed by
+// the state, since reporting codes are only unique within a state.
+func (cfg *TaxDataConfig) mustLoadStates() {
+	if cfg.PrimaryState == "" {
+		cfg.PrimaryState = defaultPrimaryState
+	}
+	if cfg.StatesFilePath == "" {
+		return
+	}
+
+	var states StatesConfig
+	mustReadJSON(cfg.StatesFilePath, &states)
+
+	seen := map[string]struct{}{cfg.PrimaryState: {}}
+	for i := range states.States {
+		s := &states.States[i]
+		if s.Code == "" {
+			log.Fatal().Int("index", i).Msg("state dataset without code")
+		}
+		if _, ok := seen[s.Code]; ok {
+			log.Fatal().Str("state", s.Code).Msg("duplicate state dataset")
+		}
+		seen[s.Code] = struct{}{}
+
+		mustReadJSON(s.GeoJSONFilePath, &s.GeoJSON)
+		if s.OutlineFilePath != "" {
+			mustReadJSON(s.OutlineFilePath, &s.Outline)
+		}
+
+		var taxConfig JurisdictionTaxConfig
+		mustReadJSON(s.JurisdictionsFilePath, &taxConfig)
+		taxConfig.mustApplyTaxability()
+		taxConfig.mustValidateOverrides()
+
+		s.Jurisdictions = taxConfig.Jurisdictions
+		s.TaxLayers = mustLoadTaxLayers(s.TaxLayersFilePath).Layers
+		for _, o := range taxConfig.Overrides {
+			o.State = s.Code
+			cfg.TaxConfig.Overrides = append(cfg.TaxConfig.Overrides, o)
+		}
+	}
+
+	cfg.States = states.States
+}
+
+// jurisdictions returns the rate table of the state.
+// An empty code means the primary state.
+func (cfg *TaxDataConfig) jurisdictions(state string) (map[string]entity.JurisdictionTax, bool) {
+	if state == "" || state == cfg.PrimaryState {
+		return cfg.TaxConfig.Jurisdictions, true
+	}
+	for _, s := range cfg.States {
+		if s.Code == state {
+			return s.Jurisdictions, true
+		}
+	}
+	return nil, false
+}
+
+// RatesVersion fingerprints the loaded rate tables, taxability rules,
+// overrides and layer rates, so that taxes computed with different
+// tax data can be told apart. Boundaries are not part of it.
+func (cfg *TaxDataConfig) RatesVersion() string {
+	type stateRates struct {
+		Jurisdictions map[string]entity.JurisdictionTax
+		TaxLayers     []entity.TaxLayer
+	}
+	states := make(map[string]stateRates, len(cfg.States))
+	for _, s := range cfg.States {
+		states[s.Code] = stateRates{s.Jurisdictions, s.TaxLayers}
+	}
+
+	b, err := json.Marshal(struct {
+		TaxConfig *JurisdictionTaxConfig
+		TaxLayers *TaxLayersConfig
+		States    map[string]stateRates
+	}{cfg.TaxConfig, cfg.TaxLayers, states})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to marshal tax rates")
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// mustReadJSON reads and unmarshals a JSON file into v.
+func mustReadJSON(path string, v any) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to read json file")
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to unmarshal json file")
+	}
+}
+
+// mustLoadZipFiles loads the optional ZIP geocoding datasets.
+// Every jurisdiction of the ZIP-to-jurisdiction mapping must exist
+// in the rate table of its state, given by an optional third column.
+func (cfg *TaxDataConfig) mustLoadZipFiles() {
+	if cfg.ZipCentroidsFilePath != "" {
+		for _, rec := range mustReadCSV(cfg.ZipCentroidsFilePath, 3) {
+			lat, latErr := strconv.ParseFloat(strings.TrimSpace(rec[1]), 64)
+			lon, lonErr := strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+			if latErr != nil || lonErr != nil {
+				log.Fatal().Strs("record", rec).Msg("invalid zip centroid coordinates")
+			}
+			cfg.ZipCentroids = append(cfg.ZipCentroids, entity.ZipCentroid{Zip: strings.TrimSpace(rec[0]), Latitude: lat, Longitude: lon})
+		}
+	}
+
+	if cfg.ZipJurisdictionsFilePath != "" {
+		for _, rec := range mustReadCSV(cfg.ZipJurisdictionsFilePath, 2) {
+			var state string
+			if len(rec) > 2 {
+				state = strings.TrimSpace(rec[2])
+			}
+
+			jurisdictions, ok := cfg.jurisdictions(state)
+			if !ok {
+				log.Fatal().Strs("record", rec).Msg("zip mapped to unknown state")
+			}
+
+			jurisdiction := strings.TrimSpace(rec[1])
+			if _, ok := jurisdictions[jurisdiction]; !ok {
+				log.Fatal().Strs("record", rec).Msg("zip mapped to unknown jurisdiction")
+			}
+			cfg.ZipJurisdictions = append(cfg.ZipJurisdictions, entity.ZipJurisdiction{Zip: strings.TrimSpace(rec[0]), State: state, Jurisdiction: jurisdiction})
+		}
+	}
+}
+
+// mustLoadExchangeRates loads the optional exchange rate file.
+func (cfg *Config) mustLoadExchangeRates() {
+	if cfg.ExchangeRatesFilePath == "" {
+		return
+	}
+
+	for _, rec := range mustReadCSV(cfg.ExchangeRatesFilePath, 3) {
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(rec[0]))
+		if err != nil {
+			log.Fatal().Err(err).Strs("record", rec).Msg("invalid exchange rate date")
+		}
+
+		currency, ok := entity.NormalizeCurrency(rec[1])
+		if !ok || currency == entity.ReportingCurrency {
+			log.Fatal().Strs("record", rec).Msg("invalid exchange rate currency")
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+		if err != nil || rate <= 0 {
+			log.Fatal().Strs("record", rec).Msg("invalid exchange rate")
+		}
+
+		cfg.ExchangeRates = append(cfg.ExchangeRates, entity.ExchangeRate{
+			Currency: currency,
+			Date:     date,
+			Rate:     rate,
+			Source:   entity.ExchangeRateSourceFile,
+		})
+	}
+}
+
+// mustReadCSV reads all records of a CSV file with a header row,
+// each having at least the given number of columns.
+func mustReadCSV(path string, columns int) [][]string {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to open csv file")
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to read csv file")
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	for _, rec := range records[1:] {
+		if len(rec) < columns {
+			log.Fatal().Str("path", path).Strs("record", rec).Msg("invalid csv column count")
+		}
+	}
+	return records[1:]
+}
+
+// mustApplyTaxability validates category and component rules
+// and merges the default rules into every jurisdiction.
+func (c *JurisdictionTaxConfig) mustApplyTaxability() {
+	for category, rule := range c.Taxability {
+		if !rule.Validate() {
+			log.Fatal().Str("category", category).Msg("invalid default taxability rule")
+		}
+	}
+	for component, rule := range c.ComponentTaxability {
+		if !component.Valid() || !rule.Validate() {
+			log.Fatal().Str("component", string(component)).Msg("invalid default component taxability rule")
+		}
+	}
+
+	for name, jurisdiction := range c.Jurisdictions {
+		for category, rule := range jurisdiction.Taxability {
+			if !rule.Validate() {
+				log.Fatal().Str("jurisdiction", name).Str("category", category).Msg("invalid taxability rule")
+			}
+		}
+		for component, rule := range jurisdiction.ComponentTaxability {
+			if !component.Valid() || !rule.Validate() {
+				log.Fatal().Str("jurisdiction", name).Str("component", string(component)).Msg("invalid component taxability rule")
+			}
+		}
+
+		jurisdiction.Taxability = mergeRules(c.Taxability, jurisdiction.Taxability)
+		jurisdiction.ComponentTaxability = mergeRules(c.ComponentTaxability, jurisdiction.ComponentTaxability)
+		c.Jurisdictions[name] = jurisdiction
+	}
+}
+
+// mergeRules returns the jurisdiction rules on top of the default ones.
+func mergeRules[K comparable](defaults, rules map[K]entity.TaxabilityRule) map[K]entity.TaxabilityRule {
+	if len(defaults) == 0 {
+		return rules
+	}
+
+	merged := make(map[K]entity.TaxabilityRule, len(defaults)+len(rules))
+	maps.Copy(merged, defaults)
+	maps.Copy(merged, rules)
+	return merged
+}
+
+// mustValidateOverrides validates the config overrides
+// and marks them as coming from the config.
+func (c *JurisdictionTaxConfig) mustValidateOverrides() {
+	for i := range c.Overrides {
+		o := &c.Overrides[i]
+		if !o.Validate() {
+			log.Fatal().Str("override", o.Name).Msg("invalid tax override")
+		}
+		o.Source = entity.TaxOverrideSourceConfig
+	}
 }
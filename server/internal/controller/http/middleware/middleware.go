@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/labstack/echo/v4"
 	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
 	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
@@ -9,21 +11,30 @@ import (
 // Middleware aggregates reusable HTTP middleware logic.
 // It holds configuration values shared across middleware handlers.
 type Middleware struct {
-	apiKey string
+	apiKey      string
+	adminApiKey string
 }
 
-const apiKeyHeader = "x-api-key"
+const (
+	apiKeyHeader = "x-api-key"
+
+	// userHeader optionally names the user a caller acts for.
+	userHeader    = "x-user"
+	maxUserLength = 128
+)
 
-func NewMiddleware(apiKey string) *Middleware {
+func NewMiddleware(apiKey, adminApiKey string) *Middleware {
 	return &Middleware{
-		apiKey: apiKey,
+		apiKey:      apiKey,
+		adminApiKey: adminApiKey,
 	}
 }
 
 // WithApiKey returns an Echo middleware function
 // that validates the presence and correctness of the API key.
 // If the key is missing or invalid, it returns an unauthorized response.
-// Otherwise, the request is forwarded to the next handler.
+// Otherwise, the request is forwarded to the next handler
+// with the actor of the request in its context.
 func (m *Middleware) WithApiKey() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -37,7 +48,45 @@ func (m *Middleware) WithApiKey() echo.MiddlewareFunc {
 				return response.NewErrorResponse(c, entity.ErrUnauthorizedAccessToProvidedData)
 			}
 
-			return next(c)
+			return next(withActor(c, key))
+		}
+	}
+}
+
+// WithAdminApiKey returns an Echo middleware function
+// that restricts access to administrative endpoints.
+// The request must carry the admin API key; if no admin key
+// is configured, administrative endpoints are not accessible at all.
+func (m *Middleware) WithAdminApiKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(apiKeyHeader)
+
+			if key == "" {
+				return response.NewErrorResponse(c, entity.ErrMissingAPIKey)
+			}
+
+			if m.adminApiKey == "" || key != m.adminApiKey {
+				return response.NewErrorResponse(c, entity.ErrUnauthorizedAccessToProvidedData)
+			}
+
+			return next(withActor(c, key))
 		}
 	}
 }
+
+// withActor stores the actor of the request, identified by the fingerprint
+// of its API key and the user it names, in the request context.
+// Invalid UTF-8 is dropped from the user name and overlong names are cut
+// to maxUserLength characters, so the name always fits its column.
+func withActor(c echo.Context, key string) echo.Context {
+	actor := entity.Actor{
+		Key:  entity.KeyFingerprint(key),
+		User: strings.ToValidUTF8(c.Request().Header.Get(userHeader), ""),
+	}
+	if user := []rune(actor.User); len(user) > maxUserLength {
+		actor.User = string(user[:maxUserLength])
+	}
+	c.SetRequest(c.Request().WithContext(entity.ContextWithActor(c.Request().Context(), actor)))
+	return c
+}
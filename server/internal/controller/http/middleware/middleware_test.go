@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+func TestWithActor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		user     string
+		wantUser string
+	}{
+		{name: "no_user", user: "", wantUser: ""},
+		{name: "short", user: "jane", wantUser: "jane"},
+		{name: "long_ascii", user: strings.Repeat("a", 200), wantUser: strings.Repeat("a", maxUserLength)},
+		{name: "long_multibyte", user: "a" + strings.Repeat("é", 200), wantUser: "a" + strings.Repeat("é", maxUserLength-1)},
+		{name: "invalid_utf8", user: "ja\xffne", wantUser: "jane"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(userHeader, tc.user)
+			ctx := withActor(echo.New().NewContext(req, httptest.NewRecorder()), "key")
+
+			actor := entity.ActorFromContext(ctx.Request().Context())
+			if actor.User != tc.wantUser || !utf8.ValidString(actor.User) {
+				t.Errorf("withActor() user = %q, want %q", actor.User, tc.wantUser)
+			}
+			if actor.Key != entity.KeyFingerprint("key") {
+				t.Errorf("withActor() key = %q", actor.Key)
+			}
+		})
+	}
+}
@@ -17,6 +17,26 @@ var errorToMetadata = map[error]Metadata{
 	entity.ErrInvalidFileFormat:                   NewMetadata(entity.BadRequestCode, http.StatusBadRequest, entity.ErrInvalidFileFormat.Error()),
 	entity.ErrInvalidOrEmptyPaginationQueryParams: NewMetadata(entity.BadRequestCode, http.StatusBadRequest, entity.ErrInvalidOrEmptyPaginationQueryParams.Error()),
 	entity.ErrOrderNotFound:                       NewMetadata(entity.NotFoundCode, http.StatusNotFound, entity.ErrOrderNotFound.Error()),
+	entity.ErrRefundExceedsOrder:                  NewMetadata(entity.BadRequestCode, http.StatusUnprocessableEntity, entity.ErrRefundExceedsOrder.Error()),
+	entity.ErrRefundLineNotFound:                  NewMetadata(entity.BadRequestCode, http.StatusUnprocessableEntity, entity.ErrRefundLineNotFound.Error()),
+	entity.ErrOrderNotRefundable:                  NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrOrderNotRefundable.Error()),
+	entity.ErrOrderVersionConflict:                NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrOrderVersionConflict.Error()),
+	entity.ErrOrderNotEditable:                    NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrOrderNotEditable.Error()),
+	entity.ErrInvalidStatusTransition:             NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrInvalidStatusTransition.Error()),
+	entity.ErrDeletionScopeRequired:               NewMetadata(entity.BadRequestCode, http.StatusBadRequest, entity.ErrDeletionScopeRequired.Error()),
+	entity.ErrInvalidConfirmationToken:            NewMetadata(entity.ForbiddenCode, http.StatusForbidden, entity.ErrInvalidConfirmationToken.Error()),
+	entity.ErrTaxRecalculationNotFound:            NewMetadata(entity.NotFoundCode, http.StatusNotFound, entity.ErrTaxRecalculationNotFound.Error()),
+	entity.ErrTaxRecalculationNotPending:          NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrTaxRecalculationNotPending.Error()),
+	entity.ErrRatesVersionMismatch:                NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrRatesVersionMismatch.Error()),
+	entity.ErrExchangeRateNotFound:                NewMetadata(entity.BadRequestCode, http.StatusUnprocessableEntity, entity.ErrExchangeRateNotFound.Error()),
+	entity.ErrBoundarySetNotFound:                 NewMetadata(entity.NotFoundCode, http.StatusNotFound, entity.ErrBoundarySetNotFound.Error()),
+	entity.ErrInvalidBoundarySet:                  NewMetadata(entity.BadRequestCode, http.StatusUnprocessableEntity, entity.ErrInvalidBoundarySet.Error()),
+	entity.ErrExemptionCertificateAlreadyExists:   NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrExemptionCertificateAlreadyExists.Error()),
+	entity.ErrCustomerNotFound:                    NewMetadata(entity.NotFoundCode, http.StatusNotFound, entity.ErrCustomerNotFound.Error()),
+	entity.ErrCustomerAlreadyExists:               NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrCustomerAlreadyExists.Error()),
+	entity.ErrCustomerRefImmutable:                NewMetadata(entity.BadRequestCode, http.StatusUnprocessableEntity, entity.ErrCustomerRefImmutable.Error()),
+	entity.ErrTaxOverrideNotFound:                 NewMetadata(entity.NotFoundCode, http.StatusNotFound, entity.ErrTaxOverrideNotFound.Error()),
+	entity.ErrTaxOverrideAlreadyExists:            NewMetadata(entity.ConflictCode, http.StatusConflict, entity.ErrTaxOverrideAlreadyExists.Error()),
 }
 
 func MapErrorToMetadata(err error) Metadata {
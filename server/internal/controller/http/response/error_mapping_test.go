@@ -21,6 +21,24 @@ func TestMapErrorToMetadata_HTTPStatuses(t *testing.T) {
 		{name: "file_too_large", err: entity.ErrFileToLarge, statusCode: http.StatusRequestEntityTooLarge},
 		{name: "file_not_found", err: entity.ErrFileNotFound, statusCode: http.StatusNotFound},
 		{name: "order_not_found", err: entity.ErrOrderNotFound, statusCode: http.StatusNotFound},
+		{name: "refund_exceeds_order", err: entity.ErrRefundExceedsOrder, statusCode: http.StatusUnprocessableEntity},
+		{name: "order_version_conflict", err: entity.ErrOrderVersionConflict, statusCode: http.StatusConflict},
+		{name: "order_not_editable", err: entity.ErrOrderNotEditable, statusCode: http.StatusConflict},
+		{name: "invalid_status_transition", err: entity.ErrInvalidStatusTransition, statusCode: http.StatusConflict},
+		{name: "deletion_scope_required", err: entity.ErrDeletionScopeRequired, statusCode: http.StatusBadRequest},
+		{name: "invalid_confirmation_token", err: entity.ErrInvalidConfirmationToken, statusCode: http.StatusForbidden},
+		{name: "tax_recalculation_not_found", err: entity.ErrTaxRecalculationNotFound, statusCode: http.StatusNotFound},
+		{name: "tax_recalculation_not_pending", err: entity.ErrTaxRecalculationNotPending, statusCode: http.StatusConflict},
+		{name: "rates_version_mismatch", err: entity.ErrRatesVersionMismatch, statusCode: http.StatusConflict},
+		{name: "exchange_rate_not_found", err: entity.ErrExchangeRateNotFound, statusCode: http.StatusUnprocessableEntity},
+		{name: "boundary_set_not_found", err: entity.ErrBoundarySetNotFound, statusCode: http.StatusNotFound},
+		{name: "invalid_boundary_set", err: entity.ErrInvalidBoundarySet, statusCode: http.StatusUnprocessableEntity},
+		{name: "exemption_certificate_exists", err: entity.ErrExemptionCertificateAlreadyExists, statusCode: http.StatusConflict},
+		{name: "customer_not_found", err: entity.ErrCustomerNotFound, statusCode: http.StatusNotFound},
+		{name: "customer_exists", err: entity.ErrCustomerAlreadyExists, statusCode: http.StatusConflict},
+		{name: "customer_ref_immutable", err: entity.ErrCustomerRefImmutable, statusCode: http.StatusUnprocessableEntity},
+		{name: "tax_override_not_found", err: entity.ErrTaxOverrideNotFound, statusCode: http.StatusNotFound},
+		{name: "tax_override_exists", err: entity.ErrTaxOverrideAlreadyExists, statusCode: http.StatusConflict},
 	}
 
 	for _, tc := range tests {
@@ -23,23 +23,44 @@ import (
 // @name                       x-api-key
 
 type Router struct {
-	echo            *echo.Echo
-	orderController *v1.OrdersControllers
-	middleware      *custommiddleware.Middleware
+	echo                 *echo.Echo
+	orderController      *v1.OrdersControllers
+	boundariesController *v1.BoundariesController
+	exemptionsController *v1.ExemptionsController
+	overridesController  *v1.TaxOverridesController
+	taxController        *v1.TaxController
+	recalcController     *v1.TaxRecalculationsController
+	exchangeController   *v1.ExchangeRatesController
+	customersController  *v1.CustomersController
+	middleware           *custommiddleware.Middleware
 }
 
 func NewRouter(
 	echo *echo.Echo,
 	orderController *v1.OrdersControllers,
+	boundariesController *v1.BoundariesController,
+	exemptionsController *v1.ExemptionsController,
+	overridesController *v1.TaxOverridesController,
+	taxController *v1.TaxController,
+	recalcController *v1.TaxRecalculationsController,
+	exchangeController *v1.ExchangeRatesController,
+	customersController *v1.CustomersController,
 	middleware *custommiddleware.Middleware,
 	validator *request.CustomValidator,
 ) *Router {
 	echo.Validator = validator
 
 	return &Router{
-		echo:            echo,
-		middleware:      middleware,
-		orderController: orderController,
+		echo:                 echo,
+		middleware:           middleware,
+		orderController:      orderController,
+		boundariesController: boundariesController,
+		exemptionsController: exemptionsController,
+		overridesController:  overridesController,
+		taxController:        taxController,
+		recalcController:     recalcController,
+		exchangeController:   exchangeController,
+		customersController:  customersController,
 	}
 }
 
@@ -50,9 +71,12 @@ func (r *Router) RegisterRoutes() {
 			echo.HeaderOrigin,
 			echo.HeaderContentType,
 			echo.HeaderAccept,
+			"If-Match",
 			"x-api-key",
+			"x-user",
 		},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodPut, http.MethodOptions},
+		ExposeHeaders: []string{"ETag"},
+		AllowMethods:  []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodPut, http.MethodPatch, http.MethodOptions},
 	}))
 
 	swaggerHandler := echoSwagger.EchoWrapHandler()
@@ -74,6 +98,7 @@ func (r *Router) RegisterRoutes() {
 
 	withPagination := r.middleware.WithPagination()
 	withApiKey := r.middleware.WithApiKey()
+	withAdminApiKey := r.middleware.WithAdminApiKey()
 
 	v1Group := r.echo.Group("/v1", withApiKey)
 
@@ -81,5 +106,44 @@ func (r *Router) RegisterRoutes() {
 	v1Group.POST("/orders", r.orderController.Create)
 	v1Group.GET("/orders", r.orderController.GetAll, withPagination)
 	v1Group.GET("/orders/:id", r.orderController.GetById)
-	v1Group.DELETE("/orders", r.orderController.DeleteAll)
+	v1Group.PATCH("/orders/:id", r.orderController.Update)
+	v1Group.POST("/orders/:id/void", r.orderController.Void)
+	v1Group.POST("/orders/:id/refunds", r.orderController.Refund)
+	v1Group.GET("/orders/:id/history", r.orderController.History)
+	v1Group.DELETE("/orders", r.orderController.Delete)
+	v1Group.POST("/orders/restore", r.orderController.Restore)
+
+	v1Group.POST("/customers", r.customersController.Create)
+	v1Group.GET("/customers", r.customersController.GetAll, withPagination)
+	v1Group.GET("/customers/:id", r.customersController.GetById)
+	v1Group.PUT("/customers/:id", r.customersController.Update)
+	v1Group.DELETE("/customers/:id", r.customersController.Delete)
+	v1Group.GET("/customers/:id/orders", r.customersController.GetOrders, withPagination)
+	v1Group.GET("/customers/:id/totals", r.customersController.GetTotals)
+
+	v1Group.GET("/tax/explain", r.taxController.Explain)
+
+	v1Group.POST("/exemption-certificates", r.exemptionsController.Create)
+	v1Group.GET("/exemption-certificates", r.exemptionsController.GetByCustomerRef)
+
+	adminGroup := r.echo.Group("/v1/admin", withAdminApiKey)
+
+	adminGroup.POST("/boundaries", r.boundariesController.Upload)
+	adminGroup.GET("/boundaries/:id", r.boundariesController.GetById)
+	adminGroup.POST("/boundaries/:id/activate", r.boundariesController.Activate)
+
+	adminGroup.POST("/orders/wipe-confirmations", r.orderController.RequestWipe)
+	adminGroup.DELETE("/orders", r.orderController.DeleteAll)
+
+	adminGroup.POST("/tax-recalculations", r.recalcController.Preview)
+	adminGroup.GET("/tax-recalculations/:id", r.recalcController.GetById)
+	adminGroup.POST("/tax-recalculations/:id/apply", r.recalcController.Apply)
+	adminGroup.POST("/orders/out-of-scope/resolve", r.recalcController.ResolveOutOfScope)
+
+	adminGroup.POST("/tax-overrides", r.overridesController.Create)
+	adminGroup.GET("/tax-overrides", r.overridesController.GetAll)
+	adminGroup.DELETE("/tax-overrides/:id", r.overridesController.Delete)
+
+	adminGroup.POST("/exchange-rates", r.exchangeController.Create)
+	adminGroup.GET("/exchange-rates", r.exchangeController.GetAll)
 }
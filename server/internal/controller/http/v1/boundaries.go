@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+const (
+	boundaryFileName   = "boundaries"
+	boundaryStateField = "state"
+)
+
+// BoundariesController handles administrative operations
+// on jurisdiction boundary sets.
+type BoundariesController struct {
+	boundaryService  usecase.BoundaryService
+	maxFileSizeBytes int64
+	logger           zerolog.Logger
+}
+
+func NewBoundariesController(boundaryService usecase.BoundaryService, maxFileSizeBytes int64, logger zerolog.Logger) *BoundariesController {
+	l := logger.With().Str("controller", "boundaries_controller").Logger()
+	return &BoundariesController{
+		boundaryService:  boundaryService,
+		maxFileSizeBytes: maxFileSizeBytes,
+		logger:           l,
+	}
+}
+
+// Upload godoc
+// @Summary      Upload a boundary set
+// @Description  Uploads a GeoJSON feature collection of a state, validates it against the tax config of the state and previews the diff against the active boundaries of the state. The set is staged until activated.
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        boundaries  formData  file    true   "GeoJSON file containing boundary features"
+// @Param        state       formData  string  false  "Code of the state the boundaries belong to, the primary state by default"
+// @Success      201  {object}  entity.BoundarySet
+// @Failure      400  {object}  response.Response  "Invalid file format or unknown state"
+// @Failure      404  {object}  response.Response  "File not found"
+// @Failure      413  {object}  response.Response  "File too large"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/boundaries [post]
+func (c *BoundariesController) Upload(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "upload").Logger()
+
+	fileHeader, err := ctx.FormFile(boundaryFileName)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to get file")
+
+		if err == http.ErrMissingFile {
+			return response.NewErrorResponse(ctx, entity.ErrFileNotFound)
+		}
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	if fileHeader.Size > c.maxFileSizeBytes {
+		err := entity.ErrFileToLarge
+		l.Warn().Err(err).Send()
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		l.Error().Err(err).Msg("failed to open file")
+		return response.NewErrorResponse(ctx, err)
+	}
+	defer src.Close()
+
+	set, err := c.boundaryService.Upload(ctx.Request().Context(), ctx.FormValue(boundaryStateField), src)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to upload boundary set")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	l.Info().Int("id", set.Id).Str("state", set.State).Bool("valid", set.Report.Valid).Msg("successfully staged boundary set")
+
+	return response.NewSuccessResponse(ctx, set, http.StatusCreated)
+}
+
+// GetById godoc
+// @Summary      Get boundary set by ID
+// @Description  Returns the validation report and diff of a boundary set.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Boundary set ID"
+// @Success      200  {object}  entity.BoundarySet
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Boundary set not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/boundaries/{id} [get]
+func (c *BoundariesController) GetById(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "get_by_id").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of boundary set")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	set, err := c.boundaryService.GetById(ctx.Request().Context(), id)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get boundary set by id")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, set, http.StatusOK)
+}
+
+// Activate godoc
+// @Summary      Activate a boundary set
+// @Description  Re-validates a staged boundary set and makes it the active one used for tax lookups in its state.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Boundary set ID"
+// @Success      200  {object}  entity.BoundarySet
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Boundary set not found"
+// @Failure      422  {object}  response.Response  "Boundary set failed validation"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/boundaries/{id}/activate [post]
+func (c *BoundariesController) Activate(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "activate").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of boundary set")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	set, err := c.boundaryService.Activate(ctx.Request().Context(), id)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to activate boundary set")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	l.Info().Int("id", set.Id).Msg("successfully activated boundary set")
+
+	return response.NewSuccessResponse(ctx, set, http.StatusOK)
+}
@@ -0,0 +1,284 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// CustomersController handles customers and the orders they placed.
+type CustomersController struct {
+	customerService usecase.CustomerService
+	logger          zerolog.Logger
+}
+
+func NewCustomersController(customerService usecase.CustomerService, logger zerolog.Logger) *CustomersController {
+	l := logger.With().Str("controller", "customers_controller").Logger()
+	return &CustomersController{
+		customerService: customerService,
+		logger:          l,
+	}
+}
+
+// Create godoc
+// @Summary      Create a customer
+// @Description  Registers a customer by its external reference. The address, ZIP code or coordinates are the default location of orders placed by the customer without one.
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.Customer  true  "Customer data"
+// @Success      201      {object}  entity.Customer
+// @Failure      400      {object}  response.Response  "Invalid request body or validation failed"
+// @Failure      409      {object}  response.Response  "Customer with this external reference already exists"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers [post]
+func (c *CustomersController) Create(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "create").Logger()
+
+	var req dto.Customer
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	err = ctx.Validate(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	customer, err := c.customerService.Create(ctx.Request().Context(), req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to create customer")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", customer.Id).Str("external_ref", customer.ExternalRef).Msg("successfully created customer")
+
+	return response.NewSuccessResponse(ctx, customer, http.StatusCreated)
+}
+
+// GetAll godoc
+// @Summary      List customers
+// @Description  Returns a paginated list of customers ordered by ID.
+// @Tags         customers
+// @Produce      json
+// @Param        pageSize  query     int  true  "Limit for pagination"
+// @Param        page      query     int  true  "Offset for pagination"
+// @Success      200       {object}  entity.CustomerList
+// @Failure      400       {object}  response.Response  "Invalid pagination query params"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers [get]
+func (c *CustomersController) GetAll(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "get_all").Logger()
+
+	limit, ok := ctx.Get(entity.LimitKey).(int)
+	if !ok {
+		return response.NewErrorResponse(ctx, entity.ErrInvalidOrEmptyPaginationQueryParams)
+	}
+
+	offset, ok := ctx.Get(entity.OffsetKey).(int)
+	if !ok {
+		return response.NewErrorResponse(ctx, entity.ErrInvalidOrEmptyPaginationQueryParams)
+	}
+
+	customers, err := c.customerService.GetAll(ctx.Request().Context(), limit, offset)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get customers")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, customers, http.StatusOK)
+}
+
+// GetById godoc
+// @Summary      Get customer by ID
+// @Description  Returns the customer with its default location and whether it holds an exemption certificate valid now.
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      200  {object}  entity.Customer
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Customer not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id} [get]
+func (c *CustomersController) GetById(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "get_by_id").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of customer")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	customer, err := c.customerService.GetById(ctx.Request().Context(), id)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to get customer")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, customer, http.StatusOK)
+}
+
+// Update godoc
+// @Summary      Update a customer
+// @Description  Replaces the name and default location of the customer. Orders already placed keep the location they were taxed at. The external reference cannot be changed, as it links orders to the exemption certificates of the customer.
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int           true  "Customer ID"
+// @Param        request  body      dto.Customer  true  "Customer data"
+// @Success      200      {object}  entity.Customer
+// @Failure      400      {object}  response.Response  "Invalid request body or validation failed"
+// @Failure      404      {object}  response.Response  "Customer not found"
+// @Failure      422      {object}  response.Response  "External reference differs from the stored one"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id} [put]
+func (c *CustomersController) Update(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "update").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of customer")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	var req dto.Customer
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	err = ctx.Validate(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	customer, err := c.customerService.Update(ctx.Request().Context(), id, req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to update customer")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", id).Msg("successfully updated customer")
+
+	return response.NewSuccessResponse(ctx, customer, http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete a customer
+// @Description  Deletes the customer together with its exemption certificates. Its orders are kept and no longer linked to it.
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Customer not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id} [delete]
+func (c *CustomersController) Delete(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "delete").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of customer")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	if err := c.customerService.Delete(ctx.Request().Context(), id); err != nil {
+		l.Warn().Err(err).Msg("failed to delete customer")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", id).Msg("successfully deleted customer")
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetOrders godoc
+// @Summary      List orders of a customer
+// @Description  Returns a paginated list of the orders placed by the customer. It takes the same query filters as listing orders.
+// @Tags         customers
+// @Produce      json
+// @Param        id        path      int  true  "Customer ID"
+// @Param        pageSize  query     int  true  "Limit for pagination"
+// @Param        page      query     int  true  "Offset for pagination"
+// @Success      200       {object}  entity.OrderList
+// @Failure      400       {object}  response.Response  "Invalid ID format or query params"
+// @Failure      404       {object}  response.Response  "Customer not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id}/orders [get]
+func (c *CustomersController) GetOrders(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "get_orders").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of customer")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	limit, ok := ctx.Get(entity.LimitKey).(int)
+	if !ok {
+		return response.NewErrorResponse(ctx, entity.ErrInvalidOrEmptyPaginationQueryParams)
+	}
+
+	offset, ok := ctx.Get(entity.OffsetKey).(int)
+	if !ok {
+		return response.NewErrorResponse(ctx, entity.ErrInvalidOrEmptyPaginationQueryParams)
+	}
+
+	filter := dto.OrderFilters{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if err := populateOrderFilters(ctx, &filter); err != nil {
+		l.Warn().Err(err).Msg("invalid query filter")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	orders, err := c.customerService.GetOrders(ctx.Request().Context(), id, filter)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to get orders of customer")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, orders, http.StatusOK)
+}
+
+// GetTotals godoc
+// @Summary      Get order totals of a customer
+// @Description  Sums up the orders placed by the customer. Amounts are net of refunds and leave out voided orders, which are only counted.
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      200  {object}  entity.CustomerTotals
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Customer not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id}/totals [get]
+func (c *CustomersController) GetTotals(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "get_totals").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of customer")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	totals, err := c.customerService.GetTotals(ctx.Request().Context(), id)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to get order totals of customer")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, totals, http.StatusOK)
+}
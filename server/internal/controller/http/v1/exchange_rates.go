@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// ExchangeRatesController handles administrative operations
+// on the exchange rates orders in other currencies are converted with.
+type ExchangeRatesController struct {
+	exchangeService usecase.ExchangeRateService
+	logger          zerolog.Logger
+}
+
+func NewExchangeRatesController(exchangeService usecase.ExchangeRateService, logger zerolog.Logger) *ExchangeRatesController {
+	l := logger.With().Str("controller", "exchange_rates_controller").Logger()
+	return &ExchangeRatesController{
+		exchangeService: exchangeService,
+		logger:          l,
+	}
+}
+
+// Create godoc
+// @Summary      Set exchange rates
+// @Description  Sets the value in USD of one unit of each currency from the given date on, replacing any rate of the same currency and date. Rates set here take precedence over the rate file and take effect immediately for new orders.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ExchangeRates  true  "Exchange rates"
+// @Success      201      {array}   entity.ExchangeRate
+// @Failure      400      {object}  response.Response  "Invalid request body or validation failed"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/exchange-rates [post]
+func (c *ExchangeRatesController) Create(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "create").Logger()
+
+	var req dto.ExchangeRates
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	err = ctx.Validate(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	rates, err := c.exchangeService.Create(ctx.Request().Context(), req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to set exchange rates")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("count", len(rates)).Msg("successfully set exchange rates")
+
+	return response.NewSuccessResponse(ctx, rates, http.StatusCreated)
+}
+
+// GetAll godoc
+// @Summary      List exchange rates
+// @Description  Returns the rates from the rate file and the admin API orders are converted with, sorted by currency and date.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}  entity.ExchangeRate
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/exchange-rates [get]
+func (c *ExchangeRatesController) GetAll(ctx echo.Context) error {
+	return response.NewSuccessResponse(ctx, c.exchangeService.GetAll(ctx.Request().Context()), http.StatusOK)
+}
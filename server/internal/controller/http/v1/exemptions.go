@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// ExemptionsController handles the customer exemption certificate registry.
+type ExemptionsController struct {
+	exemptionService usecase.ExemptionService
+	logger           zerolog.Logger
+}
+
+func NewExemptionsController(exemptionService usecase.ExemptionService, logger zerolog.Logger) *ExemptionsController {
+	l := logger.With().Str("controller", "exemptions_controller").Logger()
+	return &ExemptionsController{
+		exemptionService: exemptionService,
+		logger:           l,
+	}
+}
+
+// Create godoc
+// @Summary      Register an exemption certificate
+// @Description  Registers a tax exemption certificate for a customer. The customer is created on first use. Orders referencing the customer have the listed tax components zeroed out in covered jurisdictions of the certificate state ("*" covers all of them, an empty state means the primary one) from valid_from up to, but not including, valid_to; an empty component list exempts the whole tax.
+// @Tags         exemptions
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ExemptionCertificate  true  "Certificate data"
+// @Success      201      {object}  entity.ExemptionCertificate
+// @Failure      400      {object}  response.Response  "Invalid request body or validation failed"
+// @Failure      409      {object}  response.Response  "Certificate already exists"
+// @Security     ApiKeyAuth
+// @Router       /v1/exemption-certificates [post]
+func (c *ExemptionsController) Create(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "create").Logger()
+
+	var req dto.ExemptionCertificate
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	err = ctx.Validate(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	cert, err := c.exemptionService.Create(ctx.Request().Context(), req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to create exemption certificate")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", cert.Id).Str("customer_ref", cert.CustomerRef).Msg("successfully created exemption certificate")
+
+	return response.NewSuccessResponse(ctx, cert, http.StatusCreated)
+}
+
+// GetByCustomerRef godoc
+// @Summary      List exemption certificates of a customer
+// @Description  Returns all exemption certificates registered for the customer reference.
+// @Tags         exemptions
+// @Produce      json
+// @Param        customer_ref  query     string  true  "Customer reference"
+// @Success      200           {array}   entity.ExemptionCertificate
+// @Failure      400           {object}  response.Response  "Missing customer reference"
+// @Security     ApiKeyAuth
+// @Router       /v1/exemption-certificates [get]
+func (c *ExemptionsController) GetByCustomerRef(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "get_by_customer_ref").Logger()
+
+	customerRef := ctx.QueryParam(customerRefQueryParam)
+	if customerRef == "" {
+		l.Warn().Msg("missing customer reference")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	certs, err := c.exemptionService.GetByCustomerRef(ctx.Request().Context(), customerRef)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get exemption certificates")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, certs, http.StatusOK)
+}
@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"crypto/rand"
 	"encoding/csv"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
@@ -23,17 +25,38 @@ import (
 const (
 	fileName = "orders"
 
+	taxInclusiveFormField = "tax_inclusive"
+
 	idParam = "id"
 
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+
 	statusQueryParam         = "status"
 	reportingCodeQueryParam  = "reporting_code"
+	stateQueryParam          = "state"
+	categoryQueryParam       = "category"
+	customerRefQueryParam    = "customer_ref"
+	customerIdQueryParam     = "customer_id"
+	taxOverrideQueryParam    = "tax_override"
+	boundaryResolvedParam    = "boundary_resolved"
+	warningQueryParam        = "warning"
+	geocodingMethodParam     = "geocoding_method"
 	totalAmountMinQueryParam = "total_amount_min"
 	totalAmountMaxQueryParam = "total_amount_max"
 	fromDateQueryParam       = "from_date"
 	toDateQueryParam         = "to_date"
 	sortByQueryParam         = "sort_by"
 	sortOrderQueryParam      = "sort_order"
+	idsQueryParam            = "ids"
+	importIdQueryParam       = "import_id"
+	confirmationTokenParam   = "confirmation_token"
+	metadataQueryParam       = "metadata"
+	tagsQueryParam           = "tags"
 	maxConcurrentImports     = 4
+
+	// maxFilterIds caps the ids an order filter can list.
+	maxFilterIds = 1000
 )
 
 var allowedCSVContentTypes = map[string]struct{}{
@@ -92,12 +115,13 @@ func NewOrdersController(orderService usecase.OrderService, maxFileSizeBytes int
 
 // BatchCreate godoc
 // @Summary      Batch create orders from CSV
-// @Description  Uploads a CSV file, validates format and size, and processes orders asynchronously.
+// @Description  Uploads a CSV file, validates format and size, and processes orders asynchronously. Columns: id, longitude, latitude, timestamp, subtotal, then optional product category, customer reference, address, shipping, handling, discount, a JSON array of items, which replaces the subtotal, the currency code, a JSON object of metadata and a JSON array of tags. Set tax_inclusive when the amounts of all rows already include the tax.
 // @Tags         orders
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        orders  formData  file  true  "CSV file containing orders data"
-// @Success      202  {object}  response.Response  "Successfully accepted for processing"
+// @Param        orders         formData  file     true   "CSV file containing orders data"
+// @Param        tax_inclusive  formData  boolean  false  "Amounts already include the tax"
+// @Success      202  {object}  dto.OrderImport    "Successfully accepted for processing, with the id of the import job"
 // @Failure      400  {object}  response.Response    "Invalid file format or file too large"
 // @Failure      404  {object}  response.Response    "File not found"
 // @Security     ApiKeyAuth
@@ -115,6 +139,17 @@ func (c *OrdersControllers) BatchCreate(ctx echo.Context) error {
 		return response.NewErrorResponse(ctx, err)
 	}
 
+	taxInclusive, err := parseOptionalBool(ctx.FormValue(taxInclusiveFormField))
+	if err != nil {
+		l.Warn().Err(err).Msg("invalid tax inclusive flag")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+	options := dto.OrderImport{
+		Id:           newImportId(),
+		TaxInclusive: taxInclusive != nil && *taxInclusive,
+		Actor:        entity.ActorFromContext(ctx.Request().Context()),
+	}
+
 	contentType := fileHeader.Header.Get("Content-Type")
 	if !isAllowedCSVUpload(contentType, fileHeader.Filename) {
 		l.Warn().
@@ -147,23 +182,32 @@ func (c *OrdersControllers) BatchCreate(ctx echo.Context) error {
 
 	go func() {
 		defer func() { <-c.importSlots }()
-		c.orderService.AsyncBatchCreate(reader, src)
+		c.orderService.AsyncBatchCreate(reader, src, options)
 	}()
 
-	l.Info().Msg("successfully pushed orders for process")
+	l.Info().Str("import_id", options.Id).Msg("successfully pushed orders for process")
+
+	return response.NewSuccessResponse(ctx, options, http.StatusAccepted)
+}
 
-	return response.NewSuccessResponse(ctx, nil, http.StatusAccepted)
+// newImportId returns a random id of an import job.
+func newImportId() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
 // Create godoc
 // @Summary      Create a single order
-// @Description  Manually create a new order with tax rates and jurisdictions.
+// @Description  Manually create a new order with tax rates and jurisdictions. Orders without coordinates are geocoded offline by zip or the ZIP code found in address. Items, when given, replace the subtotal and are taxed one by one by their own category. Set tax_inclusive when the amounts already include the tax, which is then backed out of them. Set explain to store how the tax was derived on the order. Orders given a customer_id, or the customer_ref of a registered customer, are linked to the customer and placed at its default location when they have none.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Param        request  body      dto.Order  true  "Order data"
 // @Success      200      {object}  entity.Order
 // @Failure      400      {object}  response.Response  "Invalid request body or validation failed"
+// @Failure      404      {object}  response.Response  "Customer not found"
+// @Failure      422      {object}  response.Response  "No exchange rate for the currency at the order date"
 // @Failure      500      {object}  response.Response  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /v1/orders [post]
@@ -207,14 +251,26 @@ func (c *OrdersControllers) Create(ctx echo.Context) error {
 // @Produce      json
 // @Param        pageSize              query     int     true  "Limit for pagination"
 // @Param        page             query     int     true  "Offset for pagination"
-// @Param        status             query     entity.OrderStatus  false  "Filter by order status"
+// @Param        ids                query     string  false  "Filter by comma-separated order IDs"
+// @Param        import_id          query     string  false  "Filter by import job ID"
+// @Param        status             query     string  false  "Filter by comma-separated order statuses (pending, completed, out_of_scope, failed_tax_resolution, voided, refunded, partially_refunded)"
+// @Param        state              query     string  false  "Filter by code of the state the order was taxed in"
 // @Param        reporting_code     query     string  false  "Filter by reporting code"
+// @Param        category           query     string  false  "Filter by product category"
+// @Param        customer_ref       query     string  false  "Filter by customer reference"
+// @Param        customer_id        query     int     false  "Filter by ID of the customer"
+// @Param        tax_override       query     string  false  "Filter by name of the applied tax override"
+// @Param        boundary_resolved  query     bool    false  "Filter by orders resolved on a shared edge or snapped to a nearby boundary"
+// @Param        warning            query     entity.OrderWarning  false  "Filter by order warning"
+// @Param        geocoding_method   query     entity.GeocodingMethod  false  "Filter by how the order location was obtained"
 // @Param        total_amount_min   query     number  false  "Minimum total amount"
 // @Param        total_amount_max   query     number  false  "Maximum total amount"
 // @Param        from_date          query     string  false  "Start date (ISO8601)"        example(2023-01-01T00:00:00Z)
 // @Param        to_date            query     string  false  "End date (ISO8601)"          example(2023-12-31T23:59:59Z)
-// @Param        sort_by            query     string  false  "Sort by field (id, created_at, total_amount)"
+// @Param        sort_by            query     string  false  "Sort by field (id, created_at, total_amount, net_total_amount, status)"
 // @Param        sort_order         query     string  false  "Sort order (asc, desc)"      Enums(asc, desc)
+// @Param        metadata           query     []string  false  "Filter by metadata value given as key=value, repeatable" collectionFormat(multi)
+// @Param        tags               query     string  false  "Filter by comma-separated tags the order must all have"
 // @Success      200  {object}  entity.OrderList
 // @Failure      400  {object}  response.Response  "Invalid pagination query params"
 // @Failure      500  {object}  response.Response
@@ -234,9 +290,8 @@ func (c *OrdersControllers) GetAll(ctx echo.Context) error {
 	}
 
 	filter := dto.OrderFilters{
-		Limit:         limit,
-		Offset:        offset,
-		ReportingCode: ctx.QueryParam(reportingCodeQueryParam),
+		Limit:  limit,
+		Offset: offset,
 	}
 	if err := populateOrderFilters(ctx, &filter); err != nil {
 		l.Warn().Err(err).Msg("invalid query filter")
@@ -254,19 +309,140 @@ func (c *OrdersControllers) GetAll(ctx echo.Context) error {
 	return response.NewSuccessResponse(ctx, orders, http.StatusOK)
 }
 
+// Delete godoc
+// @Summary      Delete orders
+// @Description  Deletes the orders matching the filters, which take the same query params as listing orders. The `ids`, `import_id` or `customer_id` filter is required, and the other filters narrow it down; wiping all orders is an admin operation. Deleted orders are hidden and can be restored until the retention period ends, when they are purged.
+// @Tags         orders
+// @Produce      json
+// @Param        ids                query     string  false  "Comma-separated order IDs"
+// @Param        import_id          query     string  false  "Import job ID"
+// @Param        status             query     string  false  "Comma-separated order statuses"
+// @Param        state              query     string  false  "Code of the state the order was taxed in"
+// @Param        reporting_code     query     string  false  "Reporting code"
+// @Param        category           query     string  false  "Product category"
+// @Param        customer_ref       query     string  false  "Customer reference"
+// @Param        customer_id        query     int     false  "ID of the customer"
+// @Param        tax_override       query     string  false  "Name of the applied tax override"
+// @Param        boundary_resolved  query     bool    false  "Orders resolved on a shared edge or snapped to a nearby boundary"
+// @Param        warning            query     entity.OrderWarning  false  "Order warning"
+// @Param        geocoding_method   query     entity.GeocodingMethod  false  "How the order location was obtained"
+// @Param        total_amount_min   query     number  false  "Minimum total amount"
+// @Param        total_amount_max   query     number  false  "Maximum total amount"
+// @Param        from_date          query     string  false  "Start date (ISO8601)"        example(2023-01-01T00:00:00Z)
+// @Param        to_date            query     string  false  "End date (ISO8601)"          example(2023-12-31T23:59:59Z)
+// @Param        metadata           query     []string  false  "Metadata value given as key=value, repeatable" collectionFormat(multi)
+// @Param        tags               query     string  false  "Comma-separated tags the order must all have"
+// @Success      200  {object}  entity.OrderDeletion
+// @Failure      400  {object}  response.Response  "Invalid or missing filters"
+// @Failure      500  {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/orders [delete]
+func (c *OrdersControllers) Delete(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "delete").Logger()
+
+	var filter dto.OrderFilters
+	if err := populateOrderFilters(ctx, &filter); err != nil {
+		l.Warn().Err(err).Msg("invalid query filter")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	deletion, err := c.orderService.Delete(ctx.Request().Context(), filter)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to delete orders")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	l.Info().Int("deleted", deletion.Deleted).Msg("successfully deleted orders")
+
+	return response.NewSuccessResponse(ctx, deletion, http.StatusOK)
+}
+
+// Restore godoc
+// @Summary      Restore deleted orders
+// @Description  Restores the deleted orders matching the filters that were not purged yet. The filters take the same query params as listing orders. The `ids`, `import_id` or `customer_id` filter is required, like for deletion.
+// @Tags         orders
+// @Produce      json
+// @Param        ids                query     string  false  "Comma-separated order IDs"
+// @Param        import_id          query     string  false  "Import job ID"
+// @Param        status             query     string  false  "Comma-separated order statuses"
+// @Param        state              query     string  false  "Code of the state the order was taxed in"
+// @Param        reporting_code     query     string  false  "Reporting code"
+// @Param        category           query     string  false  "Product category"
+// @Param        customer_ref       query     string  false  "Customer reference"
+// @Param        customer_id        query     int     false  "ID of the customer"
+// @Param        tax_override       query     string  false  "Name of the applied tax override"
+// @Param        boundary_resolved  query     bool    false  "Orders resolved on a shared edge or snapped to a nearby boundary"
+// @Param        warning            query     entity.OrderWarning  false  "Order warning"
+// @Param        geocoding_method   query     entity.GeocodingMethod  false  "How the order location was obtained"
+// @Param        total_amount_min   query     number  false  "Minimum total amount"
+// @Param        total_amount_max   query     number  false  "Maximum total amount"
+// @Param        from_date          query     string  false  "Start date (ISO8601)"        example(2023-01-01T00:00:00Z)
+// @Param        to_date            query     string  false  "End date (ISO8601)"          example(2023-12-31T23:59:59Z)
+// @Param        metadata           query     []string  false  "Metadata value given as key=value, repeatable" collectionFormat(multi)
+// @Param        tags               query     string  false  "Comma-separated tags the order must all have"
+// @Success      200  {object}  entity.OrderRestoration
+// @Failure      400  {object}  response.Response  "Invalid or missing filters"
+// @Failure      500  {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/orders/restore [post]
+func (c *OrdersControllers) Restore(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "restore").Logger()
+
+	var filter dto.OrderFilters
+	if err := populateOrderFilters(ctx, &filter); err != nil {
+		l.Warn().Err(err).Msg("invalid query filter")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	restoration, err := c.orderService.Restore(ctx.Request().Context(), filter)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to restore orders")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	l.Info().Int("restored", restoration.Restored).Msg("successfully restored orders")
+
+	return response.NewSuccessResponse(ctx, restoration, http.StatusOK)
+}
+
+// RequestWipe godoc
+// @Summary      Request a wipe of all orders
+// @Description  Issues the token that confirms deleting all orders. Only the latest token is valid, once and for five minutes.
+// @Tags         admin
+// @Produce      json
+// @Success      201  {object}  entity.WipeConfirmation
+// @Failure      500  {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/orders/wipe-confirmations [post]
+func (c *OrdersControllers) RequestWipe(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "request_wipe").Logger()
+
+	confirmation, err := c.orderService.RequestWipe(ctx.Request().Context())
+	if err != nil {
+		l.Error().Err(err).Msg("failed to request wipe")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	l.Warn().Time("expires_at", confirmation.ExpiresAt).Msg("issued wipe confirmation")
+
+	return response.NewSuccessResponse(ctx, confirmation, http.StatusCreated)
+}
+
 // DeleteAll godoc
 // @Summary      Delete all orders
-// @Description  Remove all orders from the database.
-// @Tags         orders
+// @Description  Removes all orders from the database for good, including deleted ones. Requires the token issued by the wipe confirmation request. The history of the orders is kept.
+// @Tags         admin
 // @Produce      json
+// @Param        confirmation_token  query  string  true  "Wipe confirmation token"
 // @Success      204  "No Content"
+// @Failure      403  {object}  response.Response  "Invalid or expired confirmation token"
 // @Failure      500  {object}  response.Response  "Internal server error"
 // @Security     ApiKeyAuth
-// @Router       /v1/orders [delete]
+// @Router       /v1/admin/orders [delete]
 func (c *OrdersControllers) DeleteAll(ctx echo.Context) error {
 	l := c.logger.With().Str("method", "delete_all").Logger()
 
-	err := c.orderService.DeleteAll(ctx.Request().Context())
+	err := c.orderService.DeleteAll(ctx.Request().Context(), ctx.QueryParam(confirmationTokenParam))
 	if err != nil {
 		l.Error().Err(err).Msg("failed to delete all orders")
 		return response.NewErrorResponse(ctx, err)
@@ -279,7 +455,7 @@ func (c *OrdersControllers) DeleteAll(ctx echo.Context) error {
 
 // GetById godoc
 // @Summary      Get order by ID
-// @Description  Fetch detailed information about a specific order using its unique identifier.
+// @Description  Fetch detailed information about a specific order, including its items and refunds, using its unique identifier. The ETag header carries the order version for conditional updates.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
@@ -309,9 +485,194 @@ func (c *OrdersControllers) GetById(ctx echo.Context) error {
 
 	l.Info().Int("id", order.Id).Msg("successfully retrieved order by id")
 
+	setETag(ctx, order.Version)
+	return response.NewSuccessResponse(ctx, order, http.StatusOK)
+}
+
+// Update godoc
+// @Summary      Update an order
+// @Description  Corrects the coordinates, subtotal or timestamp of an order and resolves its tax again; omitted fields are kept. The subtotal of an order with items comes from the items and cannot be set. Send the ETag of the order in If-Match to reject the update when the order was changed meanwhile. Voided and refunded orders cannot be updated.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int              true   "Order ID"
+// @Param        If-Match  header    string           false  "ETag of the order version being updated"
+// @Param        request   body      dto.OrderUpdate  true   "Corrected fields"
+// @Success      200       {object}  entity.Order
+// @Failure      400       {object}  response.Response  "Invalid ID format, If-Match header or request body"
+// @Failure      404       {object}  response.Response  "Order not found"
+// @Failure      409       {object}  response.Response  "Order was changed meanwhile or can no longer be changed"
+// @Failure      500       {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/orders/{id} [patch]
+func (c *OrdersControllers) Update(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "update").Logger()
+
+	id, version, err := parseOrderVersion(ctx)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id or version of order")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	var req dto.OrderUpdate
+	if err := ctx.Bind(&req); err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	order, err := c.orderService.Update(ctx.Request().Context(), id, version, req)
+	if err != nil {
+		l.Error().Err(err).Int("id", id).Msg("failed to update order")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", order.Id).Str("status", string(order.Status)).Msg("successfully updated order")
+
+	setETag(ctx, order.Version)
+	return response.NewSuccessResponse(ctx, order, http.StatusOK)
+}
+
+// Void godoc
+// @Summary      Void an order
+// @Description  Voids an order that was recorded by mistake. Send the ETag of the order in If-Match to reject voiding when the order was changed meanwhile. Voided and refunded orders cannot be voided.
+// @Tags         orders
+// @Produce      json
+// @Param        id        path      int     true   "Order ID"
+// @Param        If-Match  header    string  false  "ETag of the order version being voided"
+// @Success      200       {object}  entity.Order
+// @Failure      400       {object}  response.Response  "Invalid ID format or If-Match header"
+// @Failure      404       {object}  response.Response  "Order not found"
+// @Failure      409       {object}  response.Response  "Order was changed meanwhile or can no longer be changed"
+// @Failure      500       {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/orders/{id}/void [post]
+func (c *OrdersControllers) Void(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "void").Logger()
+
+	id, version, err := parseOrderVersion(ctx)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id or version of order")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	order, err := c.orderService.Void(ctx.Request().Context(), id, version)
+	if err != nil {
+		l.Error().Err(err).Int("id", id).Msg("failed to void order")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", order.Id).Msg("successfully voided order")
+
+	setETag(ctx, order.Version)
 	return response.NewSuccessResponse(ctx, order, http.StatusOK)
 }
 
+// Refund godoc
+// @Summary      Refund an order
+// @Description  Refunds a full or partial return. Lines refund the pre-tax amount of the subtotal, shipping or handling, or of an item line of the subtotal, everything left on it when their amount is omitted. The amount is spread over everything left on the order after the lines, and omitting both refunds everything left. The tax of every component and item line is reversed at its own rate, and the refund lists how it was allocated. Only completed and partially_refunded orders can be refunded. The order becomes partially_refunded, or refunded once nothing is left, and its net figures drop accordingly.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int              true  "Order ID"
+// @Param        request  body      dto.OrderRefund  true  "Refund data"
+// @Success      201      {object}  entity.OrderRefund
+// @Failure      400      {object}  response.Response  "Invalid ID format or request body"
+// @Failure      404      {object}  response.Response  "Order not found"
+// @Failure      409      {object}  response.Response  "Order was changed meanwhile or cannot be refunded in its status"
+// @Failure      422      {object}  response.Response  "Refund exceeds the amount left on the order or a line of it, or names a line the order does not have"
+// @Failure      500      {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/orders/{id}/refunds [post]
+func (c *OrdersControllers) Refund(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "refund").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of order")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	var req dto.OrderRefund
+	if err := ctx.Bind(&req); err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	refund, err := c.orderService.Refund(ctx.Request().Context(), id, req)
+	if err != nil {
+		l.Error().Err(err).Int("id", id).Msg("failed to refund order")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", id).Int("refund_id", refund.Id).Msg("successfully refunded order")
+
+	return response.NewSuccessResponse(ctx, refund, http.StatusCreated)
+}
+
+// History godoc
+// @Summary      Get order history
+// @Description  Lists every change of an order from the oldest: creation, import, update, void and refund, with the actor (API key fingerprint and the user named in the x-user header), the source (API or import job id) and the order before and after the change. The history outlives the order.
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {array}   entity.OrderEvent
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Order not found"
+// @Failure      500  {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/orders/{id}/history [get]
+func (c *OrdersControllers) History(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "history").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of order")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	events, err := c.orderService.History(ctx.Request().Context(), id)
+	if err != nil {
+		l.Error().Err(err).Int("id", id).Msg("failed to get order history")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	l.Info().Int("id", id).Int("count", len(events)).Msg("successfully retrieved order history")
+
+	return response.NewSuccessResponse(ctx, events, http.StatusOK)
+}
+
+// parseOrderVersion returns the order id from the path and the version
+// from the If-Match header, which is zero when the header is not sent.
+func parseOrderVersion(ctx echo.Context) (int, int, error) {
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	etag := strings.TrimSpace(ctx.Request().Header.Get(ifMatchHeader))
+	if etag == "" {
+		return id, 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(etag, "W/"), `"`))
+	if err != nil || version <= 0 {
+		return 0, 0, errors.Join(entity.ErrBadRequest, err)
+	}
+	return id, version, nil
+}
+
+// setETag sets the ETag of the response to the order version.
+func setETag(ctx echo.Context, version int) {
+	ctx.Response().Header().Set(etagHeader, fmt.Sprintf(`"%d"`, version))
+}
+
 func validateOrderRequest(req dto.Order) error {
 	if req.Latitude < -90 || req.Latitude > 90 {
 		return fmt.Errorf("latitude is out of range")
@@ -329,19 +690,71 @@ func validateOrderRequest(req dto.Order) error {
 		return fmt.Errorf("timestamp is required")
 	}
 
+	if req.Zip != "" {
+		if _, _, ok := entity.NormalizeZip(req.Zip); !ok {
+			return fmt.Errorf("zip must be a ZIP or ZIP+4 code")
+		}
+	}
+
 	return nil
 }
 
 func populateOrderFilters(ctx echo.Context, filters *dto.OrderFilters) error {
-	if status := strings.TrimSpace(ctx.QueryParam(statusQueryParam)); status != "" {
-		if status != string(entity.OrderStatusCompleted) && status != string(entity.OrderStatusOutOfScope) {
+	filters.State = strings.ToUpper(strings.TrimSpace(ctx.QueryParam(stateQueryParam)))
+	filters.ReportingCode = ctx.QueryParam(reportingCodeQueryParam)
+	filters.Category = ctx.QueryParam(categoryQueryParam)
+	filters.CustomerRef = ctx.QueryParam(customerRefQueryParam)
+	filters.TaxOverride = ctx.QueryParam(taxOverrideQueryParam)
+	filters.ImportId = strings.TrimSpace(ctx.QueryParam(importIdQueryParam))
+
+	ids, err := parseIds(ctx.QueryParam(idsQueryParam))
+	if err != nil {
+		return err
+	}
+	filters.Ids = ids
+
+	if v := strings.TrimSpace(ctx.QueryParam(customerIdQueryParam)); v != "" {
+		customerId, err := strconv.Atoi(v)
+		if err != nil || customerId <= 0 {
+			return entity.ErrBadRequest
+		}
+		filters.CustomerId = &customerId
+	}
+
+	metadata, err := parseMetadata(ctx.QueryParams()[metadataQueryParam])
+	if err != nil {
+		return err
+	}
+	filters.Metadata = metadata
+	filters.Tags = parseTags(ctx.QueryParam(tagsQueryParam))
+
+	statuses, err := parseStatuses(ctx.QueryParams()[statusQueryParam])
+	if err != nil {
+		return err
+	}
+	filters.Statuses = statuses
+
+	if warning := strings.TrimSpace(ctx.QueryParam(warningQueryParam)); warning != "" {
+		if warning != string(entity.OrderWarningAmbiguousJurisdiction) {
+			return entity.ErrBadRequest
+		}
+		filters.Warning = warning
+	}
+
+	if method := strings.TrimSpace(ctx.QueryParam(geocodingMethodParam)); method != "" {
+		if !slices.Contains([]entity.GeocodingMethod{
+			entity.GeocodingMethodCoordinates,
+			entity.GeocodingMethodZipJurisdiction,
+			entity.GeocodingMethodZipCentroid,
+			entity.GeocodingMethodUnresolved,
+		}, entity.GeocodingMethod(method)) {
 			return entity.ErrBadRequest
 		}
-		filters.Status = status
+		filters.GeocodingMethod = method
 	}
 
 	if sortBy := strings.TrimSpace(ctx.QueryParam(sortByQueryParam)); sortBy != "" {
-		if !slices.Contains([]string{"id", "created_at", "total_amount", "status"}, sortBy) {
+		if !slices.Contains([]string{"id", "created_at", "total_amount", "net_total_amount", "status"}, sortBy) {
 			return entity.ErrBadRequest
 		}
 		filters.SortBy = sortBy
@@ -382,6 +795,12 @@ func populateOrderFilters(ctx echo.Context, filters *dto.OrderFilters) error {
 	}
 	filters.ToDate = toDate
 
+	boundaryResolved, err := parseOptionalBool(ctx.QueryParam(boundaryResolvedParam))
+	if err != nil {
+		return err
+	}
+	filters.BoundaryResolved = boundaryResolved
+
 	if filters.FromDate != nil && filters.ToDate != nil && filters.FromDate.After(*filters.ToDate) {
 		return entity.ErrBadRequest
 	}
@@ -389,6 +808,88 @@ func populateOrderFilters(ctx echo.Context, filters *dto.OrderFilters) error {
 	return nil
 }
 
+// parseMetadata parses metadata filters given as key=value pairs.
+func parseMetadata(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	metadata := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, entity.ErrBadRequest
+		}
+		metadata[key] = value
+	}
+	return metadata, nil
+}
+
+// parseTags parses a comma-separated list of tags, skipping blank ones.
+func parseTags(v string) []string {
+	var tags []string
+	for _, tag := range strings.Split(v, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseStatuses parses order statuses given as repeated
+// or comma-separated values, skipping blank and repeated ones.
+// Unknown statuses return ErrBadRequest.
+func parseStatuses(values []string) ([]string, error) {
+	var statuses []string
+	for _, v := range values {
+		for _, status := range strings.Split(v, ",") {
+			status = strings.TrimSpace(status)
+			if status == "" || slices.Contains(statuses, status) {
+				continue
+			}
+			if !slices.Contains([]entity.OrderStatus{
+				entity.OrderStatusPending,
+				entity.OrderStatusCompleted,
+				entity.OrderStatusOutOfScope,
+				entity.OrderStatusFailedTaxResolution,
+				entity.OrderStatusPartiallyRefunded,
+				entity.OrderStatusRefunded,
+				entity.OrderStatusVoided,
+			}, entity.OrderStatus(status)) {
+				return nil, entity.ErrBadRequest
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// parseIds parses a comma-separated list of order ids.
+func parseIds(v string) ([]int, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(v, ",")
+	if len(parts) > maxFilterIds {
+		return nil, fmt.Errorf("more than %d ids", maxFilterIds)
+	}
+
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if id <= 0 {
+			return nil, entity.ErrBadRequest
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 func parseOptionalFloat(v string) (*float64, error) {
 	if strings.TrimSpace(v) == "" {
 		return nil, nil
@@ -402,6 +903,19 @@ func parseOptionalFloat(v string) (*float64, error) {
 	return &parsed, nil
 }
 
+func parseOptionalBool(v string) (*bool, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, errors.Join(entity.ErrBadRequest, err)
+	}
+
+	return &parsed, nil
+}
+
 func parseOptionalDate(v string) (*time.Time, error) {
 	if strings.TrimSpace(v) == "" {
 		return nil, nil
@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseOrderVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		id          string
+		ifMatch     string
+		wantVersion int
+		wantErr     bool
+	}{
+		{name: "no_header", id: "7", wantVersion: 0},
+		{name: "strong_etag", id: "7", ifMatch: `"3"`, wantVersion: 3},
+		{name: "weak_etag", id: "7", ifMatch: `W/"4"`, wantVersion: 4},
+		{name: "invalid_etag", id: "7", ifMatch: `"abc"`, wantErr: true},
+		{name: "zero_version", id: "7", ifMatch: `"0"`, wantErr: true},
+		{name: "invalid_id", id: "x", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPatch, "/", nil)
+			if tc.ifMatch != "" {
+				req.Header.Set(ifMatchHeader, tc.ifMatch)
+			}
+			ctx := echo.New().NewContext(req, httptest.NewRecorder())
+			ctx.SetParamNames(idParam)
+			ctx.SetParamValues(tc.id)
+
+			id, version, err := parseOrderVersion(ctx)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseOrderVersion() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && (id != 7 || version != tc.wantVersion) {
+				t.Errorf("parseOrderVersion() = %d, %d, want 7, %d", id, version, tc.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParseIds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    []int
+		wantErr bool
+	}{
+		{name: "empty", value: ""},
+		{name: "single", value: "5", want: []int{5}},
+		{name: "list", value: "1, 2,3", want: []int{1, 2, 3}},
+		{name: "not_a_number", value: "1,x", wantErr: true},
+		{name: "not_positive", value: "0", wantErr: true},
+		{name: "too_many", value: strings.Repeat("1,", maxFilterIds) + "1", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseIds(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseIds() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("parseIds() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "pairs", values: []string{"channel=web", "campaign=a=b"}, want: map[string]string{"channel": "web", "campaign": "a=b"}},
+		{name: "empty_value", values: []string{"store_id="}, want: map[string]string{"store_id": ""}},
+		{name: "no_separator", values: []string{"channel"}, wantErr: true},
+		{name: "empty_key", values: []string{"=web"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseMetadata(tc.values)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseMetadata() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !maps.Equal(got, tc.want) {
+				t.Errorf("parseMetadata() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseStatuses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "single", values: []string{"voided"}, want: []string{"voided"}},
+		{name: "comma_separated", values: []string{"pending, failed_tax_resolution,"}, want: []string{"pending", "failed_tax_resolution"}},
+		{name: "repeated", values: []string{"refunded", "partially_refunded,refunded"}, want: []string{"refunded", "partially_refunded"}},
+		{name: "unknown", values: []string{"completed,shipped"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseStatuses(tc.values)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseStatuses() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("parseStatuses() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	t.Parallel()
+
+	if got := parseTags(" promo, ,q3 "); !slices.Equal(got, []string{"promo", "q3"}) {
+		t.Errorf("parseTags() = %v", got)
+	}
+	if got := parseTags(""); got != nil {
+		t.Errorf("parseTags() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+const (
+	latQueryParam       = "lat"
+	lonQueryParam       = "lon"
+	subtotalQueryParam  = "subtotal"
+	shippingQueryParam  = "shipping"
+	handlingQueryParam  = "handling"
+	discountQueryParam  = "discount"
+	timestampQueryParam = "timestamp"
+	zipQueryParam       = "zip"
+	taxInclusiveParam   = "tax_inclusive"
+)
+
+// TaxController exposes tax lookups that do not create orders.
+type TaxController struct {
+	taxService usecase.TaxService
+	logger     zerolog.Logger
+}
+
+func NewTaxController(taxService usecase.TaxService, logger zerolog.Logger) *TaxController {
+	l := logger.With().Str("controller", "tax_controller").Logger()
+	return &TaxController{
+		taxService: taxService,
+		logger:     l,
+	}
+}
+
+// Explain godoc
+// @Summary      Explain tax of a location
+// @Description  Calculates the tax of an order at the location without storing it and returns every R-tree candidate feature, which of them contain the point, the winner by index priority, the matched tax config entry and layers, the applied override and every computation step.
+// @Tags         tax
+// @Produce      json
+// @Param        lat           query     number  false  "Latitude, required unless zip is given"
+// @Param        lon           query     number  false  "Longitude, required unless zip is given"
+// @Param        zip           query     string  false  "ZIP or ZIP+4 code geocoded when coordinates are not given"
+// @Param        subtotal      query     number  false  "Order subtotal"
+// @Param        shipping      query     number  false  "Shipping charge"
+// @Param        handling      query     number  false  "Handling charge"
+// @Param        discount      query     number  false  "Discount taken off the subtotal"
+// @Param        tax_inclusive query     boolean false  "Amounts already include the tax"
+// @Param        category      query     string  false  "Product category"
+// @Param        customer_ref  query     string  false  "Customer reference"
+// @Param        timestamp     query     string  false  "Order time (ISO8601), defaults to now"  example(2025-08-09T12:00:00Z)
+// @Success      200  {object}  entity.TaxExplanation
+// @Failure      400  {object}  response.Response  "Invalid query params"
+// @Failure      500  {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/tax/explain [get]
+func (c *TaxController) Explain(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "explain").Logger()
+
+	req, err := parseExplainRequest(ctx)
+	if err != nil {
+		l.Warn().Err(err).Msg("invalid query params")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	if err := validateOrderRequest(req); err != nil {
+		l.Warn().Err(err).Msg("failed domain validation for request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	explanation, err := c.taxService.Explain(ctx.Request().Context(), req)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to explain tax")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, explanation, http.StatusOK)
+}
+
+func parseExplainRequest(ctx echo.Context) (dto.Order, error) {
+	lat, err := parseOptionalFloat(ctx.QueryParam(latQueryParam))
+	if err != nil {
+		return dto.Order{}, err
+	}
+
+	lon, err := parseOptionalFloat(ctx.QueryParam(lonQueryParam))
+	if err != nil {
+		return dto.Order{}, err
+	}
+
+	zip := ctx.QueryParam(zipQueryParam)
+	if (lat == nil || lon == nil) && (lat != nil || lon != nil || zip == "") {
+		return dto.Order{}, entity.ErrBadRequest
+	}
+
+	var amounts [4]float64
+	for i, param := range []string{subtotalQueryParam, shippingQueryParam, handlingQueryParam, discountQueryParam} {
+		amount, err := parseOptionalFloat(ctx.QueryParam(param))
+		if err != nil {
+			return dto.Order{}, err
+		}
+		if amount != nil {
+			if *amount < 0 {
+				return dto.Order{}, entity.ErrBadRequest
+			}
+			amounts[i] = *amount
+		}
+	}
+
+	taxInclusive, err := parseOptionalBool(ctx.QueryParam(taxInclusiveParam))
+	if err != nil {
+		return dto.Order{}, err
+	}
+
+	timestamp, err := parseOptionalDate(ctx.QueryParam(timestampQueryParam))
+	if err != nil {
+		return dto.Order{}, err
+	}
+
+	req := dto.Order{
+		Zip:         zip,
+		Category:    ctx.QueryParam(categoryQueryParam),
+		CustomerRef: ctx.QueryParam(customerRefQueryParam),
+		Subtotal:    amounts[0],
+		Shipping:    amounts[1],
+		Handling:    amounts[2],
+		Discount:    amounts[3],
+		Timestamp:   time.Now(),
+		Explain:     true,
+	}
+	if lat != nil && lon != nil {
+		req.Latitude, req.Longitude = *lat, *lon
+	}
+	if taxInclusive != nil {
+		req.TaxInclusive = *taxInclusive
+	}
+	if timestamp != nil {
+		req.Timestamp = *timestamp
+	}
+
+	return req, nil
+}
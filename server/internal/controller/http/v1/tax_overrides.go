@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// TaxOverridesController handles administrative operations
+// on temporary tax overrides such as sales tax holidays.
+type TaxOverridesController struct {
+	overrideService usecase.TaxOverrideService
+	logger          zerolog.Logger
+}
+
+func NewTaxOverridesController(overrideService usecase.TaxOverrideService, logger zerolog.Logger) *TaxOverridesController {
+	l := logger.With().Str("controller", "tax_overrides_controller").Logger()
+	return &TaxOverridesController{
+		overrideService: overrideService,
+		logger:          l,
+	}
+}
+
+// Create godoc
+// @Summary      Create a tax override
+// @Description  Creates a time-bounded override applied to orders placed within [starts_at, ends_at) in the covered jurisdictions of its state ("*" covers all of them, an empty state means the primary one), optionally limited to a category. Without rate the listed components (or the whole tax when none are listed) are exempt; with rate the single listed component is replaced. Takes effect immediately.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.TaxOverride  true  "Override data"
+// @Success      201      {object}  entity.TaxOverride
+// @Failure      400      {object}  response.Response  "Invalid request body, validation failed or unknown state"
+// @Failure      409      {object}  response.Response  "Override with this name already exists"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/tax-overrides [post]
+func (c *TaxOverridesController) Create(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "create").Logger()
+
+	var req dto.TaxOverride
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	err = ctx.Validate(&req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	override, err := c.overrideService.Create(ctx.Request().Context(), req)
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to create tax override")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", override.Id).Str("name", override.Name).Msg("successfully created tax override")
+
+	return response.NewSuccessResponse(ctx, override, http.StatusCreated)
+}
+
+// GetAll godoc
+// @Summary      List tax overrides
+// @Description  Returns the overrides from the admin API and the tax config in the order of their precedence.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}  entity.TaxOverride
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/tax-overrides [get]
+func (c *TaxOverridesController) GetAll(ctx echo.Context) error {
+	return response.NewSuccessResponse(ctx, c.overrideService.GetAll(ctx.Request().Context()), http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete a tax override
+// @Description  Deletes an override created through the admin API. Overrides from the tax config cannot be deleted.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Override ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Override not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/tax-overrides/{id} [delete]
+func (c *TaxOverridesController) Delete(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "delete").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of tax override")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	if err := c.overrideService.Delete(ctx.Request().Context(), id); err != nil {
+		l.Warn().Err(err).Msg("failed to delete tax override")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", id).Msg("successfully deleted tax override")
+
+	return ctx.NoContent(http.StatusNoContent)
+}
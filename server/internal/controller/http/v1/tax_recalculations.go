@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/controller/http/response"
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	"github.com/ryl1k/INT20H-test-task-server/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// TaxRecalculationsController handles administrative recalculations
+// of the tax of stored orders after a rate correction.
+type TaxRecalculationsController struct {
+	recalculationService usecase.TaxRecalculationService
+	logger               zerolog.Logger
+}
+
+func NewTaxRecalculationsController(recalculationService usecase.TaxRecalculationService, logger zerolog.Logger) *TaxRecalculationsController {
+	l := logger.With().Str("controller", "tax_recalculations_controller").Logger()
+	return &TaxRecalculationsController{
+		recalculationService: recalculationService,
+		logger:               l,
+	}
+}
+
+// Preview godoc
+// @Summary      Preview a tax recalculation
+// @Description  Resolves the tax of the orders matching the filter again with the loaded rates and stores the orders whose tax would change, without changing them. Voided and refunded orders are skipped. Only the loaded rates can be previewed, other versions cannot: when expected_rates_version is given it must match the loaded tax data, including the active boundaries and overrides. Tax amounts are compared in cents.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.TaxRecalculation  true  "Orders to recalculate"
+// @Success      201      {object}  entity.TaxRecalculation
+// @Failure      400      {object}  response.Response  "Invalid request body or validation failed"
+// @Failure      409      {object}  response.Response  "Loaded rates differ from the expected version"
+// @Failure      500      {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/tax-recalculations [post]
+func (c *TaxRecalculationsController) Preview(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "preview").Logger()
+
+	var req dto.TaxRecalculation
+	if err := ctx.Bind(&req); err != nil {
+		l.Warn().Err(err).Msg("failed to parse request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		l.Warn().Err(err).Msg("failed to validate request")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	rec, err := c.recalculationService.PreviewRecalculation(ctx.Request().Context(), req)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to preview tax recalculation")
+		return response.NewErrorResponse(ctx, err)
+	}
+	l.Info().Int("id", rec.Id).Int("changes", len(rec.Changes)).Msg("successfully previewed tax recalculation")
+
+	return response.NewSuccessResponse(ctx, rec, http.StatusCreated)
+}
+
+// GetById godoc
+// @Summary      Get tax recalculation by ID
+// @Description  Returns the previewed changes of a tax recalculation and the progress of applying them.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Tax recalculation ID"
+// @Success      200  {object}  entity.TaxRecalculation
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Tax recalculation not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/tax-recalculations/{id} [get]
+func (c *TaxRecalculationsController) GetById(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "get_by_id").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of tax recalculation")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	rec, err := c.recalculationService.GetRecalculation(ctx.Request().Context(), id)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get tax recalculation by id")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(ctx, rec, http.StatusOK)
+}
+
+// Apply godoc
+// @Summary      Apply a tax recalculation
+// @Description  Applies the previewed changes in batches in the background and records them in the order history. Orders changed since the preview, or whose tax no longer comes out as previewed, are left out as conflicts. A recalculation is applied once and only with the rates it was previewed with; poll it by ID for the progress.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Tax recalculation ID"
+// @Success      202  {object}  entity.TaxRecalculation
+// @Failure      400  {object}  response.Response  "Invalid ID format"
+// @Failure      404  {object}  response.Response  "Tax recalculation not found"
+// @Failure      409  {object}  response.Response  "Already applied or loaded rates differ from the preview"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/tax-recalculations/{id}/apply [post]
+func (c *TaxRecalculationsController) Apply(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "apply").Logger()
+
+	id, err := strconv.Atoi(ctx.Param(idParam))
+	if err != nil {
+		l.Warn().Err(err).Msg("failed to parse id of tax recalculation")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	rec, err := c.recalculationService.StartRecalculation(ctx.Request().Context(), id)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to start tax recalculation")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	actor := entity.ActorFromContext(ctx.Request().Context())
+	go c.recalculationService.AsyncApplyRecalculation(rec, actor)
+
+	l.Info().Int("id", rec.Id).Int("changes", len(rec.Changes)).Msg("successfully started tax recalculation")
+
+	return response.NewSuccessResponse(ctx, rec, http.StatusAccepted)
+}
+
+// ResolveOutOfScope godoc
+// @Summary      Retry out-of-scope orders
+// @Description  Resolves the tax of the out-of-scope orders, of the orders whose tax failed to resolve and of the imported orders left pending, matching the filters again with the loaded tax data and boundaries, and stores the ones that now match a jurisdiction as completed with a full breakdown. Pending orders are stored with whatever status their tax resolves to. The filters take the same query params as listing orders, except status. Every resolved order is recorded in its history.
+// @Tags         admin
+// @Produce      json
+// @Param        ids                query     string  false  "Comma-separated order IDs"
+// @Param        import_id          query     string  false  "Import job ID"
+// @Param        state              query     string  false  "Code of the state the order was taxed in"
+// @Param        category           query     string  false  "Product category"
+// @Param        customer_ref       query     string  false  "Customer reference"
+// @Param        warning            query     entity.OrderWarning  false  "Order warning"
+// @Param        geocoding_method   query     entity.GeocodingMethod  false  "How the order location was obtained"
+// @Param        total_amount_min   query     number  false  "Minimum total amount"
+// @Param        total_amount_max   query     number  false  "Maximum total amount"
+// @Param        from_date          query     string  false  "Start date (ISO8601)"        example(2023-01-01T00:00:00Z)
+// @Param        to_date            query     string  false  "End date (ISO8601)"          example(2023-12-31T23:59:59Z)
+// @Success      200  {object}  entity.OutOfScopeResolution
+// @Failure      400  {object}  response.Response  "Invalid filters"
+// @Failure      500  {object}  response.Response  "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /v1/admin/orders/out-of-scope/resolve [post]
+func (c *TaxRecalculationsController) ResolveOutOfScope(ctx echo.Context) error {
+	l := c.logger.With().Str("method", "resolve_out_of_scope").Logger()
+
+	var filter dto.OrderFilters
+	if err := populateOrderFilters(ctx, &filter); err != nil {
+		l.Warn().Err(err).Msg("invalid query filter")
+		return response.NewErrorResponse(ctx, entity.ErrBadRequest)
+	}
+
+	resolution, err := c.recalculationService.ResolveOutOfScope(ctx.Request().Context(), filter)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to resolve out-of-scope orders")
+		return response.NewErrorResponse(ctx, err)
+	}
+
+	l.Info().
+		Int("checked", resolution.Checked).
+		Int("resolved", resolution.Resolved).
+		Msg("successfully resolved out-of-scope orders")
+
+	return response.NewSuccessResponse(ctx, resolution, http.StatusOK)
+}
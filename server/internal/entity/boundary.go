@@ -0,0 +1,95 @@
+package entity
+
+import "time"
+
+type BoundarySetStatus string
+
+// BoundarySet represents an uploaded collection of jurisdiction boundaries
+// of a single state. A set is staged after upload and becomes active once
+// it is validated and explicitly activated; the previously active set
+// of the same state is superseded.
+type BoundarySet struct {
+	Id     int               `json:"id"`
+	Status BoundarySetStatus `json:"status"`
+
+	// State is the code of the state the boundaries belong to.
+	// An empty state means the primary state.
+	State string `json:"state"`
+
+	FeatureCount int `json:"feature_count"`
+
+	Report BoundaryValidationReport `json:"report"`
+	Diff   BoundaryDiff             `json:"diff"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+
+	// GeoJSON holds the uploaded features. It is not exposed through the API.
+	GeoJSON *GeoJSON `json:"-"`
+}
+
+// BoundaryValidationReport describes the result of validating
+// a boundary set against the jurisdiction tax configuration.
+// A set is valid when it contains no errors; overlaps, gaps and
+// uncovered jurisdictions are reported for review only.
+type BoundaryValidationReport struct {
+	Valid bool `json:"valid"`
+
+	Errors []BoundaryIssue `json:"errors"`
+
+	// UncoveredJurisdictions lists tax config entries
+	// that are not referenced by any feature. Areas without
+	// a feature are reported as Gaps.
+	UncoveredJurisdictions []string `json:"uncovered_jurisdictions"`
+
+	// DuplicateNames lists names shared by more than one feature,
+	// which usually indicates a copy/paste error in the boundary file.
+	DuplicateNames []string `json:"duplicate_names"`
+
+	// Overlaps lists feature pairs whose interiors intersect.
+	Overlaps []BoundaryOverlap `json:"overlaps"`
+
+	// Gaps lists areas inside the state outline covered by no feature.
+	// It is empty when no outline is configured for the state.
+	Gaps []BoundaryGap `json:"gaps"`
+}
+
+// Consistent reports whether the boundary set is valid and every
+// tax config entry maps to exactly one feature.
+func (r BoundaryValidationReport) Consistent() bool {
+	return r.Valid && len(r.UncoveredJurisdictions) == 0 && len(r.DuplicateNames) == 0
+}
+
+// BoundaryIssue describes a single problem found in a feature.
+type BoundaryIssue struct {
+	FeatureIndex int    `json:"feature_index"`
+	Name         string `json:"name"`
+	Message      string `json:"message"`
+}
+
+// BoundaryOverlap describes two features sharing interior area.
+type BoundaryOverlap struct {
+	First       string `json:"first"`
+	FirstIndex  int    `json:"first_index"`
+	Second      string `json:"second"`
+	SecondIndex int    `json:"second_index"`
+}
+
+// BoundaryGap describes an area inside the state outline covered by
+// no feature, by a feature bordering it and a location inside it.
+// A FeatureIndex of -1 means a gap bordering the state outline.
+type BoundaryGap struct {
+	Name         string  `json:"name,omitempty"`
+	FeatureIndex int     `json:"feature_index"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+}
+
+// BoundaryDiff summarizes changes of a boundary set
+// compared to the currently active one, keyed by feature name.
+type BoundaryDiff struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Changed   []string `json:"changed"`
+	Unchanged int      `json:"unchanged"`
+}
@@ -12,4 +12,24 @@ var (
 	ErrInvalidFileFormat                   = errors.New("unsupported file format")
 	ErrInvalidOrEmptyPaginationQueryParams = errors.New("invalid or empty pagination query params")
 	ErrOrderNotFound                       = errors.New("order not found")
+	ErrRefundExceedsOrder                  = errors.New("refund exceeds the amount left on the order")
+	ErrRefundLineNotFound                  = errors.New("order has no such component or item line to refund")
+	ErrOrderNotRefundable                  = errors.New("only completed or partially refunded orders can be refunded")
+	ErrOrderVersionConflict                = errors.New("order was changed by another request")
+	ErrOrderNotEditable                    = errors.New("order can no longer be changed")
+	ErrInvalidStatusTransition             = errors.New("order cannot move to this status")
+	ErrDeletionScopeRequired               = errors.New("deleting or restoring orders requires order ids, an import job or a customer")
+	ErrInvalidConfirmationToken            = errors.New("confirmation token is invalid or expired")
+	ErrTaxRecalculationNotFound            = errors.New("tax recalculation not found")
+	ErrTaxRecalculationNotPending          = errors.New("tax recalculation was already applied")
+	ErrRatesVersionMismatch                = errors.New("loaded tax rates differ from the requested version")
+	ErrExchangeRateNotFound                = errors.New("no exchange rate for the currency at the order date")
+	ErrBoundarySetNotFound                 = errors.New("boundary set not found")
+	ErrInvalidBoundarySet                  = errors.New("boundary set failed validation")
+	ErrExemptionCertificateAlreadyExists   = errors.New("exemption certificate already exists")
+	ErrCustomerNotFound                    = errors.New("customer not found")
+	ErrCustomerAlreadyExists               = errors.New("customer with this external reference already exists")
+	ErrCustomerRefImmutable                = errors.New("external reference of a customer cannot be changed")
+	ErrTaxOverrideNotFound                 = errors.New("tax override not found")
+	ErrTaxOverrideAlreadyExists            = errors.New("tax override with this name already exists")
 )
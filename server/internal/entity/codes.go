@@ -18,4 +18,5 @@ const (
 	ForbiddenCode
 	NotFoundCode
 	InternalErrorCode
+	ConflictCode
 )
@@ -1,12 +1,131 @@
 package entity
 
 const (
-	OrderStatusCompleted  OrderStatus = "completed"
-	OrderStatusOutOfScope OrderStatus = "out_of_scope"
+	OrderStatusPending             OrderStatus = "pending"
+	OrderStatusCompleted           OrderStatus = "completed"
+	OrderStatusOutOfScope          OrderStatus = "out_of_scope"
+	OrderStatusFailedTaxResolution OrderStatus = "failed_tax_resolution"
+	OrderStatusPartiallyRefunded   OrderStatus = "partially_refunded"
+	OrderStatusRefunded            OrderStatus = "refunded"
+	OrderStatusVoided              OrderStatus = "voided"
+)
+
+const (
+	OrderEventCreated  OrderEventType = "created"
+	OrderEventImported OrderEventType = "imported"
+	OrderEventUpdated  OrderEventType = "updated"
+	OrderEventVoided   OrderEventType = "voided"
+	OrderEventRefunded OrderEventType = "refunded"
+	OrderEventDeleted  OrderEventType = "deleted"
+	OrderEventRestored OrderEventType = "restored"
+
+	OrderEventRecalculated OrderEventType = "recalculated"
+	OrderEventResolved     OrderEventType = "resolved"
+)
+
+const (
+	OrderEventSourceApi    OrderEventSource = "api"
+	OrderEventSourceImport OrderEventSource = "import"
+
+	OrderEventSourceRecalculation OrderEventSource = "recalculation"
+)
+
+const (
+	TaxRecalculationStatusPreviewed TaxRecalculationStatus = "previewed"
+	TaxRecalculationStatusApplying  TaxRecalculationStatus = "applying"
+	TaxRecalculationStatusApplied   TaxRecalculationStatus = "applied"
+	TaxRecalculationStatusFailed    TaxRecalculationStatus = "failed"
+)
+
+const (
+	OrderComponentSubtotal OrderComponent = "subtotal"
+	OrderComponentShipping OrderComponent = "shipping"
+	OrderComponentHandling OrderComponent = "handling"
+	OrderComponentDiscount OrderComponent = "discount"
+)
+
+const (
+	BoundarySetStatusStaged     BoundarySetStatus = "staged"
+	BoundarySetStatusActive     BoundarySetStatus = "active"
+	BoundarySetStatusSuperseded BoundarySetStatus = "superseded"
+)
+
+const (
+	ExchangeRateSourceFile ExchangeRateSource = "file"
+	ExchangeRateSourceApi  ExchangeRateSource = "api"
+)
+
+const (
+	CustomerExemptionStatusNone    CustomerExemptionStatus = "none"
+	CustomerExemptionStatusActive  CustomerExemptionStatus = "active"
+	CustomerExemptionStatusExpired CustomerExemptionStatus = "expired"
+)
+
+const (
+	TaxLayerLevelState   TaxLayerLevel = "state"
+	TaxLayerLevelCounty  TaxLayerLevel = "county"
+	TaxLayerLevelCity    TaxLayerLevel = "city"
+	TaxLayerLevelSpecial TaxLayerLevel = "special"
+)
+
+const (
+	TaxTreatmentTaxable   TaxTreatment = "taxable"
+	TaxTreatmentExempt    TaxTreatment = "exempt"
+	TaxTreatmentReduced   TaxTreatment = "reduced"
+	TaxTreatmentStateOnly TaxTreatment = "state_only"
+	TaxTreatmentLocalOnly TaxTreatment = "local_only"
+)
+
+const (
+	ExemptionTypeResale     ExemptionType = "resale"
+	ExemptionTypeNonprofit  ExemptionType = "nonprofit"
+	ExemptionTypeGovernment ExemptionType = "government"
+	ExemptionTypeOther      ExemptionType = "other"
+)
+
+const (
+	TaxOverrideSourceConfig TaxOverrideSource = "config"
+	TaxOverrideSourceApi    TaxOverrideSource = "api"
+)
+
+const (
+	ComputationStepJurisdiction ComputationStepKind = "jurisdiction"
+	ComputationStepLayer        ComputationStepKind = "layer"
+	ComputationStepOverride     ComputationStepKind = "override"
+	ComputationStepTaxability   ComputationStepKind = "taxability"
+	ComputationStepExemption    ComputationStepKind = "exemption"
+	ComputationStepItems        ComputationStepKind = "items"
+)
+
+const (
+	AmbiguityPolicyFirstWins   AmbiguityPolicy = "first_wins"
+	AmbiguityPolicyHighestRate AmbiguityPolicy = "highest_rate"
+	AmbiguityPolicyFail        AmbiguityPolicy = "fail"
+)
+
+const (
+	OrderWarningAmbiguousJurisdiction OrderWarning = "ambiguous_jurisdiction"
+)
+
+const (
+	GeocodingMethodCoordinates     GeocodingMethod = "coordinates"
+	GeocodingMethodZipJurisdiction GeocodingMethod = "zip_jurisdiction"
+	GeocodingMethodZipCentroid     GeocodingMethod = "zip_centroid"
+	GeocodingMethodUnresolved      GeocodingMethod = "unresolved"
+)
+
+const (
+	GeocodingPrecisionExact GeocodingPrecision = "exact"
+	GeocodingPrecisionZip4  GeocodingPrecision = "zip4"
+	GeocodingPrecisionZip5  GeocodingPrecision = "zip5"
+	GeocodingPrecisionNone  GeocodingPrecision = "none"
 )
 
 const (
 	UnknownName = "Unknown"
+
+	// AllJurisdictions matches every reporting code.
+	AllJurisdictions = "*"
 )
 
 const (
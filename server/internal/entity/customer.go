@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+type CustomerExemptionStatus string
+
+// Customer identifies who placed an order by an external reference
+// (e.g. an id in the sales channel).
+type Customer struct {
+	Id          int    `json:"id"`
+	ExternalRef string `json:"external_ref"`
+	Name        string `json:"name"`
+
+	// Address, Zip and the coordinates are the default location
+	// of orders placed by the customer without one.
+	Address   string   `json:"address"`
+	Zip       string   `json:"zip"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+
+	// ExemptionStatus tells whether the customer holds an exemption
+	// certificate valid now. It is derived from the certificates.
+	ExemptionStatus CustomerExemptionStatus `json:"exemption_status"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HasLocation reports whether the customer has a default location.
+func (c Customer) HasLocation() bool {
+	return c.Latitude != nil && c.Longitude != nil || c.Address != "" || c.Zip != ""
+}
+
+type CustomerList struct {
+	Customers []Customer `json:"customers"`
+	Total     int        `json:"total"`
+}
+
+// CustomerTotals sums up the orders placed by a customer. Amounts
+// are net of refunds and leave out voided orders, which only count.
+type CustomerTotals struct {
+	CustomerId     int        `json:"customer_id"`
+	OrderCount     int        `json:"order_count"`
+	VoidedCount    int        `json:"voided_count"`
+	NetTotalAmount float64    `json:"net_total_amount"`
+	NetTaxAmount   float64    `json:"net_tax_amount"`
+	FirstOrderAt   *time.Time `json:"first_order_at"`
+	LastOrderAt    *time.Time `json:"last_order_at"`
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type ExchangeRateSource string
+
+// ReportingCurrency is the currency taxes are computed and reported in.
+const ReportingCurrency = "USD"
+
+// ExchangeRate is the value in USD of one unit of Currency, valid
+// from Date until the next rate of the currency.
+type ExchangeRate struct {
+	Currency string             `json:"currency"`
+	Date     time.Time          `json:"date"`
+	Rate     float64            `json:"rate"`
+	Source   ExchangeRateSource `json:"source"`
+}
+
+// NormalizeCurrency upper-cases a currency code. It returns false
+// unless the code is made of three letters, as ISO 4217 codes are.
+// An empty code is the reporting currency.
+func NormalizeCurrency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return ReportingCurrency, true
+	}
+	if len(code) != 3 {
+		return "", false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", false
+		}
+	}
+	return code, true
+}
+
+// ToReporting converts an amount of the currency to USD, rounded to cents.
+func (r ExchangeRate) ToReporting(amount float64) float64 {
+	return roundCents(amount * r.Rate)
+}
+
+// FromReporting converts an amount in USD to the currency, rounded to cents.
+func (r ExchangeRate) FromReporting(amount float64) float64 {
+	return roundCents(amount / r.Rate)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+type ExemptionType string
+
+// ExemptionCertificate is a tax exemption presented by a customer.
+// It covers the listed reporting codes of its state ("*" covers every
+// jurisdiction of the state, an empty state means the primary one)
+// within [ValidFrom, ValidTo). Only the listed components are exempted;
+// an empty list exempts the order completely.
+type ExemptionCertificate struct {
+	Id          int    `json:"id"`
+	CustomerId  int    `json:"customer_id"`
+	CustomerRef string `json:"customer_ref"`
+
+	Number string        `json:"number"`
+	Type   ExemptionType `json:"type"`
+	State  string        `json:"state,omitempty"`
+
+	Jurisdictions []string        `json:"jurisdictions"`
+	Components    []TaxLayerLevel `json:"components"`
+
+	ValidFrom time.Time  `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Covers reports whether the certificate applies to an order taxed
+// in the given state with the given reporting code at the given time.
+// Empty states on either side stand for the primary state.
+func (c ExemptionCertificate) Covers(state, primaryState, reportingCode string, at time.Time) bool {
+	if cmp.Or(c.State, primaryState) != cmp.Or(state, primaryState) {
+		return false
+	}
+	if at.Before(c.ValidFrom) {
+		return false
+	}
+	if c.ValidTo != nil && !at.Before(*c.ValidTo) {
+		return false
+	}
+	return slices.Contains(c.Jurisdictions, AllJurisdictions) || slices.Contains(c.Jurisdictions, reportingCode)
+}
+
+// Apply zeroes the exempted components of the breakdown.
+func (c ExemptionCertificate) Apply(b JurisdictionTaxBreakdown) JurisdictionTaxBreakdown {
+	if len(c.Components) == 0 {
+		return JurisdictionTaxBreakdown{}
+	}
+
+	for _, level := range c.Components {
+		b.AddLayerRate(level, -b.Rate(level))
+	}
+	return b
+}
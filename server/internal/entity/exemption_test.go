@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExemptionCertificate_Covers(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name  string
+		cert  ExemptionCertificate
+		state string
+		code  string
+		at    time.Time
+		want  bool
+	}{
+		{name: "primary_state", cert: ExemptionCertificate{Jurisdictions: []string{"0001"}}, state: "NY", code: "0001", at: from, want: true},
+		{name: "primary_state_given", cert: ExemptionCertificate{State: "NY", Jurisdictions: []string{"0001"}}, state: "", code: "0001", at: from, want: true},
+		{name: "same_code_other_state", cert: ExemptionCertificate{State: "NJ", Jurisdictions: []string{"0001"}}, state: "NY", code: "0001", at: from},
+		{name: "primary_certificate_other_state", cert: ExemptionCertificate{Jurisdictions: []string{AllJurisdictions}}, state: "NJ", code: "0001", at: from},
+		{name: "other_state", cert: ExemptionCertificate{State: "NJ", Jurisdictions: []string{AllJurisdictions}}, state: "NJ", code: "0009", at: from, want: true},
+		{name: "other_jurisdiction", cert: ExemptionCertificate{Jurisdictions: []string{"0002"}}, state: "NY", code: "0001", at: from},
+		{name: "before_start", cert: ExemptionCertificate{Jurisdictions: []string{AllJurisdictions}}, state: "NY", code: "0001", at: from.Add(-time.Second)},
+		{name: "before_end", cert: ExemptionCertificate{Jurisdictions: []string{AllJurisdictions}}, state: "NY", code: "0001", at: to.Add(-time.Second), want: true},
+		{name: "end_is_exclusive", cert: ExemptionCertificate{Jurisdictions: []string{AllJurisdictions}}, state: "NY", code: "0001", at: to},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := tc.cert
+			c.ValidFrom, c.ValidTo = from, &to
+
+			if got := c.Covers(tc.state, "NY", tc.code, tc.at); got != tc.want {
+				t.Errorf("Covers(%q, %q, %v) = %v, want %v", tc.state, tc.code, tc.at, got, tc.want)
+			}
+		})
+	}
+}
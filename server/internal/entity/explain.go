@@ -0,0 +1,92 @@
+package entity
+
+type ComputationStepKind string
+
+// TaxExplanation describes how the tax of an order location was derived:
+// which boundary features were considered, which one won,
+// which config entry and layers matched, and every step
+// that changed the rates on the way to the final tax amount.
+type TaxExplanation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+
+	// Geocode describes how the location was obtained.
+	Geocode *Geocode `json:"geocode,omitempty"`
+
+	// State is the code of the state dataset the winner belongs to.
+	State string `json:"state,omitempty"`
+
+	// Candidates are the features whose bounding box contains the point,
+	// ordered by feature index.
+	Candidates []FeatureMatch `json:"candidates"`
+
+	// Winner is the containing feature picked by the ambiguity policy,
+	// the owner of a shared edge the point lies on, or the nearest feature
+	// the point was snapped to.
+	Winner *FeatureMatch `json:"winner,omitempty"`
+
+	BoundaryResolved bool `json:"boundary_resolved"`
+
+	// Ambiguous is set when several overlapping features contain the point.
+	Ambiguous bool `json:"ambiguous"`
+
+	// Jurisdiction is the tax config entry matched by the winner name.
+	Jurisdiction *JurisdictionTax `json:"jurisdiction,omitempty"`
+
+	Layers []LayerMatch `json:"layers"`
+
+	Override *TaxOverride `json:"override,omitempty"`
+
+	Steps []ComputationStep `json:"steps"`
+
+	Subtotal      float64 `json:"subtotal"`
+	CompositeRate float64 `json:"composite_rate"`
+	TaxAmount     float64 `json:"tax_amount"`
+
+	// Components is the tax of every order component.
+	Components []OrderComponentTax `json:"components,omitempty"`
+}
+
+// FeatureMatch is a boundary feature considered for a point.
+type FeatureMatch struct {
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Contains bool   `json:"contains"`
+
+	// Distance is the distance in meters to a feature
+	// the point was snapped to.
+	Distance float64 `json:"distance,omitempty"`
+}
+
+// LayerMatch describes the resolution of an additional tax layer.
+type LayerMatch struct {
+	Layer      string         `json:"layer"`
+	Level      TaxLayerLevel  `json:"level"`
+	Candidates []FeatureMatch `json:"candidates"`
+	Winner     *FeatureMatch  `json:"winner,omitempty"`
+	Rate       float64        `json:"rate"`
+	Applied    bool           `json:"applied"`
+}
+
+// ComputationStep is the state of the rates after one step of the calculation.
+type ComputationStep struct {
+	Kind          ComputationStepKind      `json:"kind"`
+	Name          string                   `json:"name"`
+	Breakdown     JurisdictionTaxBreakdown `json:"breakdown"`
+	CompositeRate float64                  `json:"composite_rate"`
+}
+
+// AddStep records the rates after a calculation step.
+// It is a no-op on a nil explanation, so callers can
+// record steps unconditionally.
+func (e *TaxExplanation) AddStep(kind ComputationStepKind, name string, b JurisdictionTaxBreakdown, compositeRate float64) {
+	if e == nil {
+		return
+	}
+	e.Steps = append(e.Steps, ComputationStep{
+		Kind:          kind,
+		Name:          name,
+		Breakdown:     b,
+		CompositeRate: compositeRate,
+	})
+}
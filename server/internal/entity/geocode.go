@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+type GeocodingMethod string
+type GeocodingPrecision string
+
+// zipPattern matches a ZIP or ZIP+4 code in a free-form address line.
+var zipPattern = regexp.MustCompile(`\b(\d{5})(?:-?(\d{4}))?\b`)
+
+// Geocode is the location resolved for an order given by address
+// instead of coordinates. Centroid lookups fill the coordinates;
+// ZIP-to-jurisdiction lookups fill the jurisdiction name directly.
+type Geocode struct {
+	Method    GeocodingMethod    `json:"method"`
+	Precision GeocodingPrecision `json:"precision"`
+	Zip       string             `json:"zip"`
+
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+
+	// State and Jurisdiction are the state dataset and its
+	// tax config entry the ZIP code maps to.
+	State        string `json:"state,omitempty"`
+	Jurisdiction string `json:"jurisdiction,omitempty"`
+}
+
+// ZipCentroid is an entry of the ZIP centroid dataset.
+type ZipCentroid struct {
+	Zip       string
+	Latitude  float64
+	Longitude float64
+}
+
+// ZipJurisdiction is an entry of the ZIP-to-jurisdiction dataset.
+// An empty state means the primary state.
+type ZipJurisdiction struct {
+	Zip          string
+	State        string
+	Jurisdiction string
+}
+
+// NormalizeZip returns the 5-digit ZIP code and the optional
+// 4-digit extension of a ZIP or ZIP+4 code, e.g. "10001-1234".
+func NormalizeZip(zip string) (zip5, plus4 string, ok bool) {
+	m := zipPattern.FindStringSubmatch(strings.TrimSpace(zip))
+	if m == nil || len(m[0]) != len(strings.TrimSpace(zip)) {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// ExtractZip returns the last ZIP or ZIP+4 code found in an address line,
+// which is where US addresses put it.
+func ExtractZip(address string) (string, bool) {
+	matches := zipPattern.FindAllString(address, -1)
+	if len(matches) == 0 {
+		return "", false
+	}
+	return matches[len(matches)-1], true
+}
@@ -17,7 +17,7 @@ type GeoJSON struct {
 // This helper ensures consistent geometry handling
 // for spatial operations such as point-in-polygon checks.
 func GetMultiPolygon(f *geojson.Feature) orb.MultiPolygon {
-	if f.Geometry == nil {
+	if f == nil || f.Geometry == nil {
 		return nil
 	}
 
@@ -31,3 +31,17 @@ func GetMultiPolygon(f *geojson.Feature) orb.MultiPolygon {
 
 	return nil
 }
+
+// MultiPolygon merges the polygonal geometries of every feature
+// into one. Features of other geometry types are skipped.
+func (g *GeoJSON) MultiPolygon() orb.MultiPolygon {
+	if g == nil {
+		return nil
+	}
+
+	var mp orb.MultiPolygon
+	for _, f := range g.Features {
+		mp = append(mp, GetMultiPolygon(f)...)
+	}
+	return mp
+}
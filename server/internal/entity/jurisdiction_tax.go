@@ -8,6 +8,50 @@ type JurisdictionTax struct {
 	Breakdown     JurisdictionTaxBreakdown `json:"breakdown"`
 	Names         []string                 `json:"names"`
 	Code          string                   `json:"code"`
+
+	// Taxability maps product categories to the rule applied to them.
+	// Categories without a rule are taxed at the full composite rate.
+	Taxability map[string]TaxabilityRule `json:"taxability,omitempty"`
+
+	// ComponentTaxability maps shipping, handling and discount to the rule
+	// applied to them on top of the rates of the merchandise.
+	// Components without a rule follow the merchandise.
+	ComponentTaxability map[OrderComponent]TaxabilityRule `json:"component_taxability,omitempty"`
+}
+
+// LocationTax is the tax resolved for a location together with
+// how the location was matched to a jurisdiction boundary.
+type LocationTax struct {
+	JurisdictionTax
+
+	// State is the code of the state dataset the location was resolved in.
+	State string
+
+	// BoundaryResolved is set when the location lies on an edge shared
+	// by several jurisdictions or was snapped to the nearest jurisdiction
+	// within the configured tolerance.
+	BoundaryResolved bool
+
+	// BoundaryDistance is the distance in meters from a snapped location
+	// to the boundary of its jurisdiction.
+	BoundaryDistance float64
+
+	// Ambiguous is set when the location lies inside several overlapping
+	// jurisdictions. Matches lists the names of all of them by feature index.
+	Ambiguous bool
+	Matches   []string
+}
+
+// AmbiguityPolicy decides which jurisdiction wins when a location
+// lies inside several overlapping jurisdiction boundaries.
+type AmbiguityPolicy string
+
+func (p AmbiguityPolicy) Valid() bool {
+	switch p {
+	case AmbiguityPolicyFirstWins, AmbiguityPolicyHighestRate, AmbiguityPolicyFail:
+		return true
+	}
+	return false
 }
 
 type JurisdictionTaxBreakdown struct {
@@ -16,3 +60,109 @@ type JurisdictionTaxBreakdown struct {
 	City    float64 `json:"city"`
 	Special float64 `json:"special"`
 }
+
+// Total returns the sum of all components.
+func (b JurisdictionTaxBreakdown) Total() float64 {
+	return b.State + b.County + b.City + b.Special
+}
+
+type TaxLayerLevel string
+
+// TaxLayer describes an additional boundary layer resolved independently
+// of the primary jurisdiction boundaries. Each feature of the layer that
+// contains the order location contributes its rate to the breakdown
+// component matching the layer level.
+type TaxLayer struct {
+	Name            string               `json:"name"`
+	Level           TaxLayerLevel        `json:"level"`
+	GeoJSONFilePath string               `json:"geojson_file_path"`
+	PropertyKey     string               `json:"property_key"`
+	Rates           map[string]LayerRate `json:"rates"`
+	GeoJSON         *GeoJSON             `json:"-"`
+}
+
+// StateDataset describes the tax data of a state loaded side by side
+// with the primary one: its boundary file, the feature property holding
+// the jurisdiction name, its rate table, in the jurisdictions.json format,
+// its optional tax layers and its optional outline.
+type StateDataset struct {
+	Code                  string `json:"code"`
+	GeoJSONFilePath       string `json:"geojson_file_path"`
+	PropertyKey           string `json:"property_key"`
+	JurisdictionsFilePath string `json:"jurisdictions_file_path"`
+	TaxLayersFilePath     string `json:"tax_layers_file_path"`
+	OutlineFilePath       string `json:"outline_file_path"`
+
+	GeoJSON       *GeoJSON                   `json:"-"`
+	Outline       *GeoJSON                   `json:"-"`
+	Jurisdictions map[string]JurisdictionTax `json:"-"`
+	TaxLayers     []TaxLayer                 `json:"-"`
+}
+
+// LayerRate is the rate of a single feature of a tax layer.
+// Name is the jurisdiction name appended to the order jurisdictions.
+type LayerRate struct {
+	Rate float64 `json:"rate"`
+	Name string  `json:"name"`
+}
+
+// Valid reports whether the level maps to a breakdown component.
+func (l TaxLayerLevel) Valid() bool {
+	switch l {
+	case TaxLayerLevelState, TaxLayerLevelCounty, TaxLayerLevelCity, TaxLayerLevelSpecial:
+		return true
+	}
+	return false
+}
+
+// SetRate replaces the component matching the layer level.
+// It returns false for an unknown level.
+func (b *JurisdictionTaxBreakdown) SetRate(level TaxLayerLevel, rate float64) bool {
+	switch level {
+	case TaxLayerLevelState:
+		b.State = rate
+	case TaxLayerLevelCounty:
+		b.County = rate
+	case TaxLayerLevelCity:
+		b.City = rate
+	case TaxLayerLevelSpecial:
+		b.Special = rate
+	default:
+		return false
+	}
+	return true
+}
+
+// Rate returns the component matching the layer level.
+func (b JurisdictionTaxBreakdown) Rate(level TaxLayerLevel) float64 {
+	switch level {
+	case TaxLayerLevelState:
+		return b.State
+	case TaxLayerLevelCounty:
+		return b.County
+	case TaxLayerLevelCity:
+		return b.City
+	case TaxLayerLevelSpecial:
+		return b.Special
+	default:
+		return 0
+	}
+}
+
+// AddLayerRate adds the rate to the component matching the layer level.
+// It reports false for unknown levels.
+func (b *JurisdictionTaxBreakdown) AddLayerRate(level TaxLayerLevel, rate float64) bool {
+	switch level {
+	case TaxLayerLevelState:
+		b.State += rate
+	case TaxLayerLevelCounty:
+		b.County += rate
+	case TaxLayerLevelCity:
+		b.City += rate
+	case TaxLayerLevelSpecial:
+		b.Special += rate
+	default:
+		return false
+	}
+	return true
+}
@@ -4,26 +4,158 @@ import "time"
 
 // could use decimal.Decimal for more precision
 type OrderStatus string
+
+// OrderWarning flags an order whose tax was resolved from questionable data.
+type OrderWarning string
+
 type Order struct {
 	Id        int     `json:"id"`
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 
+	// Zip is the ZIP code of the order address, if given.
+	// GeocodingMethod and GeocodingPrecision describe how the location
+	// was obtained: from the given coordinates or from the ZIP code.
+	Zip                string             `json:"zip"`
+	GeocodingMethod    GeocodingMethod    `json:"geocoding_method"`
+	GeocodingPrecision GeocodingPrecision `json:"geocoding_precision"`
+
+	// Subtotal is the merchandise amount. Shipping and handling are
+	// charged on top of it and Discount is taken off it.
+	Subtotal float64 `json:"subtotal"`
+	Shipping float64 `json:"shipping"`
+	Handling float64 `json:"handling"`
+	Discount float64 `json:"discount"`
+
+	// TotalAmount is the grand total: the subtotal with shipping and
+	// handling, less the discount, plus TaxAmount, the tax of all components.
 	TotalAmount float64 `json:"total_amount"`
 	TaxAmount   float64 `json:"tax_amount"`
 
+	// TaxInclusive tells the order was reported with gross amounts.
+	// Its net amounts and tax are backed out of them and rounded to cents,
+	// so they are stored like those of tax-exclusive orders.
+	TaxInclusive bool `json:"tax_inclusive"`
+
+	// RefundedAmount and RefundedTaxAmount sum the pre-tax amount and the
+	// tax of all refunds. NetTotalAmount and NetTaxAmount are the total
+	// and the tax left on the order after them.
+	RefundedAmount    float64 `json:"refunded_amount"`
+	RefundedTaxAmount float64 `json:"refunded_tax_amount"`
+	NetTotalAmount    float64 `json:"net_total_amount"`
+	NetTaxAmount      float64 `json:"net_tax_amount"`
+
+	// Refunds are the refunds of the order, only loaded for a single order.
+	Refunds []OrderRefund `json:"refunds,omitempty"`
+
+	// Components holds the taxable base and tax of every component.
+	Components []OrderComponentTax `json:"components"`
+
+	// Items are the order lines. The subtotal of an order with items
+	// is the sum of their amounts and its tax the sum of their taxes.
+	// Items are only loaded for a single order.
+	Items []OrderItem `json:"items,omitempty"`
+
+	// Category is the product category used to pick the taxability rule.
+	Category string `json:"category"`
+
+	// CustomerRef references the customer, and CustomerId links
+	// the order to it when the customer is registered.
+	CustomerRef string `json:"customer_ref"`
+	CustomerId  *int   `json:"customer_id,omitempty"`
+
+	// ExemptionCertificate is the number of the certificate
+	// applied to the order, if any.
+	ExemptionCertificate string `json:"exemption_certificate"`
+
+	// TaxOverride is the name of the temporary override,
+	// e.g. a sales tax holiday, applied to the order, if any.
+	TaxOverride string `json:"tax_override"`
+
 	CompositeTaxRate float64          `json:"composite_tax_rate"`
 	Breakdown        TaxRateBreakdown `json:"breakdown"`
 
 	Jurisdictions []string `json:"jurisdictions"`
 	ReportingCode string   `json:"reporting_code"`
 
+	// State is the code of the state the order was taxed in.
+	// It is empty for orders outside of every configured state.
+	State string `json:"state"`
+
 	Status OrderStatus `json:"status"`
 
+	// StatusTransitions records every change of the status,
+	// starting from pending when the order was created.
+	StatusTransitions []StatusTransition `json:"status_transitions"`
+
+	// BoundaryResolved flags orders whose location lay on a shared
+	// jurisdiction edge or was snapped to the nearest jurisdiction.
+	// BoundaryDistance is the snapping distance in meters.
+	BoundaryResolved bool    `json:"boundary_resolved"`
+	BoundaryDistance float64 `json:"boundary_distance"`
+
+	// Warning is set when the order location matched several overlapping
+	// jurisdictions, listed in AmbiguousJurisdictions.
+	Warning                OrderWarning `json:"warning"`
+	AmbiguousJurisdictions []string     `json:"ambiguous_jurisdictions,omitempty"`
+
+	// Explain describes how the tax was derived.
+	// It is only stored when requested on order creation.
+	Explain *TaxExplanation `json:"explain,omitempty"`
+
+	// Currency is the ISO 4217 code of the currency the order was placed in.
+	// All other amounts are in USD, converted at ExchangeRate, the value
+	// in USD of one unit of the currency at the order date. Original holds
+	// the amounts as given, only for orders placed in another currency.
+	Currency     string        `json:"currency"`
+	ExchangeRate float64       `json:"exchange_rate"`
+	Original     *OrderAmounts `json:"original,omitempty"`
+
+	// Metadata and Tags are free-form labels of the order, such as its
+	// sales channel or campaign, kept for reconciliation. They take no part
+	// in the tax calculation.
+	Metadata map[string]string `json:"metadata"`
+	Tags     []string          `json:"tags"`
+
+	// ImportId identifies the import job of imported orders.
+	ImportId string `json:"import_id,omitempty"`
+
+	// Version is incremented by every change of the order
+	// and guards updates against concurrent ones.
+	Version int `json:"version"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Editable reports whether the order can still be updated or voided.
+// Voided orders and orders with refunds are final.
+func (o Order) Editable() bool {
+	switch o.Status {
+	case OrderStatusPending, OrderStatusCompleted, OrderStatusOutOfScope, OrderStatusFailedTaxResolution:
+		return true
+	}
+	return false
+}
+
+// StatusTransition is a change of the status of an order.
+type StatusTransition struct {
+	From OrderStatus `json:"from"`
+	To   OrderStatus `json:"to"`
+	At   time.Time   `json:"at"`
+}
+
+// OrderAmounts are the amounts of an order in the currency it was placed in.
+// TotalAmount and TaxAmount are converted back from USD and rounded to cents.
+type OrderAmounts struct {
+	Subtotal    float64 `json:"subtotal"`
+	Shipping    float64 `json:"shipping"`
+	Handling    float64 `json:"handling"`
+	Discount    float64 `json:"discount"`
+	TotalAmount float64 `json:"total_amount"`
+	TaxAmount   float64 `json:"tax_amount"`
+}
+
 type TaxRateBreakdown struct {
 	StateRate   float64 `json:"state_rate"`
 	CountyRate  float64 `json:"county_rate"`
@@ -0,0 +1,95 @@
+package entity
+
+import "math"
+
+// OrderComponent is a part of the order amount taxed on its own:
+// the merchandise subtotal, shipping, handling or a discount.
+type OrderComponent string
+
+// Valid reports whether the component can carry a taxability rule.
+// The subtotal is taxed by the rule of the order category instead.
+func (c OrderComponent) Valid() bool {
+	switch c {
+	case OrderComponentShipping, OrderComponentHandling, OrderComponentDiscount:
+		return true
+	}
+	return false
+}
+
+// OrderComponentTax is the tax of one component of an order.
+// Discounts have a negative amount, so their tax reduces the order tax.
+type OrderComponentTax struct {
+	Component OrderComponent `json:"component"`
+	Amount    float64        `json:"amount"`
+
+	// TaxableAmount is the part of the amount tax is charged on:
+	// the whole amount, or zero when the component is not taxed.
+	TaxableAmount float64 `json:"taxable_amount"`
+	TaxRate       float64 `json:"tax_rate"`
+	TaxAmount     float64 `json:"tax_amount"`
+}
+
+// OrderComponents returns the components of an order with the given amounts
+// and no tax. The subtotal is always present, other components only when set.
+func OrderComponents(subtotal, shipping, handling, discount float64) []OrderComponentTax {
+	components := []OrderComponentTax{{Component: OrderComponentSubtotal, Amount: subtotal}}
+
+	for _, c := range []OrderComponentTax{
+		{Component: OrderComponentShipping, Amount: shipping},
+		{Component: OrderComponentHandling, Amount: handling},
+		{Component: OrderComponentDiscount, Amount: -discount},
+	} {
+		if c.Amount != 0 {
+			components = append(components, c)
+		}
+	}
+
+	return components
+}
+
+// Apply charges the component at the given rates. The amount of a
+// tax-inclusive component is gross and becomes the net amount.
+func (c *OrderComponentTax) Apply(rates JurisdictionTaxBreakdown, inclusive bool) {
+	c.TaxRate = rates.Total()
+	c.Amount, c.TaxAmount = SplitTax(c.Amount, c.TaxRate, inclusive)
+	c.TaxableAmount = 0
+	if c.TaxRate != 0 {
+		c.TaxableAmount = c.Amount
+	}
+}
+
+// ApplyItems charges the subtotal component with the tax of the taxed items.
+// The amount is the sum of the item amounts, which are net for tax-inclusive
+// orders, and the tax rate is the effective rate over the whole subtotal.
+func (c *OrderComponentTax) ApplyItems(items []OrderItem) {
+	c.Amount, c.TaxableAmount, c.TaxAmount, c.TaxRate = 0, 0, 0, 0
+	for _, item := range items {
+		if item.TaxRate != 0 {
+			c.TaxableAmount += item.Amount
+		}
+		c.Amount += item.Amount
+		c.TaxAmount += item.TaxAmount
+	}
+	if c.Amount != 0 {
+		c.TaxRate = c.TaxAmount / c.Amount
+	}
+}
+
+// SplitTax returns the net amount and the tax of an amount charged at rate.
+// The tax is rounded to cents either way. A tax-exclusive amount is net and
+// is charged the tax on top of it. A tax-inclusive amount is gross: the tax
+// is backed out of it, and the net amount is the rest, so both add up to
+// the gross.
+func SplitTax(amount, rate float64, inclusive bool) (net, tax float64) {
+	if !inclusive {
+		return amount, roundCents(amount * rate)
+	}
+
+	tax = roundCents(amount * rate / (1 + rate))
+	return roundCents(amount - tax), tax
+}
+
+// roundCents rounds a money amount to cents.
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSplitTax(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		amount    float64
+		rate      float64
+		inclusive bool
+		wantNet   float64
+		wantTax   float64
+	}{
+		{name: "exclusive", amount: 100, rate: 0.08, wantNet: 100, wantTax: 8},
+		{name: "exclusive_rounded", amount: 10, rate: 0.08875, wantNet: 10, wantTax: 0.89},
+		{name: "inclusive", amount: 108, rate: 0.08, inclusive: true, wantNet: 100, wantTax: 8},
+		{name: "inclusive_rounded", amount: 10, rate: 0.08875, inclusive: true, wantNet: 9.18, wantTax: 0.82},
+		{name: "inclusive_untaxed", amount: 10, inclusive: true, wantNet: 10},
+		{name: "inclusive_discount", amount: -10.8, rate: 0.08, inclusive: true, wantNet: -10, wantTax: -0.8},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			net, tax := SplitTax(tc.amount, tc.rate, tc.inclusive)
+			if math.Abs(net-tc.wantNet) > 1e-9 || math.Abs(tax-tc.wantTax) > 1e-9 {
+				t.Errorf("SplitTax(%v, %v, %v) = %v, %v, want %v, %v", tc.amount, tc.rate, tc.inclusive, net, tax, tc.wantNet, tc.wantTax)
+			}
+		})
+	}
+}
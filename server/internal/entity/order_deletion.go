@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// OrderDeletion reports a soft deletion of orders.
+type OrderDeletion struct {
+	Deleted int `json:"deleted"`
+
+	// PurgeAt is when the deleted orders are removed for good
+	// unless they are restored before.
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// OrderRestoration reports a restoration of soft-deleted orders.
+type OrderRestoration struct {
+	Restored int `json:"restored"`
+}
+
+// WipeConfirmation is the single-use token that confirms
+// a wipe of all orders until it expires.
+type WipeConfirmation struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// OrderEventType is the kind of change an order event records.
+type OrderEventType string
+
+// OrderEventSource is where a change of an order came from.
+type OrderEventSource string
+
+// OrderEvent records a change of an order with who made it, where it came
+// from and the order before and after it. Events are only ever appended.
+type OrderEvent struct {
+	Id      int            `json:"id"`
+	OrderId int            `json:"order_id"`
+	Type    OrderEventType `json:"type"`
+	Actor   Actor          `json:"actor"`
+
+	// SourceId identifies the import job of imported orders.
+	Source   OrderEventSource `json:"source"`
+	SourceId string           `json:"source_id,omitempty"`
+
+	// Before is nil for created orders. Deletions and restorations
+	// do not change the order and keep no snapshots.
+	Before *Order `json:"before"`
+	After  *Order `json:"after"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Actor is who made a request: the API key, by its fingerprint and never
+// the key itself, and the user the caller acts for, if it names one.
+type Actor struct {
+	Key  string `json:"key"`
+	User string `json:"user,omitempty"`
+}
+
+type actorContextKey struct{}
+
+// ContextWithActor returns a copy of the context carrying the actor.
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor of the context,
+// or the zero actor when there is none.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorContextKey{}).(Actor)
+	return actor
+}
+
+// KeyFingerprint identifies an API key without revealing it.
+func KeyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
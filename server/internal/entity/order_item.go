@@ -0,0 +1,26 @@
+package entity
+
+// OrderItem is a line of an order. Items are taxed one by one
+// by the taxability rule and override of their own category.
+type OrderItem struct {
+	Id   int `json:"id"`
+	Line int `json:"line"`
+
+	SKU         string  `json:"sku"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Category    string  `json:"category"`
+
+	// Discount is taken off the line, capped at its gross amount.
+	// Amount is the quantity times the unit price less the discount.
+	// For tax-inclusive orders the unit price and discount are gross
+	// and Amount is the net amount left after backing out the tax.
+	Discount float64 `json:"discount"`
+	Amount   float64 `json:"amount"`
+
+	// TaxOverride is the name of the override applied to the item, if any.
+	TaxOverride string  `json:"tax_override"`
+	TaxRate     float64 `json:"tax_rate"`
+	TaxAmount   float64 `json:"tax_amount"`
+}
@@ -0,0 +1,243 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// OrderRefund is a full or partial return of an order. The refund is
+// allocated to the components and item lines of the order, and the tax of
+// every line is reversed at its own rate, so refunding everything left on
+// the order reverses exactly the tax left on it.
+type OrderRefund struct {
+	Id      int `json:"id"`
+	OrderId int `json:"order_id"`
+
+	// Amount is the pre-tax amount returned
+	// and TaxAmount the tax reversed with it.
+	Amount    float64 `json:"amount"`
+	TaxAmount float64 `json:"tax_amount"`
+
+	// Lines lists how the amount and the tax are allocated.
+	Lines []OrderRefundLine `json:"lines"`
+
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrderRefundLine is the part of a refund allocated to a component
+// of the order, or to an item line of its subtotal.
+type OrderRefundLine struct {
+	Component OrderComponent `json:"component"`
+
+	// Line is the item line the amount is refunded from,
+	// zero for components other than itemized subtotals.
+	Line int `json:"line,omitempty"`
+
+	Amount    float64 `json:"amount"`
+	TaxRate   float64 `json:"tax_rate"`
+	TaxAmount float64 `json:"tax_amount"`
+}
+
+// refundTolerance absorbs the rounding of amounts compared to what is left
+// on an order, so a refund of the displayed amount refunds it in full.
+const refundTolerance = 0.005
+
+// WithRefund returns the order after the refund, which takes it to the status.
+func (o Order) WithRefund(refund OrderRefund, status OrderStatus) Order {
+	o.RefundedAmount += refund.Amount
+	o.RefundedTaxAmount += refund.TaxAmount
+	o.NetTotalAmount -= refund.Amount + refund.TaxAmount
+	o.NetTaxAmount -= refund.TaxAmount
+	o.Refunds = append(o.Refunds[:len(o.Refunds):len(o.Refunds)], refund)
+	o.Status = status
+	o.UpdatedAt = refund.CreatedAt
+	o.Version++
+	return o
+}
+
+// Refundable returns the pre-tax amount and the tax
+// of the order that have not been refunded yet.
+func (o Order) Refundable() (amount, tax float64) {
+	return o.TotalAmount - o.TaxAmount - o.RefundedAmount, o.TaxAmount - o.RefundedTaxAmount
+}
+
+// Refund returns the refund from the order and the status the order takes
+// after it. The given lines are refunded from the matching component or item
+// line, everything left on it when their amount is zero, and the pre-tax
+// amount is spread over what is left on all lines after them. A zero amount
+// without lines refunds everything left. The tax of every line is reversed
+// at its rate and rounded to cents, or is the tax left on it when it is
+// refunded in full.
+// Only completed orders, which carry a tax, can be refunded, others return
+// ErrOrderNotRefundable. Lines the order does not have return
+// ErrRefundLineNotFound, and amounts above what is left on a line
+// or on the order return ErrRefundExceedsOrder.
+func (o Order) Refund(amount float64, lines []OrderRefundLine) (OrderRefund, OrderStatus, error) {
+	if o.Status != OrderStatusCompleted && o.Status != OrderStatusPartiallyRefunded {
+		return OrderRefund{}, "", ErrOrderNotRefundable
+	}
+
+	left, _ := o.Refundable()
+	if left < refundTolerance {
+		return OrderRefund{}, "", ErrRefundExceedsOrder
+	}
+
+	refund := OrderRefund{OrderId: o.Id}
+	rest := o.refundableLines()
+
+	for _, requested := range lines {
+		i := refundLineIndex(rest, requested)
+		if i < 0 || requested.Component == OrderComponentDiscount {
+			return OrderRefund{}, "", ErrRefundLineNotFound
+		}
+		if requested.Amount > rest[i].Amount+refundTolerance {
+			return OrderRefund{}, "", ErrRefundExceedsOrder
+		}
+
+		line := rest[i].part(requested.Amount)
+		rest[i].Amount -= line.Amount
+		rest[i].TaxAmount -= line.TaxAmount
+		refund.add(line)
+	}
+
+	var restAmount float64
+	for _, line := range rest {
+		restAmount += line.Amount
+	}
+	if amount > restAmount+refundTolerance {
+		return OrderRefund{}, "", ErrRefundExceedsOrder
+	}
+
+	if amount > 0 || len(lines) == 0 {
+		share := 1.0
+		if amount > 0 && amount < restAmount-refundTolerance {
+			share = amount / restAmount
+		}
+		for _, line := range rest {
+			if line.Amount == 0 && line.TaxAmount == 0 {
+				continue
+			}
+			refund.add(line.part(line.Amount * share))
+		}
+	}
+
+	if refund.Amount < refundTolerance {
+		return OrderRefund{}, "", ErrRefundExceedsOrder
+	}
+
+	status := OrderStatusPartiallyRefunded
+	if left-refund.Amount < refundTolerance {
+		status = OrderStatusRefunded
+	}
+	return refund, status, nil
+}
+
+// add adds the line to the refund.
+func (r *OrderRefund) add(line OrderRefundLine) {
+	r.Amount += line.Amount
+	r.TaxAmount += line.TaxAmount
+	r.Lines = append(r.Lines, line)
+}
+
+// part returns the given pre-tax amount of what is left on the line with
+// the tax reversed at the rate of the line, or everything left on it when
+// the amount is zero or within the tolerance of what is left.
+func (l OrderRefundLine) part(amount float64) OrderRefundLine {
+	if amount == 0 || math.Abs(amount-l.Amount) < refundTolerance {
+		return l
+	}
+
+	l.TaxAmount = clampTax(roundCents(amount*l.TaxRate), l.TaxAmount)
+	l.Amount = amount
+	return l
+}
+
+// clampTax keeps the reversed tax within the tax left on a line.
+func clampTax(tax, left float64) float64 {
+	if left >= 0 {
+		return math.Max(0, math.Min(tax, left))
+	}
+	return math.Min(0, math.Max(tax, left))
+}
+
+// refundableLines returns what is left to refund on every component of the
+// order, with the itemized subtotal split into its item lines. Refunds that
+// are not allocated to lines, e.g. ones recorded before refunds were
+// allocated, are spread over the lines in proportion to their amount and tax.
+func (o Order) refundableLines() []OrderRefundLine {
+	var lines []OrderRefundLine
+	for _, c := range o.Components {
+		if c.Component == OrderComponentSubtotal && len(o.Items) > 0 {
+			for _, item := range o.Items {
+				lines = append(lines, OrderRefundLine{
+					Component: c.Component,
+					Line:      item.Line,
+					Amount:    item.Amount,
+					TaxRate:   item.TaxRate,
+					TaxAmount: item.TaxAmount,
+				})
+			}
+			continue
+		}
+		lines = append(lines, OrderRefundLine{
+			Component: c.Component,
+			Amount:    c.Amount,
+			TaxRate:   c.TaxRate,
+			TaxAmount: c.TaxAmount,
+		})
+	}
+
+	// orders stored without components are refunded as a whole
+	if len(lines) == 0 {
+		line := OrderRefundLine{Component: OrderComponentSubtotal, Amount: o.TotalAmount - o.TaxAmount, TaxAmount: o.TaxAmount}
+		if line.Amount != 0 {
+			line.TaxRate = line.TaxAmount / line.Amount
+		}
+		lines = append(lines, line)
+	}
+
+	// the unallocated refunds are spread over the lines as charged
+	var total, totalTax float64
+	for _, line := range lines {
+		total += line.Amount
+		totalTax += line.TaxAmount
+	}
+	unallocated, unallocatedTax := o.RefundedAmount, o.RefundedTaxAmount
+	for _, refund := range o.Refunds {
+		for _, refunded := range refund.Lines {
+			unallocated -= refunded.Amount
+			unallocatedTax -= refunded.TaxAmount
+		}
+	}
+	for i := range lines {
+		if total != 0 {
+			lines[i].Amount -= unallocated * lines[i].Amount / total
+		}
+		if totalTax != 0 {
+			lines[i].TaxAmount -= unallocatedTax * lines[i].TaxAmount / totalTax
+		}
+	}
+
+	for _, refund := range o.Refunds {
+		for _, refunded := range refund.Lines {
+			if i := refundLineIndex(lines, refunded); i >= 0 {
+				lines[i].Amount -= refunded.Amount
+				lines[i].TaxAmount -= refunded.TaxAmount
+			}
+		}
+	}
+
+	return lines
+}
+
+// refundLineIndex returns the index of the line of the same component
+// and item line, or -1 when there is none.
+func refundLineIndex(lines []OrderRefundLine, line OrderRefundLine) int {
+	for i := range lines {
+		if lines[i].Component == line.Component && lines[i].Line == line.Line {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestOrder_Refund(t *testing.T) {
+	t.Parallel()
+
+	// 100 taxed at 8% plus 10 of untaxed shipping
+	order := Order{
+		Id: 1, Status: OrderStatusCompleted, TotalAmount: 118, TaxAmount: 8,
+		Components: []OrderComponentTax{
+			{Component: OrderComponentSubtotal, Amount: 100, TaxableAmount: 100, TaxRate: 0.08, TaxAmount: 8},
+			{Component: OrderComponentShipping, Amount: 10},
+		},
+	}
+	partially := order
+	partially.Status = OrderStatusPartiallyRefunded
+
+	// items of 60 at 10% and 40 exempt plus 10 of shipping at 5%
+	itemized := Order{
+		Id: 2, Status: OrderStatusCompleted, TotalAmount: 116.5, TaxAmount: 6.5,
+		Components: []OrderComponentTax{
+			{Component: OrderComponentSubtotal, Amount: 100, TaxableAmount: 60, TaxRate: 0.06, TaxAmount: 6},
+			{Component: OrderComponentShipping, Amount: 10, TaxableAmount: 10, TaxRate: 0.05, TaxAmount: 0.5},
+		},
+		Items: []OrderItem{
+			{Line: 1, Amount: 60, TaxRate: 0.1, TaxAmount: 6},
+			{Line: 2, Amount: 40},
+		},
+	}
+	itemizedRefunded := itemized
+	itemizedRefunded.Status = OrderStatusPartiallyRefunded
+	itemizedRefunded.RefundedAmount, itemizedRefunded.RefundedTaxAmount = 40, 0
+	itemizedRefunded.Refunds = []OrderRefund{{Amount: 40, Lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Line: 2, Amount: 40}}}}
+
+	tests := []struct {
+		name       string
+		order      Order
+		amount     float64
+		lines      []OrderRefundLine
+		wantAmount float64
+		wantTax    float64
+		wantStatus OrderStatus
+		wantErr    error
+	}{
+		{name: "full", order: order, wantAmount: 110, wantTax: 8, wantStatus: OrderStatusRefunded},
+		{name: "partial", order: order, amount: 55, wantAmount: 55, wantTax: 4, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "partial_rounded", order: order, amount: 10, wantAmount: 10, wantTax: 0.73, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "exact_rest", order: Order{Status: OrderStatusPartiallyRefunded, TotalAmount: 118, TaxAmount: 8, RefundedAmount: 55, RefundedTaxAmount: 4}, amount: 55, wantAmount: 55, wantTax: 4, wantStatus: OrderStatusRefunded},
+		{name: "rest_within_cent", order: Order{Status: OrderStatusPartiallyRefunded, TotalAmount: 118, TaxAmount: 8, RefundedAmount: 10, RefundedTaxAmount: 0.73}, amount: 100.001, wantAmount: 100, wantTax: 7.27, wantStatus: OrderStatusRefunded},
+		{name: "exceeds", order: order, amount: 110.01, wantErr: ErrRefundExceedsOrder},
+		{name: "already_refunded", order: Order{Status: OrderStatusPartiallyRefunded, TotalAmount: 118, TaxAmount: 8, RefundedAmount: 110, RefundedTaxAmount: 8}, wantErr: ErrRefundExceedsOrder},
+		{name: "untaxed_shipping", order: order, lines: []OrderRefundLine{{Component: OrderComponentShipping}}, wantAmount: 10, wantTax: 0, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "partial_subtotal", order: partially, lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Amount: 25}}, wantAmount: 25, wantTax: 2, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "exempt_item", order: itemized, lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Line: 2}}, wantAmount: 40, wantTax: 0, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "taxed_item_part", order: itemized, lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Line: 1, Amount: 30}}, wantAmount: 30, wantTax: 3, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "item_and_amount", order: itemized, amount: 10, lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Line: 2}}, wantAmount: 50, wantTax: 0.93, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "rest_after_item", order: itemizedRefunded, wantAmount: 70, wantTax: 6.5, wantStatus: OrderStatusRefunded},
+		{name: "item_refunded_before", order: itemizedRefunded, lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Line: 2, Amount: 1}}, wantErr: ErrRefundExceedsOrder},
+		{name: "item_exceeds", order: itemized, lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Line: 1, Amount: 61}}, wantErr: ErrRefundExceedsOrder},
+		{name: "unknown_line", order: itemized, lines: []OrderRefundLine{{Component: OrderComponentSubtotal, Line: 3}}, wantErr: ErrRefundLineNotFound},
+		{name: "missing_component", order: order, lines: []OrderRefundLine{{Component: OrderComponentHandling}}, wantErr: ErrRefundLineNotFound},
+		{name: "out_of_scope", order: Order{Status: OrderStatusOutOfScope, TotalAmount: 100}, wantErr: ErrOrderNotRefundable},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			refund, status, err := tc.order.Refund(tc.amount, tc.lines)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Refund(%v) error = %v, want %v", tc.amount, err, tc.wantErr)
+			}
+			if math.Abs(refund.Amount-tc.wantAmount) > 1e-9 || math.Abs(refund.TaxAmount-tc.wantTax) > 1e-9 || status != tc.wantStatus {
+				t.Errorf("Refund(%v) = %+v, %q, want amount %v, tax %v, %q", tc.amount, refund, status, tc.wantAmount, tc.wantTax, tc.wantStatus)
+			}
+
+			var amount, tax float64
+			for _, line := range refund.Lines {
+				amount += line.Amount
+				tax += line.TaxAmount
+			}
+			if math.Abs(amount-refund.Amount) > 1e-9 || math.Abs(tax-refund.TaxAmount) > 1e-9 {
+				t.Errorf("lines %+v do not add up to the refund", refund.Lines)
+			}
+		})
+	}
+}
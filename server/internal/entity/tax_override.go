@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+type TaxOverrideSource string
+
+// TaxOverride is a time-bounded rule that temporarily changes the rates
+// of the covered jurisdictions, e.g. a sales tax holiday.
+// It covers the listed reporting codes of its state ("*" covers every
+// jurisdiction of the state, an empty state means the primary one)
+// and, when Category is set, only orders of that category placed
+// within [StartsAt, EndsAt).
+//
+// Without Rate the override exempts the listed components,
+// or the whole tax when no components are listed.
+// With Rate it replaces the rate of the single listed component.
+type TaxOverride struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state,omitempty"`
+
+	Jurisdictions []string        `json:"jurisdictions"`
+	Category      string          `json:"category,omitempty"`
+	Components    []TaxLayerLevel `json:"components,omitempty"`
+	Rate          *float64        `json:"rate,omitempty"`
+
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+
+	Source    TaxOverrideSource `json:"source"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Validate checks that the override has a name, covers at least one
+// jurisdiction, has a non-empty period and, when replacing a rate,
+// targets exactly one known component with a rate within [0, 1].
+func (o TaxOverride) Validate() bool {
+	if o.Name == "" || len(o.Jurisdictions) == 0 || !o.EndsAt.After(o.StartsAt) {
+		return false
+	}
+	for _, level := range o.Components {
+		if !level.Valid() {
+			return false
+		}
+	}
+	if o.Rate != nil {
+		return len(o.Components) == 1 && *o.Rate >= 0 && *o.Rate <= 1
+	}
+	return true
+}
+
+// Matches reports whether the override applies to an order
+// with the given reporting code and category placed at the given time.
+func (o TaxOverride) Matches(reportingCode, category string, at time.Time) bool {
+	if at.Before(o.StartsAt) || !at.Before(o.EndsAt) {
+		return false
+	}
+	if o.Category != "" && o.Category != category {
+		return false
+	}
+	return slices.Contains(o.Jurisdictions, AllJurisdictions) || slices.Contains(o.Jurisdictions, reportingCode)
+}
+
+// Apply returns the breakdown with the override applied.
+func (o TaxOverride) Apply(b JurisdictionTaxBreakdown) JurisdictionTaxBreakdown {
+	if len(o.Components) == 0 {
+		return JurisdictionTaxBreakdown{}
+	}
+
+	rate := 0.0
+	if o.Rate != nil {
+		rate = *o.Rate
+	}
+	for _, level := range o.Components {
+		b.SetRate(level, rate)
+	}
+	return b
+}
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaxOverride(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)
+	rates := JurisdictionTaxBreakdown{State: 0.04, County: 0.04, City: 0.01}
+	reduced := 0.02
+
+	tests := []struct {
+		name     string
+		override TaxOverride
+		code     string
+		category string
+		at       time.Time
+		matches  bool
+		want     JurisdictionTaxBreakdown
+	}{
+		{
+			name:     "full_holiday",
+			override: TaxOverride{Jurisdictions: []string{AllJurisdictions}},
+			code:     "0001", at: start,
+			matches: true,
+			want:    JurisdictionTaxBreakdown{},
+		},
+		{
+			name:     "state_holiday_for_category",
+			override: TaxOverride{Jurisdictions: []string{"0001"}, Category: "clothing", Components: []TaxLayerLevel{TaxLayerLevelState}},
+			code:     "0001", category: "clothing", at: start.Add(time.Hour),
+			matches: true,
+			want:    JurisdictionTaxBreakdown{County: 0.04, City: 0.01},
+		},
+		{
+			name:     "replacement_rate",
+			override: TaxOverride{Jurisdictions: []string{"0001"}, Components: []TaxLayerLevel{TaxLayerLevelCounty}, Rate: &reduced},
+			code:     "0001", at: start,
+			matches: true,
+			want:    JurisdictionTaxBreakdown{State: 0.04, County: 0.02, City: 0.01},
+		},
+		{
+			name:     "other_category",
+			override: TaxOverride{Jurisdictions: []string{AllJurisdictions}, Category: "clothing"},
+			code:     "0001", category: "grocery", at: start,
+		},
+		{
+			name:     "other_jurisdiction",
+			override: TaxOverride{Jurisdictions: []string{"0002"}},
+			code:     "0001", at: start,
+		},
+		{
+			name:     "end_is_exclusive",
+			override: TaxOverride{Jurisdictions: []string{AllJurisdictions}},
+			code:     "0001", at: start.AddDate(0, 0, 3),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := tc.override
+			o.Name = tc.name
+			o.StartsAt = start
+			o.EndsAt = start.AddDate(0, 0, 3)
+
+			if !o.Validate() {
+				t.Fatalf("expected override %+v to be valid", o)
+			}
+			if got := o.Matches(tc.code, tc.category, tc.at); got != tc.matches {
+				t.Fatalf("Matches() = %v, want %v", got, tc.matches)
+			}
+			if tc.matches {
+				if got := o.Apply(rates); got != tc.want {
+					t.Errorf("Apply() = %+v, want %+v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTaxOverride_Validate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)
+	rate := 0.02
+
+	invalid := []TaxOverride{
+		{Jurisdictions: []string{"0001"}, StartsAt: start, EndsAt: start.Add(time.Hour)},
+		{Name: "no_jurisdictions", StartsAt: start, EndsAt: start.Add(time.Hour)},
+		{Name: "empty_period", Jurisdictions: []string{"0001"}, StartsAt: start, EndsAt: start},
+		{Name: "rate_without_component", Jurisdictions: []string{"0001"}, Rate: &rate, StartsAt: start, EndsAt: start.Add(time.Hour)},
+		{Name: "unknown_component", Jurisdictions: []string{"0001"}, Components: []TaxLayerLevel{"federal"}, StartsAt: start, EndsAt: start.Add(time.Hour)},
+	}
+
+	for _, o := range invalid {
+		if o.Validate() {
+			t.Errorf("expected override %q to be invalid", o.Name)
+		}
+	}
+}
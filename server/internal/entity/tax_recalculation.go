@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// rateTolerance is the difference below which two tax rates are
+// considered equal, absorbing the noise of summing rate components.
+const rateTolerance = 1e-9
+
+type TaxRecalculationStatus string
+
+// TaxRecalculation is a run resolving the tax of stored orders again
+// with the currently loaded rates. It is previewed when it is created:
+// Changes lists every order whose tax would change. Applying it later
+// updates those orders in batches, unless they were changed meanwhile.
+type TaxRecalculation struct {
+	Id     int                    `json:"id"`
+	Status TaxRecalculationStatus `json:"status"`
+
+	// RatesVersion identifies the tax data, including the active boundaries
+	// and overrides, the preview was computed with. The recalculation can
+	// only be applied with the same data loaded.
+	RatesVersion string `json:"rates_version"`
+
+	Filter TaxRecalculationFilter `json:"filter"`
+
+	Changes []OrderTaxChange `json:"changes"`
+
+	// Unchanged counts the matched orders whose tax stays the same.
+	// Skipped counts the matched orders that can no longer be changed,
+	// e.g. voided or refunded ones.
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+
+	// Applied counts the changes applied so far and Conflicts the ones
+	// left out because the order was changed after the preview.
+	Applied   int `json:"applied"`
+	Conflicts int `json:"conflicts"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// TaxRecalculationFilter selects the orders of a recalculation.
+type TaxRecalculationFilter struct {
+	FromDate      *time.Time  `json:"from_date,omitempty"`
+	ToDate        *time.Time  `json:"to_date,omitempty"`
+	ReportingCode string      `json:"reporting_code,omitempty"`
+	Status        OrderStatus `json:"status,omitempty"`
+}
+
+// OrderTaxChange is the change of the tax of a single order.
+// Version is the version of the order the change was computed from.
+type OrderTaxChange struct {
+	OrderId int             `json:"order_id"`
+	Version int             `json:"version"`
+	Before  OrderTaxFigures `json:"before"`
+	After   OrderTaxFigures `json:"after"`
+}
+
+// OrderTaxFigures are the tax figures of an order compared by a recalculation.
+type OrderTaxFigures struct {
+	Status           OrderStatus `json:"status"`
+	ReportingCode    string      `json:"reporting_code"`
+	CompositeTaxRate float64     `json:"composite_tax_rate"`
+	TaxAmount        float64     `json:"tax_amount"`
+	TotalAmount      float64     `json:"total_amount"`
+}
+
+// NewOrderTaxFigures returns the tax figures of the order.
+func NewOrderTaxFigures(o Order) OrderTaxFigures {
+	return OrderTaxFigures{
+		Status:           o.Status,
+		ReportingCode:    o.ReportingCode,
+		CompositeTaxRate: o.CompositeTaxRate,
+		TaxAmount:        o.TaxAmount,
+		TotalAmount:      o.TotalAmount,
+	}
+}
+
+// Equal reports whether the figures are the same, comparing amounts
+// in rounded cents and rates within rateTolerance, so that recomputing
+// the same tax is never reported as a change.
+func (f OrderTaxFigures) Equal(other OrderTaxFigures) bool {
+	return f.Status == other.Status &&
+		f.ReportingCode == other.ReportingCode &&
+		math.Abs(f.CompositeTaxRate-other.CompositeTaxRate) < rateTolerance &&
+		roundCents(f.TaxAmount) == roundCents(other.TaxAmount) &&
+		roundCents(f.TotalAmount) == roundCents(other.TotalAmount)
+}
+
+// OutOfScopeResolution reports a retry of out-of-scope orders against
+// the loaded tax data. Resolved counts the orders now completed and
+// Conflicts the ones changed by another request meanwhile.
+type OutOfScopeResolution struct {
+	Checked   int `json:"checked"`
+	Resolved  int `json:"resolved"`
+	Conflicts int `json:"conflicts"`
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "testing"
+
+func TestOrderTaxFigures_Equal(t *testing.T) {
+	t.Parallel()
+
+	base := OrderTaxFigures{Status: OrderStatusCompleted, ReportingCode: "NY-1", CompositeTaxRate: 0.08875, TaxAmount: 8.88, TotalAmount: 108.88}
+
+	tests := []struct {
+		name  string
+		other func(f *OrderTaxFigures)
+		equal bool
+	}{
+		{name: "same", other: func(f *OrderTaxFigures) {}, equal: true},
+		{name: "floating point noise", other: func(f *OrderTaxFigures) {
+			f.CompositeTaxRate = 0.04 + 0.04875
+			f.TaxAmount += 1e-9
+			f.TotalAmount -= 1e-9
+		}, equal: true},
+		{name: "one cent", other: func(f *OrderTaxFigures) { f.TaxAmount = 8.89 }},
+		{name: "rate", other: func(f *OrderTaxFigures) { f.CompositeTaxRate = 0.08876 }},
+		{name: "reporting code", other: func(f *OrderTaxFigures) { f.ReportingCode = "NY-2" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.other(&other)
+			if got := base.Equal(other); got != tt.equal {
+				t.Errorf("expected %v, got %v", tt.equal, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package entity
+
+import "math"
+
+type TaxTreatment string
+
+// TaxabilityRule describes how a product category is taxed in a jurisdiction.
+// When Threshold is set, amounts strictly below it are taxed using
+// BelowThreshold instead of Treatment, e.g. NY clothing under $110
+// is taxed at local rates only.
+type TaxabilityRule struct {
+	Treatment TaxTreatment `json:"treatment"`
+
+	// Factor scales every component for the reduced treatment.
+	Factor float64 `json:"factor,omitempty"`
+
+	Threshold      float64      `json:"threshold,omitempty"`
+	BelowThreshold TaxTreatment `json:"below_threshold,omitempty"`
+}
+
+// Valid reports whether the treatment is known.
+// An empty treatment is valid and means fully taxable.
+func (t TaxTreatment) Valid() bool {
+	switch t {
+	case "", TaxTreatmentTaxable, TaxTreatmentExempt, TaxTreatmentReduced, TaxTreatmentStateOnly, TaxTreatmentLocalOnly:
+		return true
+	}
+	return false
+}
+
+// Validate checks that the rule only references known treatments
+// and that a reduced treatment carries a factor within [0, 1].
+func (r TaxabilityRule) Validate() bool {
+	if !r.Treatment.Valid() || !r.BelowThreshold.Valid() {
+		return false
+	}
+	if r.Threshold < 0 {
+		return false
+	}
+	if r.Treatment == TaxTreatmentReduced || r.BelowThreshold == TaxTreatmentReduced {
+		return r.Factor >= 0 && r.Factor <= 1
+	}
+	return true
+}
+
+// TreatmentFor returns the treatment applicable to the given amount.
+func (r TaxabilityRule) TreatmentFor(amount float64) TaxTreatment {
+	if r.Threshold > 0 && amount < r.Threshold && r.BelowThreshold != "" {
+		return r.BelowThreshold
+	}
+	if r.Treatment == "" {
+		return TaxTreatmentTaxable
+	}
+	return r.Treatment
+}
+
+// Apply returns the breakdown of rates applicable to the given amount.
+func (r TaxabilityRule) Apply(b JurisdictionTaxBreakdown, amount float64) JurisdictionTaxBreakdown {
+	switch r.TreatmentFor(amount) {
+	case TaxTreatmentExempt:
+		return JurisdictionTaxBreakdown{}
+	case TaxTreatmentStateOnly:
+		return JurisdictionTaxBreakdown{State: b.State}
+	case TaxTreatmentLocalOnly:
+		b.State = 0
+		return b
+	case TaxTreatmentReduced:
+		return JurisdictionTaxBreakdown{
+			State:   b.State * r.Factor,
+			County:  b.County * r.Factor,
+			City:    b.City * r.Factor,
+			Special: b.Special * r.Factor,
+		}
+	default:
+		return b
+	}
+}
+
+// ApplyCharged returns the breakdown of rates applicable to an amount as it
+// is charged, together with the amount net of the tax that the threshold is
+// compared with. A tax-inclusive amount is gross, and its net amount depends
+// on the treatment it gets, so the tax is backed out at the rates above the
+// threshold first and, when the net amount then falls below it, at the rates
+// below the threshold.
+func (r TaxabilityRule) ApplyCharged(b JurisdictionTaxBreakdown, amount float64, inclusive bool) (JurisdictionTaxBreakdown, float64) {
+	if !inclusive {
+		return r.Apply(b, amount), amount
+	}
+
+	above := r.Apply(b, math.Inf(1))
+	net, _ := SplitTax(amount, above.Total(), true)
+	if r.TreatmentFor(net) == r.TreatmentFor(math.Inf(1)) {
+		return above, net
+	}
+
+	below := r.Apply(b, 0)
+	net, _ = SplitTax(amount, below.Total(), true)
+	return below, net
+}
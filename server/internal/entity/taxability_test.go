@@ -0,0 +1,92 @@
+package entity
+
+import "testing"
+
+func TestTaxabilityRule_Apply(t *testing.T) {
+	t.Parallel()
+
+	rates := JurisdictionTaxBreakdown{State: 0.04, County: 0.04, City: 0.01, Special: 0.00375}
+
+	tests := []struct {
+		name   string
+		rule   TaxabilityRule
+		amount float64
+		want   JurisdictionTaxBreakdown
+	}{
+		{name: "taxable", rule: TaxabilityRule{Treatment: TaxTreatmentTaxable}, amount: 10, want: rates},
+		{name: "empty_treatment", rule: TaxabilityRule{}, amount: 10, want: rates},
+		{name: "exempt", rule: TaxabilityRule{Treatment: TaxTreatmentExempt}, amount: 10, want: JurisdictionTaxBreakdown{}},
+		{name: "state_only", rule: TaxabilityRule{Treatment: TaxTreatmentStateOnly}, amount: 10, want: JurisdictionTaxBreakdown{State: 0.04}},
+		{name: "local_only", rule: TaxabilityRule{Treatment: TaxTreatmentLocalOnly}, amount: 10, want: JurisdictionTaxBreakdown{County: 0.04, City: 0.01, Special: 0.00375}},
+		{name: "reduced", rule: TaxabilityRule{Treatment: TaxTreatmentReduced, Factor: 0.5}, amount: 10, want: JurisdictionTaxBreakdown{State: 0.02, County: 0.02, City: 0.005, Special: 0.001875}},
+		{
+			name:   "below_threshold",
+			rule:   TaxabilityRule{Treatment: TaxTreatmentTaxable, Threshold: 110, BelowThreshold: TaxTreatmentLocalOnly},
+			amount: 109.99,
+			want:   JurisdictionTaxBreakdown{County: 0.04, City: 0.01, Special: 0.00375},
+		},
+		{
+			name:   "at_threshold",
+			rule:   TaxabilityRule{Treatment: TaxTreatmentTaxable, Threshold: 110, BelowThreshold: TaxTreatmentLocalOnly},
+			amount: 110,
+			want:   rates,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := tc.rule.Apply(rates, tc.amount)
+			if got != tc.want {
+				t.Fatalf("Apply()=%+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTaxabilityRule_ApplyCharged(t *testing.T) {
+	t.Parallel()
+
+	rates := JurisdictionTaxBreakdown{State: 0.04, County: 0.04, City: 0.01, Special: 0.00375}
+	local := JurisdictionTaxBreakdown{County: 0.04, City: 0.01, Special: 0.00375}
+	rule := TaxabilityRule{Treatment: TaxTreatmentTaxable, Threshold: 110, BelowThreshold: TaxTreatmentLocalOnly}
+
+	tests := []struct {
+		name      string
+		amount    float64
+		inclusive bool
+		want      JurisdictionTaxBreakdown
+		wantNet   float64
+	}{
+		{name: "exclusive", amount: 112, want: rates, wantNet: 112},
+		{name: "inclusive_above", amount: 125, inclusive: true, want: rates, wantNet: 114.29},
+		{name: "inclusive_below", amount: 112, inclusive: true, want: local, wantNet: 106.29},
+		{name: "inclusive_between", amount: 118, inclusive: true, want: local, wantNet: 111.98},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, net := rule.ApplyCharged(rates, tc.amount, tc.inclusive)
+			if got != tc.want || net != tc.wantNet {
+				t.Fatalf("ApplyCharged()=%+v, %v, want %+v, %v", got, net, tc.want, tc.wantNet)
+			}
+		})
+	}
+}
+
+func TestTaxabilityRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	if !(TaxabilityRule{Treatment: TaxTreatmentExempt}).Validate() {
+		t.Error("expected exempt rule to be valid")
+	}
+	if (TaxabilityRule{Treatment: "free"}).Validate() {
+		t.Error("expected unknown treatment to be invalid")
+	}
+	if (TaxabilityRule{Treatment: TaxTreatmentReduced, Factor: 1.5}).Validate() {
+		t.Error("expected reduced factor above 1 to be invalid")
+	}
+}
@@ -2,21 +2,97 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
 	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+
+	"github.com/paulmach/orb/geojson"
 )
 
 //go:generate mockgen -source=contracts.go -destination=./mocks/mocks.go -package=repomocks
 type (
 	OrderRepo interface {
-		Create(ctx context.Context, order entity.Order) (int, error)
-		BatchCreate(ctx context.Context, orders []entity.Order) error
+		Create(ctx context.Context, order entity.Order, event entity.OrderEvent) (int, error)
+		BatchCreate(ctx context.Context, orders []entity.Order, event entity.OrderEvent) error
 		GetById(ctx context.Context, id int) (entity.Order, error)
 		GetAll(ctx context.Context, filter dto.OrderFilters) (entity.OrderList, error)
-		DeleteAll(ctx context.Context) error
+		GetItems(ctx context.Context, orderIDs []int) (map[int][]entity.OrderItem, error)
+		CreateWipeConfirmation(ctx context.Context, tokenHash string, expiresAt time.Time) error
+		DeleteAll(ctx context.Context, tokenHash string, at time.Time) error
+		SoftDelete(ctx context.Context, filter dto.OrderFilters, at time.Time, event entity.OrderEvent) ([]int, error)
+		Restore(ctx context.Context, filter dto.OrderFilters, event entity.OrderEvent) ([]int, error)
+		PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+		Update(ctx context.Context, order entity.Order, event entity.OrderEvent) error
+		BatchUpdate(ctx context.Context, orders []entity.Order, events []entity.OrderEvent) error
+		UpdateStatus(ctx context.Context, order entity.Order, event entity.OrderEvent) error
+		CreateRefund(ctx context.Context, refund entity.OrderRefund, version int, status entity.OrderStatus, event entity.OrderEvent) (int, error)
+	}
+	OrderEventRepo interface {
+		GetByOrderId(ctx context.Context, orderID int) ([]entity.OrderEvent, error)
+	}
+	TaxRecalculationRepo interface {
+		Create(ctx context.Context, rec entity.TaxRecalculation) (int, error)
+		GetById(ctx context.Context, id int) (entity.TaxRecalculation, error)
+		StartApply(ctx context.Context, id int) error
+		UpdateProgress(ctx context.Context, rec entity.TaxRecalculation) error
 	}
 	TaxRepo interface {
-		GetTaxByLocation(ctx context.Context, lat, lon float64) (*entity.JurisdictionTax, bool)
+		GetTaxByLocation(ctx context.Context, lat, lon float64) (*entity.LocationTax, bool)
+		ExplainTaxByLocation(ctx context.Context, lat, lon float64) (*entity.LocationTax, entity.TaxExplanation, bool)
+		GetTaxByJurisdiction(ctx context.Context, state, name string, lat, lon float64) (*entity.LocationTax, bool)
+		GetOverride(ctx context.Context, state, reportingCode, category string, at time.Time) (*entity.TaxOverride, bool)
+		DataVersion(ctx context.Context) string
+		HasState(ctx context.Context, state string) bool
+		PrimaryState(ctx context.Context) string
+	}
+	GeocodeRepo interface {
+		GeocodeZip(ctx context.Context, zip string) (entity.Geocode, bool)
+	}
+	ActiveExchangeRateRepo interface {
+		GetRate(ctx context.Context, currency string, at time.Time) (entity.ExchangeRate, bool)
+		GetRates(ctx context.Context) []entity.ExchangeRate
+		ReplaceRates(ctx context.Context, rates []entity.ExchangeRate)
+	}
+	ExchangeRateRepo interface {
+		Upsert(ctx context.Context, rates []entity.ExchangeRate) error
+		GetAll(ctx context.Context) ([]entity.ExchangeRate, error)
+	}
+	ActiveTaxOverrideRepo interface {
+		GetActiveOverrides(ctx context.Context) []entity.TaxOverride
+		ReplaceOverrides(ctx context.Context, overrides []entity.TaxOverride)
+		HasState(ctx context.Context, state string) bool
+	}
+	TaxOverrideRepo interface {
+		Create(ctx context.Context, override entity.TaxOverride) (entity.TaxOverride, error)
+		GetAll(ctx context.Context) ([]entity.TaxOverride, error)
+		Delete(ctx context.Context, id int) error
+	}
+	ExemptionRepo interface {
+		Create(ctx context.Context, customer entity.Customer, cert entity.ExemptionCertificate) (entity.ExemptionCertificate, error)
+		GetByCustomerRef(ctx context.Context, customerRef string) ([]entity.ExemptionCertificate, error)
+	}
+	CustomerRepo interface {
+		Create(ctx context.Context, customer entity.Customer) (entity.Customer, error)
+		GetById(ctx context.Context, id int) (entity.Customer, error)
+		GetByExternalRef(ctx context.Context, externalRef string) (entity.Customer, error)
+		GetAll(ctx context.Context, limit, offset int) (entity.CustomerList, error)
+		Update(ctx context.Context, customer entity.Customer) error
+		Delete(ctx context.Context, id int) error
+		GetTotals(ctx context.Context, id int) (entity.CustomerTotals, error)
+	}
+	BoundaryRepo interface {
+		GetActiveFeatures(ctx context.Context, state string) []*geojson.Feature
+		ValidateFeatures(ctx context.Context, state string, features []*geojson.Feature) entity.BoundaryValidationReport
+		DiffFeatures(ctx context.Context, state string, features []*geojson.Feature) entity.BoundaryDiff
+		ReplaceFeatures(ctx context.Context, state string, features []*geojson.Feature)
+		HasState(ctx context.Context, state string) bool
+		PrimaryState(ctx context.Context) string
+	}
+	BoundarySetRepo interface {
+		Create(ctx context.Context, set entity.BoundarySet) (int, error)
+		GetById(ctx context.Context, id int) (entity.BoundarySet, error)
+		GetActive(ctx context.Context) ([]entity.BoundarySet, error)
+		Activate(ctx context.Context, id int) error
 	}
 )
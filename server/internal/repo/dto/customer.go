@@ -0,0 +1,13 @@
+package dto
+
+// Customer creates or replaces a customer. The address, ZIP code and
+// coordinates are the default location of orders placed without one;
+// the coordinates must be given together.
+type Customer struct {
+	ExternalRef string   `json:"external_ref" validate:"required,max=128"`
+	Name        string   `json:"name" validate:"max=255"`
+	Address     string   `json:"address" validate:"omitempty,max=256"`
+	Zip         string   `json:"zip" validate:"omitempty,max=10"`
+	Latitude    *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude   *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+}
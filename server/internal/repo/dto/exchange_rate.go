@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// ExchangeRate sets the value in USD of one unit of the currency
+// from the date on. The time of the date is ignored.
+type ExchangeRate struct {
+	Currency string    `json:"currency" validate:"required,len=3,alpha"`
+	Date     time.Time `json:"date" validate:"required"`
+	Rate     float64   `json:"rate" validate:"gt=0"`
+}
+
+type ExchangeRates struct {
+	Rates []ExchangeRate `json:"rates" validate:"required,min=1,max=1000,dive"`
+}
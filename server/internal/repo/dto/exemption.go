@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+type ExemptionCertificate struct {
+	CustomerRef  string `json:"customer_ref" validate:"required,max=128"`
+	CustomerName string `json:"customer_name" validate:"max=255"`
+
+	Number string               `json:"number" validate:"required,max=64"`
+	Type   entity.ExemptionType `json:"type" validate:"required,oneof=resale nonprofit government other"`
+
+	// State is the code of the state the certificate was issued in.
+	// Empty means the primary state.
+	State string `json:"state" validate:"max=8"`
+
+	Jurisdictions []string               `json:"jurisdictions" validate:"required,min=1,dive,required,max=10"`
+	Components    []entity.TaxLayerLevel `json:"components" validate:"dive,oneof=state county city special"`
+
+	ValidFrom time.Time  `json:"valid_from" validate:"required"`
+	ValidTo   *time.Time `json:"valid_to"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
 
 type Order struct {
 	Id        int       `json:"id"`
@@ -8,20 +12,140 @@ type Order struct {
 	Latitude  float64   `json:"latitude"`
 	Timestamp time.Time `json:"timestamp" validate:"required"`
 	Subtotal  float64   `json:"subtotal"`
+	Category  string    `json:"category" validate:"omitempty,max=64"`
+
+	// Shipping and Handling are charged on top of the subtotal,
+	// Discount is taken off it. Each is taxed by its own rule.
+	Shipping float64 `json:"shipping" validate:"gte=0"`
+	Handling float64 `json:"handling" validate:"gte=0"`
+	Discount float64 `json:"discount" validate:"gte=0"`
+
+	// CustomerRef references the customer whose exemption
+	// certificates are applied to the order.
+	CustomerRef string `json:"customer_ref" validate:"omitempty,max=128"`
+
+	// CustomerId links the order to a registered customer, who can be
+	// given by CustomerRef instead. Orders without a location of their
+	// own are placed at the default location of the customer.
+	CustomerId *int `json:"customer_id" validate:"omitempty,gt=0"`
+
+	// Address and Zip locate orders without coordinates. The ZIP code
+	// is taken from Zip or, when empty, found in the address line.
+	Address string `json:"address" validate:"omitempty,max=256"`
+	Zip     string `json:"zip" validate:"omitempty,max=10"`
+
+	// Items are the order lines. When given, the subtotal is derived
+	// from them and every item is taxed by its own category.
+	Items []OrderItem `json:"items" validate:"omitempty,max=1000,dive"`
+
+	// TaxInclusive tells the amounts already include the tax,
+	// which is then backed out of them instead of added on top.
+	TaxInclusive bool `json:"tax_inclusive"`
+
+	// Currency is the ISO 4217 code of the currency of the amounts,
+	// USD when empty. Other currencies are converted to USD at the
+	// exchange rate of the order date.
+	Currency string `json:"currency" validate:"omitempty,len=3,alpha"`
+
+	// Explain requests storing how the tax was derived on the order.
+	Explain bool `json:"explain"`
+
+	// Metadata and Tags label the order for reconciliation.
+	Metadata map[string]string `json:"metadata" validate:"omitempty,max=50,dive,keys,min=1,max=64,endkeys,max=256"`
+	Tags     []string          `json:"tags" validate:"omitempty,max=50,dive,min=1,max=64"`
+}
+
+// OrderImport holds the options of a CSV import applied to all its rows.
+type OrderImport struct {
+	// Id identifies the import job in the history of its orders.
+	Id string `json:"import_id"`
+
+	// TaxInclusive tells the amounts of every row already include the tax.
+	TaxInclusive bool `json:"tax_inclusive"`
+
+	// Actor is who started the import.
+	Actor entity.Actor `json:"-"`
+}
+
+type OrderItem struct {
+	SKU         string  `json:"sku" validate:"omitempty,max=64"`
+	Description string  `json:"description" validate:"omitempty,max=256"`
+	Quantity    float64 `json:"quantity" validate:"gt=0"`
+	UnitPrice   float64 `json:"unit_price" validate:"gte=0"`
+	Category    string  `json:"category" validate:"omitempty,max=64"`
+	Discount    float64 `json:"discount" validate:"gte=0"`
+}
+
+// OrderUpdate corrects an order. Only the given fields change
+// and the tax of the order is resolved again.
+type OrderUpdate struct {
+	Latitude  *float64   `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude *float64   `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	Subtotal  *float64   `json:"subtotal" validate:"omitempty,gte=0"`
+	Timestamp *time.Time `json:"timestamp"`
+}
+
+type OrderRefund struct {
+	// Amount is the pre-tax amount returned, spread over everything left
+	// on the order after the lines. Zero without lines refunds everything
+	// left on the order.
+	Amount float64 `json:"amount" validate:"gte=0"`
+
+	// Lines are refunded from the given components or item lines.
+	Lines  []OrderRefundLine `json:"lines" validate:"omitempty,max=1000,dive"`
+	Reason string            `json:"reason" validate:"omitempty,max=256"`
+}
+
+// OrderRefundLine is the pre-tax amount returned from a component of an
+// order, or from an item line of its subtotal given by Line. Zero refunds
+// everything left on it.
+type OrderRefundLine struct {
+	Component string  `json:"component" validate:"oneof=subtotal shipping handling" example:"shipping"`
+	Line      int     `json:"line" validate:"gte=0"`
+	Amount    float64 `json:"amount" validate:"gte=0"`
 }
 
 type OrderFilters struct {
 	Limit  int
 	Offset int
 
-	Status        string
+	// Ids and ImportId select given orders or the orders of an import job.
+	Ids      []int
+	ImportId string
+
+	// CustomerId selects the orders linked to a customer.
+	CustomerId *int
+
+	// Statuses selects orders in any of the given statuses.
+	Statuses []string
+
+	State         string
 	ReportingCode string
+	Category      string
+	CustomerRef   string
+	TaxOverride   string
+
+	Warning          string
+	GeocodingMethod  string
+	BoundaryResolved *bool
 
 	TotalAmountMin *float64
 	TotalAmountMax *float64
 	FromDate       *time.Time
 	ToDate         *time.Time
 
+	// Metadata selects orders having all the given metadata values,
+	// Tags orders having all the given tags.
+	Metadata map[string]string
+	Tags     []string
+
 	SortBy    string
 	SortOrder string
 }
+
+// Targeted reports whether the filter selects given orders, the orders of an
+// import job or those of a customer. Other filters, such as statuses or
+// amount ranges, can match every order and do not target any on their own.
+func (f OrderFilters) Targeted() bool {
+	return len(f.Ids) > 0 || f.ImportId != "" || f.CustomerId != nil
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+type TaxOverride struct {
+	Name string `json:"name" validate:"required,max=128"`
+
+	// State is the code of the state the override applies to.
+	// Empty means the primary state.
+	State string `json:"state" validate:"max=8"`
+
+	Jurisdictions []string               `json:"jurisdictions" validate:"required,min=1,dive,required,max=10"`
+	Category      string                 `json:"category" validate:"max=64"`
+	Components    []entity.TaxLayerLevel `json:"components" validate:"dive,oneof=state county city special"`
+	Rate          *float64               `json:"rate" validate:"omitempty,min=0,max=1"`
+
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+}
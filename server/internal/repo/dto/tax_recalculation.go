@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+// TaxRecalculation requests a preview of resolving the tax of the orders
+// matching the filter again with the loaded rates; other versions of
+// the rates cannot be previewed. ExpectedRatesVersion, when given, must
+// be the version of the loaded tax data, e.g. the one a reviewed preview
+// was computed with, so that a preview is never computed with other rates.
+type TaxRecalculation struct {
+	FromDate             *time.Time         `json:"from_date"`
+	ToDate               *time.Time         `json:"to_date"`
+	ReportingCode        string             `json:"reporting_code" validate:"omitempty,max=64"`
+	Status               entity.OrderStatus `json:"status" validate:"omitempty,oneof=pending completed out_of_scope failed_tax_resolution"`
+	ExpectedRatesVersion string             `json:"expected_rates_version" validate:"omitempty,max=64"`
+}
@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+// Table holds the exchange rates in memory, keyed by currency
+// and sorted by date, so the rate at a date is found by binary search.
+type Table struct {
+	mu    sync.RWMutex
+	rates map[string][]entity.ExchangeRate
+}
+
+func New() *Table {
+	return &Table{rates: make(map[string][]entity.ExchangeRate)}
+}
+
+// GetRate returns the rate of the currency valid at the given time:
+// the latest one dated on or before its UTC date.
+// It returns false when the currency has no rate that early.
+func (t *Table) GetRate(ctx context.Context, currency string, at time.Time) (entity.ExchangeRate, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	rates := t.rates[currency]
+	day := truncateDay(at)
+	i, found := slices.BinarySearchFunc(rates, day, func(r entity.ExchangeRate, d time.Time) int {
+		return r.Date.Compare(d)
+	})
+	if found {
+		return rates[i], true
+	}
+	if i == 0 {
+		return entity.ExchangeRate{}, false
+	}
+	return rates[i-1], true
+}
+
+// GetRates returns all rates sorted by currency and date.
+func (t *Table) GetRates(ctx context.Context) []entity.ExchangeRate {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	currencies := make([]string, 0, len(t.rates))
+	for currency := range t.rates {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+
+	rates := []entity.ExchangeRate{}
+	for _, currency := range currencies {
+		rates = append(rates, t.rates[currency]...)
+	}
+	return rates
+}
+
+// ReplaceRates swaps the rates for the given ones. Of several rates
+// of a currency at the same date, the last one wins.
+func (t *Table) ReplaceRates(ctx context.Context, rates []entity.ExchangeRate) {
+	byCurrency := make(map[string][]entity.ExchangeRate)
+	for _, r := range rates {
+		r.Date = truncateDay(r.Date)
+		byCurrency[r.Currency] = append(byCurrency[r.Currency], r)
+	}
+
+	for currency, list := range byCurrency {
+		slices.SortStableFunc(list, func(a, b entity.ExchangeRate) int {
+			return a.Date.Compare(b.Date)
+		})
+
+		// keep the last rate of every date
+		deduped := list[:0]
+		for i, r := range list {
+			if i+1 < len(list) && list[i+1].Date.Equal(r.Date) {
+				continue
+			}
+			deduped = append(deduped, r)
+		}
+		byCurrency[currency] = deduped
+	}
+
+	t.mu.Lock()
+	t.rates = byCurrency
+	t.mu.Unlock()
+}
+
+// truncateDay returns the start of the UTC date of the time.
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,51 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+func date(day int) time.Time {
+	return time.Date(2025, 8, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGetRate(t *testing.T) {
+	table := New()
+	table.ReplaceRates(context.Background(), []entity.ExchangeRate{
+		{Currency: "EUR", Date: date(10), Rate: 1.10},
+		{Currency: "EUR", Date: date(1), Rate: 1.08, Source: entity.ExchangeRateSourceFile},
+		{Currency: "EUR", Date: date(1), Rate: 1.09, Source: entity.ExchangeRateSourceApi},
+		{Currency: "CAD", Date: date(5), Rate: 0.73},
+	})
+
+	tests := []struct {
+		name     string
+		currency string
+		at       time.Time
+		ok       bool
+		rate     float64
+	}{
+		{name: "same_day", currency: "EUR", at: date(10).Add(15 * time.Hour), ok: true, rate: 1.10},
+		{name: "latest_before", currency: "EUR", at: date(9), ok: true, rate: 1.09},
+		{name: "later_wins_same_date", currency: "EUR", at: date(1), ok: true, rate: 1.09},
+		{name: "utc_date", currency: "CAD", at: time.Date(2025, 8, 4, 22, 0, 0, 0, time.FixedZone("", -4*3600)), ok: true, rate: 0.73},
+		{name: "before_first", currency: "CAD", at: date(4), ok: false},
+		{name: "unknown_currency", currency: "GBP", at: date(10), ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := table.GetRate(context.Background(), tc.currency, tc.at)
+			if ok != tc.ok || (ok && got.Rate != tc.rate) {
+				t.Errorf("GetRate() = %+v, %v, want rate %v, %v", got, ok, tc.rate, tc.ok)
+			}
+		})
+	}
+
+	if rates := table.GetRates(context.Background()); len(rates) != 3 || rates[0].Currency != "CAD" {
+		t.Errorf("unexpected rates %+v", rates)
+	}
+}
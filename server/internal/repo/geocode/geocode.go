@@ -0,0 +1,106 @@
+package geocode
+
+import (
+	"context"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+// Geocoder resolves ZIP codes offline from locally loaded datasets:
+// a ZIP centroid table and a ZIP-to-jurisdiction mapping.
+// Both datasets may be keyed by 5-digit ZIP or ZIP+4 codes.
+type Geocoder struct {
+	// centroids maps normalized ZIP codes to their centroid.
+	centroids map[string]entity.ZipCentroid
+
+	// jurisdictions maps normalized ZIP codes to tax config entries.
+	jurisdictions map[string]entity.ZipJurisdiction
+}
+
+// New constructs a Geocoder from the ZIP datasets.
+// Entries with malformed ZIP codes are skipped.
+func New(centroids []entity.ZipCentroid, jurisdictions []entity.ZipJurisdiction) *Geocoder {
+	g := &Geocoder{
+		centroids:     make(map[string]entity.ZipCentroid, len(centroids)),
+		jurisdictions: make(map[string]entity.ZipJurisdiction, len(jurisdictions)),
+	}
+
+	for _, c := range centroids {
+		if key, ok := zipKey(c.Zip); ok {
+			g.centroids[key] = c
+		}
+	}
+	for _, j := range jurisdictions {
+		if key, ok := zipKey(j.Zip); ok {
+			g.jurisdictions[key] = j
+		}
+	}
+
+	return g
+}
+
+// GeocodeZip resolves a ZIP or ZIP+4 code. The most precise key is tried
+// first: the ZIP+4 code, then its 5-digit prefix. For every key
+// the jurisdiction mapping takes precedence over the centroid table,
+// since a centroid may fall outside the part of a ZIP code that
+// crosses a jurisdiction boundary. A jurisdiction mapping still carries
+// the most precise centroid of the code, at which tax layers are resolved.
+// It returns false when the code is malformed or found in neither dataset.
+func (g *Geocoder) GeocodeZip(ctx context.Context, zip string) (entity.Geocode, bool) {
+	zip5, plus4, ok := entity.NormalizeZip(zip)
+	if !ok {
+		return entity.Geocode{}, false
+	}
+
+	type key struct {
+		zip       string
+		precision entity.GeocodingPrecision
+	}
+	keys := []key{{zip5, entity.GeocodingPrecisionZip5}}
+	if plus4 != "" {
+		keys = []key{{zip5 + "-" + plus4, entity.GeocodingPrecisionZip4}, keys[0]}
+	}
+
+	for i, k := range keys {
+		if j, ok := g.jurisdictions[k.zip]; ok {
+			geocode := entity.Geocode{
+				Method:       entity.GeocodingMethodZipJurisdiction,
+				Precision:    k.precision,
+				Zip:          k.zip,
+				State:        j.State,
+				Jurisdiction: j.Jurisdiction,
+			}
+			for _, ck := range keys[i:] {
+				if centroid, ok := g.centroids[ck.zip]; ok {
+					geocode.Latitude, geocode.Longitude = centroid.Latitude, centroid.Longitude
+					break
+				}
+			}
+			return geocode, true
+		}
+
+		if centroid, ok := g.centroids[k.zip]; ok {
+			return entity.Geocode{
+				Method:    entity.GeocodingMethodZipCentroid,
+				Precision: k.precision,
+				Zip:       k.zip,
+				Latitude:  centroid.Latitude,
+				Longitude: centroid.Longitude,
+			}, true
+		}
+	}
+
+	return entity.Geocode{}, false
+}
+
+// zipKey normalizes a dataset ZIP code to "12345" or "12345-6789".
+func zipKey(zip string) (string, bool) {
+	zip5, plus4, ok := entity.NormalizeZip(zip)
+	if !ok {
+		return "", false
+	}
+	if plus4 == "" {
+		return zip5, true
+	}
+	return zip5 + "-" + plus4, true
+}
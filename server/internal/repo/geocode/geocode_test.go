@@ -0,0 +1,60 @@
+package geocode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+func TestGeocodeZip(t *testing.T) {
+	g := New(
+		[]entity.ZipCentroid{
+			{Zip: "10001", Latitude: 40.75, Longitude: -73.99},
+			{Zip: "10001-1234", Latitude: 40.751, Longitude: -73.991},
+			{Zip: "bad", Latitude: 1, Longitude: 1},
+		},
+		[]entity.ZipJurisdiction{
+			{Zip: "12345", Jurisdiction: "Schenectady"},
+			{Zip: "10001-9999", Jurisdiction: "New York"},
+		},
+	)
+
+	tests := []struct {
+		name         string
+		zip          string
+		ok           bool
+		method       entity.GeocodingMethod
+		precision    entity.GeocodingPrecision
+		key          string
+		lat          float64
+		jurisdiction string
+	}{
+		{name: "zip5_centroid", zip: "10001", ok: true, method: entity.GeocodingMethodZipCentroid, precision: entity.GeocodingPrecisionZip5, key: "10001", lat: 40.75},
+		{name: "zip4_centroid", zip: "10001-1234", ok: true, method: entity.GeocodingMethodZipCentroid, precision: entity.GeocodingPrecisionZip4, key: "10001-1234", lat: 40.751},
+		{name: "zip4_without_dash", zip: "100011234", ok: true, method: entity.GeocodingMethodZipCentroid, precision: entity.GeocodingPrecisionZip4, key: "10001-1234", lat: 40.751},
+		{name: "zip4_falls_back_to_zip5", zip: "10001-0000", ok: true, method: entity.GeocodingMethodZipCentroid, precision: entity.GeocodingPrecisionZip5, key: "10001", lat: 40.75},
+		{name: "zip4_jurisdiction_before_zip5_centroid", zip: "10001-9999", ok: true, method: entity.GeocodingMethodZipJurisdiction, precision: entity.GeocodingPrecisionZip4, key: "10001-9999", lat: 40.75, jurisdiction: "New York"},
+		{name: "zip5_jurisdiction", zip: "12345-6789", ok: true, method: entity.GeocodingMethodZipJurisdiction, precision: entity.GeocodingPrecisionZip5, key: "12345", jurisdiction: "Schenectady"},
+		{name: "unknown", zip: "99999"},
+		{name: "malformed", zip: "1234"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := g.GeocodeZip(context.Background(), tc.zip)
+			if ok != tc.ok {
+				t.Fatalf("found %v, want %v", ok, tc.ok)
+			}
+			if !ok {
+				return
+			}
+			if got.Method != tc.method || got.Precision != tc.precision || got.Zip != tc.key {
+				t.Errorf("got %s/%s/%s, want %s/%s/%s", got.Method, got.Precision, got.Zip, tc.method, tc.precision, tc.key)
+			}
+			if got.Latitude != tc.lat || got.Jurisdiction != tc.jurisdiction {
+				t.Errorf("got lat %v jurisdiction %q, want %v %q", got.Latitude, got.Jurisdiction, tc.lat, tc.jurisdiction)
+			}
+		})
+	}
+}
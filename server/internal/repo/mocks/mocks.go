@@ -12,7 +12,9 @@ package repomocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	geojson "github.com/paulmach/orb/geojson"
 	entity "github.com/ryl1k/INT20H-test-task-server/internal/entity"
 	dto "github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
 	gomock "go.uber.org/mock/gomock"
@@ -43,46 +45,89 @@ func (m *MockOrderRepo) EXPECT() *MockOrderRepoMockRecorder {
 }
 
 // BatchCreate mocks base method.
-func (m *MockOrderRepo) BatchCreate(ctx context.Context, orders []entity.Order) error {
+func (m *MockOrderRepo) BatchCreate(ctx context.Context, orders []entity.Order, event entity.OrderEvent) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BatchCreate", ctx, orders)
+	ret := m.ctrl.Call(m, "BatchCreate", ctx, orders, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BatchCreate indicates an expected call of BatchCreate.
-func (mr *MockOrderRepoMockRecorder) BatchCreate(ctx, orders any) *gomock.Call {
+func (mr *MockOrderRepoMockRecorder) BatchCreate(ctx, orders, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*MockOrderRepo)(nil).BatchCreate), ctx, orders)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*MockOrderRepo)(nil).BatchCreate), ctx, orders, event)
+}
+
+// BatchUpdate mocks base method.
+func (m *MockOrderRepo) BatchUpdate(ctx context.Context, orders []entity.Order, events []entity.OrderEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchUpdate", ctx, orders, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchUpdate indicates an expected call of BatchUpdate.
+func (mr *MockOrderRepoMockRecorder) BatchUpdate(ctx, orders, events any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpdate", reflect.TypeOf((*MockOrderRepo)(nil).BatchUpdate), ctx, orders, events)
 }
 
 // Create mocks base method.
-func (m *MockOrderRepo) Create(ctx context.Context, order entity.Order) (int, error) {
+func (m *MockOrderRepo) Create(ctx context.Context, order entity.Order, event entity.OrderEvent) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, order)
+	ret := m.ctrl.Call(m, "Create", ctx, order, event)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockOrderRepoMockRecorder) Create(ctx, order any) *gomock.Call {
+func (mr *MockOrderRepoMockRecorder) Create(ctx, order, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderRepo)(nil).Create), ctx, order, event)
+}
+
+// CreateRefund mocks base method.
+func (m *MockOrderRepo) CreateRefund(ctx context.Context, refund entity.OrderRefund, version int, status entity.OrderStatus, event entity.OrderEvent) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", ctx, refund, version, status, event)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockOrderRepoMockRecorder) CreateRefund(ctx, refund, version, status, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockOrderRepo)(nil).CreateRefund), ctx, refund, version, status, event)
+}
+
+// CreateWipeConfirmation mocks base method.
+func (m *MockOrderRepo) CreateWipeConfirmation(ctx context.Context, tokenHash string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWipeConfirmation", ctx, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWipeConfirmation indicates an expected call of CreateWipeConfirmation.
+func (mr *MockOrderRepoMockRecorder) CreateWipeConfirmation(ctx, tokenHash, expiresAt any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderRepo)(nil).Create), ctx, order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWipeConfirmation", reflect.TypeOf((*MockOrderRepo)(nil).CreateWipeConfirmation), ctx, tokenHash, expiresAt)
 }
 
 // DeleteAll mocks base method.
-func (m *MockOrderRepo) DeleteAll(ctx context.Context) error {
+func (m *MockOrderRepo) DeleteAll(ctx context.Context, tokenHash string, at time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAll", ctx)
+	ret := m.ctrl.Call(m, "DeleteAll", ctx, tokenHash, at)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAll indicates an expected call of DeleteAll.
-func (mr *MockOrderRepoMockRecorder) DeleteAll(ctx any) *gomock.Call {
+func (mr *MockOrderRepoMockRecorder) DeleteAll(ctx, tokenHash, at any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockOrderRepo)(nil).DeleteAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockOrderRepo)(nil).DeleteAll), ctx, tokenHash, at)
 }
 
 // GetAll mocks base method.
@@ -115,6 +160,215 @@ func (mr *MockOrderRepoMockRecorder) GetById(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockOrderRepo)(nil).GetById), ctx, id)
 }
 
+// GetItems mocks base method.
+func (m *MockOrderRepo) GetItems(ctx context.Context, orderIDs []int) (map[int][]entity.OrderItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, orderIDs)
+	ret0, _ := ret[0].(map[int][]entity.OrderItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockOrderRepoMockRecorder) GetItems(ctx, orderIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockOrderRepo)(nil).GetItems), ctx, orderIDs)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockOrderRepo) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockOrderRepoMockRecorder) PurgeDeleted(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockOrderRepo)(nil).PurgeDeleted), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockOrderRepo) Restore(ctx context.Context, filter dto.OrderFilters, event entity.OrderEvent) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, filter, event)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockOrderRepoMockRecorder) Restore(ctx, filter, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockOrderRepo)(nil).Restore), ctx, filter, event)
+}
+
+// SoftDelete mocks base method.
+func (m *MockOrderRepo) SoftDelete(ctx context.Context, filter dto.OrderFilters, at time.Time, event entity.OrderEvent) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, filter, at, event)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDelete indicates an expected call of SoftDelete.
+func (mr *MockOrderRepoMockRecorder) SoftDelete(ctx, filter, at, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockOrderRepo)(nil).SoftDelete), ctx, filter, at, event)
+}
+
+// Update mocks base method.
+func (m *MockOrderRepo) Update(ctx context.Context, order entity.Order, event entity.OrderEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, order, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOrderRepoMockRecorder) Update(ctx, order, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOrderRepo)(nil).Update), ctx, order, event)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockOrderRepo) UpdateStatus(ctx context.Context, order entity.Order, event entity.OrderEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, order, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockOrderRepoMockRecorder) UpdateStatus(ctx, order, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrderRepo)(nil).UpdateStatus), ctx, order, event)
+}
+
+// MockOrderEventRepo is a mock of OrderEventRepo interface.
+type MockOrderEventRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderEventRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderEventRepoMockRecorder is the mock recorder for MockOrderEventRepo.
+type MockOrderEventRepoMockRecorder struct {
+	mock *MockOrderEventRepo
+}
+
+// NewMockOrderEventRepo creates a new mock instance.
+func NewMockOrderEventRepo(ctrl *gomock.Controller) *MockOrderEventRepo {
+	mock := &MockOrderEventRepo{ctrl: ctrl}
+	mock.recorder = &MockOrderEventRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderEventRepo) EXPECT() *MockOrderEventRepoMockRecorder {
+	return m.recorder
+}
+
+// GetByOrderId mocks base method.
+func (m *MockOrderEventRepo) GetByOrderId(ctx context.Context, orderID int) ([]entity.OrderEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderId", ctx, orderID)
+	ret0, _ := ret[0].([]entity.OrderEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderId indicates an expected call of GetByOrderId.
+func (mr *MockOrderEventRepoMockRecorder) GetByOrderId(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderId", reflect.TypeOf((*MockOrderEventRepo)(nil).GetByOrderId), ctx, orderID)
+}
+
+// MockTaxRecalculationRepo is a mock of TaxRecalculationRepo interface.
+type MockTaxRecalculationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxRecalculationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxRecalculationRepoMockRecorder is the mock recorder for MockTaxRecalculationRepo.
+type MockTaxRecalculationRepoMockRecorder struct {
+	mock *MockTaxRecalculationRepo
+}
+
+// NewMockTaxRecalculationRepo creates a new mock instance.
+func NewMockTaxRecalculationRepo(ctrl *gomock.Controller) *MockTaxRecalculationRepo {
+	mock := &MockTaxRecalculationRepo{ctrl: ctrl}
+	mock.recorder = &MockTaxRecalculationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxRecalculationRepo) EXPECT() *MockTaxRecalculationRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTaxRecalculationRepo) Create(ctx context.Context, rec entity.TaxRecalculation) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rec)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTaxRecalculationRepoMockRecorder) Create(ctx, rec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaxRecalculationRepo)(nil).Create), ctx, rec)
+}
+
+// GetById mocks base method.
+func (m *MockTaxRecalculationRepo) GetById(ctx context.Context, id int) (entity.TaxRecalculation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(entity.TaxRecalculation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTaxRecalculationRepoMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTaxRecalculationRepo)(nil).GetById), ctx, id)
+}
+
+// StartApply mocks base method.
+func (m *MockTaxRecalculationRepo) StartApply(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartApply", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartApply indicates an expected call of StartApply.
+func (mr *MockTaxRecalculationRepoMockRecorder) StartApply(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartApply", reflect.TypeOf((*MockTaxRecalculationRepo)(nil).StartApply), ctx, id)
+}
+
+// UpdateProgress mocks base method.
+func (m *MockTaxRecalculationRepo) UpdateProgress(ctx context.Context, rec entity.TaxRecalculation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProgress", ctx, rec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProgress indicates an expected call of UpdateProgress.
+func (mr *MockTaxRecalculationRepoMockRecorder) UpdateProgress(ctx, rec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockTaxRecalculationRepo)(nil).UpdateProgress), ctx, rec)
+}
+
 // MockTaxRepo is a mock of TaxRepo interface.
 type MockTaxRepo struct {
 	ctrl     *gomock.Controller
@@ -139,11 +393,71 @@ func (m *MockTaxRepo) EXPECT() *MockTaxRepoMockRecorder {
 	return m.recorder
 }
 
+// DataVersion mocks base method.
+func (m *MockTaxRepo) DataVersion(ctx context.Context) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DataVersion", ctx)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DataVersion indicates an expected call of DataVersion.
+func (mr *MockTaxRepoMockRecorder) DataVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataVersion", reflect.TypeOf((*MockTaxRepo)(nil).DataVersion), ctx)
+}
+
+// ExplainTaxByLocation mocks base method.
+func (m *MockTaxRepo) ExplainTaxByLocation(ctx context.Context, lat, lon float64) (*entity.LocationTax, entity.TaxExplanation, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainTaxByLocation", ctx, lat, lon)
+	ret0, _ := ret[0].(*entity.LocationTax)
+	ret1, _ := ret[1].(entity.TaxExplanation)
+	ret2, _ := ret[2].(bool)
+	return ret0, ret1, ret2
+}
+
+// ExplainTaxByLocation indicates an expected call of ExplainTaxByLocation.
+func (mr *MockTaxRepoMockRecorder) ExplainTaxByLocation(ctx, lat, lon any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainTaxByLocation", reflect.TypeOf((*MockTaxRepo)(nil).ExplainTaxByLocation), ctx, lat, lon)
+}
+
+// GetOverride mocks base method.
+func (m *MockTaxRepo) GetOverride(ctx context.Context, state, reportingCode, category string, at time.Time) (*entity.TaxOverride, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverride", ctx, state, reportingCode, category, at)
+	ret0, _ := ret[0].(*entity.TaxOverride)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetOverride indicates an expected call of GetOverride.
+func (mr *MockTaxRepoMockRecorder) GetOverride(ctx, state, reportingCode, category, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverride", reflect.TypeOf((*MockTaxRepo)(nil).GetOverride), ctx, state, reportingCode, category, at)
+}
+
+// GetTaxByJurisdiction mocks base method.
+func (m *MockTaxRepo) GetTaxByJurisdiction(ctx context.Context, state, name string, lat, lon float64) (*entity.LocationTax, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxByJurisdiction", ctx, state, name, lat, lon)
+	ret0, _ := ret[0].(*entity.LocationTax)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetTaxByJurisdiction indicates an expected call of GetTaxByJurisdiction.
+func (mr *MockTaxRepoMockRecorder) GetTaxByJurisdiction(ctx, state, name, lat, lon any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxByJurisdiction", reflect.TypeOf((*MockTaxRepo)(nil).GetTaxByJurisdiction), ctx, state, name, lat, lon)
+}
+
 // GetTaxByLocation mocks base method.
-func (m *MockTaxRepo) GetTaxByLocation(ctx context.Context, lat, lon float64) (*entity.JurisdictionTax, bool) {
+func (m *MockTaxRepo) GetTaxByLocation(ctx context.Context, lat, lon float64) (*entity.LocationTax, bool) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTaxByLocation", ctx, lat, lon)
-	ret0, _ := ret[0].(*entity.JurisdictionTax)
+	ret0, _ := ret[0].(*entity.LocationTax)
 	ret1, _ := ret[1].(bool)
 	return ret0, ret1
 }
@@ -153,3 +467,690 @@ func (mr *MockTaxRepoMockRecorder) GetTaxByLocation(ctx, lat, lon any) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxByLocation", reflect.TypeOf((*MockTaxRepo)(nil).GetTaxByLocation), ctx, lat, lon)
 }
+
+// HasState mocks base method.
+func (m *MockTaxRepo) HasState(ctx context.Context, state string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasState", ctx, state)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasState indicates an expected call of HasState.
+func (mr *MockTaxRepoMockRecorder) HasState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasState", reflect.TypeOf((*MockTaxRepo)(nil).HasState), ctx, state)
+}
+
+// PrimaryState mocks base method.
+func (m *MockTaxRepo) PrimaryState(ctx context.Context) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrimaryState", ctx)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// PrimaryState indicates an expected call of PrimaryState.
+func (mr *MockTaxRepoMockRecorder) PrimaryState(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrimaryState", reflect.TypeOf((*MockTaxRepo)(nil).PrimaryState), ctx)
+}
+
+// MockGeocodeRepo is a mock of GeocodeRepo interface.
+type MockGeocodeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeocodeRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockGeocodeRepoMockRecorder is the mock recorder for MockGeocodeRepo.
+type MockGeocodeRepoMockRecorder struct {
+	mock *MockGeocodeRepo
+}
+
+// NewMockGeocodeRepo creates a new mock instance.
+func NewMockGeocodeRepo(ctrl *gomock.Controller) *MockGeocodeRepo {
+	mock := &MockGeocodeRepo{ctrl: ctrl}
+	mock.recorder = &MockGeocodeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeocodeRepo) EXPECT() *MockGeocodeRepoMockRecorder {
+	return m.recorder
+}
+
+// GeocodeZip mocks base method.
+func (m *MockGeocodeRepo) GeocodeZip(ctx context.Context, zip string) (entity.Geocode, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GeocodeZip", ctx, zip)
+	ret0, _ := ret[0].(entity.Geocode)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GeocodeZip indicates an expected call of GeocodeZip.
+func (mr *MockGeocodeRepoMockRecorder) GeocodeZip(ctx, zip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeocodeZip", reflect.TypeOf((*MockGeocodeRepo)(nil).GeocodeZip), ctx, zip)
+}
+
+// MockActiveExchangeRateRepo is a mock of ActiveExchangeRateRepo interface.
+type MockActiveExchangeRateRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockActiveExchangeRateRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockActiveExchangeRateRepoMockRecorder is the mock recorder for MockActiveExchangeRateRepo.
+type MockActiveExchangeRateRepoMockRecorder struct {
+	mock *MockActiveExchangeRateRepo
+}
+
+// NewMockActiveExchangeRateRepo creates a new mock instance.
+func NewMockActiveExchangeRateRepo(ctrl *gomock.Controller) *MockActiveExchangeRateRepo {
+	mock := &MockActiveExchangeRateRepo{ctrl: ctrl}
+	mock.recorder = &MockActiveExchangeRateRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActiveExchangeRateRepo) EXPECT() *MockActiveExchangeRateRepoMockRecorder {
+	return m.recorder
+}
+
+// GetRate mocks base method.
+func (m *MockActiveExchangeRateRepo) GetRate(ctx context.Context, currency string, at time.Time) (entity.ExchangeRate, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRate", ctx, currency, at)
+	ret0, _ := ret[0].(entity.ExchangeRate)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetRate indicates an expected call of GetRate.
+func (mr *MockActiveExchangeRateRepoMockRecorder) GetRate(ctx, currency, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRate", reflect.TypeOf((*MockActiveExchangeRateRepo)(nil).GetRate), ctx, currency, at)
+}
+
+// GetRates mocks base method.
+func (m *MockActiveExchangeRateRepo) GetRates(ctx context.Context) []entity.ExchangeRate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRates", ctx)
+	ret0, _ := ret[0].([]entity.ExchangeRate)
+	return ret0
+}
+
+// GetRates indicates an expected call of GetRates.
+func (mr *MockActiveExchangeRateRepoMockRecorder) GetRates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRates", reflect.TypeOf((*MockActiveExchangeRateRepo)(nil).GetRates), ctx)
+}
+
+// ReplaceRates mocks base method.
+func (m *MockActiveExchangeRateRepo) ReplaceRates(ctx context.Context, rates []entity.ExchangeRate) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReplaceRates", ctx, rates)
+}
+
+// ReplaceRates indicates an expected call of ReplaceRates.
+func (mr *MockActiveExchangeRateRepoMockRecorder) ReplaceRates(ctx, rates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRates", reflect.TypeOf((*MockActiveExchangeRateRepo)(nil).ReplaceRates), ctx, rates)
+}
+
+// MockExchangeRateRepo is a mock of ExchangeRateRepo interface.
+type MockExchangeRateRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockExchangeRateRepoMockRecorder is the mock recorder for MockExchangeRateRepo.
+type MockExchangeRateRepoMockRecorder struct {
+	mock *MockExchangeRateRepo
+}
+
+// NewMockExchangeRateRepo creates a new mock instance.
+func NewMockExchangeRateRepo(ctrl *gomock.Controller) *MockExchangeRateRepo {
+	mock := &MockExchangeRateRepo{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateRepo) EXPECT() *MockExchangeRateRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockExchangeRateRepo) GetAll(ctx context.Context) ([]entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockExchangeRateRepoMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockExchangeRateRepo)(nil).GetAll), ctx)
+}
+
+// Upsert mocks base method.
+func (m *MockExchangeRateRepo) Upsert(ctx context.Context, rates []entity.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, rates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockExchangeRateRepoMockRecorder) Upsert(ctx, rates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockExchangeRateRepo)(nil).Upsert), ctx, rates)
+}
+
+// MockActiveTaxOverrideRepo is a mock of ActiveTaxOverrideRepo interface.
+type MockActiveTaxOverrideRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockActiveTaxOverrideRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockActiveTaxOverrideRepoMockRecorder is the mock recorder for MockActiveTaxOverrideRepo.
+type MockActiveTaxOverrideRepoMockRecorder struct {
+	mock *MockActiveTaxOverrideRepo
+}
+
+// NewMockActiveTaxOverrideRepo creates a new mock instance.
+func NewMockActiveTaxOverrideRepo(ctrl *gomock.Controller) *MockActiveTaxOverrideRepo {
+	mock := &MockActiveTaxOverrideRepo{ctrl: ctrl}
+	mock.recorder = &MockActiveTaxOverrideRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActiveTaxOverrideRepo) EXPECT() *MockActiveTaxOverrideRepoMockRecorder {
+	return m.recorder
+}
+
+// GetActiveOverrides mocks base method.
+func (m *MockActiveTaxOverrideRepo) GetActiveOverrides(ctx context.Context) []entity.TaxOverride {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveOverrides", ctx)
+	ret0, _ := ret[0].([]entity.TaxOverride)
+	return ret0
+}
+
+// GetActiveOverrides indicates an expected call of GetActiveOverrides.
+func (mr *MockActiveTaxOverrideRepoMockRecorder) GetActiveOverrides(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveOverrides", reflect.TypeOf((*MockActiveTaxOverrideRepo)(nil).GetActiveOverrides), ctx)
+}
+
+// HasState mocks base method.
+func (m *MockActiveTaxOverrideRepo) HasState(ctx context.Context, state string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasState", ctx, state)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasState indicates an expected call of HasState.
+func (mr *MockActiveTaxOverrideRepoMockRecorder) HasState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasState", reflect.TypeOf((*MockActiveTaxOverrideRepo)(nil).HasState), ctx, state)
+}
+
+// ReplaceOverrides mocks base method.
+func (m *MockActiveTaxOverrideRepo) ReplaceOverrides(ctx context.Context, overrides []entity.TaxOverride) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReplaceOverrides", ctx, overrides)
+}
+
+// ReplaceOverrides indicates an expected call of ReplaceOverrides.
+func (mr *MockActiveTaxOverrideRepoMockRecorder) ReplaceOverrides(ctx, overrides any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceOverrides", reflect.TypeOf((*MockActiveTaxOverrideRepo)(nil).ReplaceOverrides), ctx, overrides)
+}
+
+// MockTaxOverrideRepo is a mock of TaxOverrideRepo interface.
+type MockTaxOverrideRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxOverrideRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxOverrideRepoMockRecorder is the mock recorder for MockTaxOverrideRepo.
+type MockTaxOverrideRepoMockRecorder struct {
+	mock *MockTaxOverrideRepo
+}
+
+// NewMockTaxOverrideRepo creates a new mock instance.
+func NewMockTaxOverrideRepo(ctrl *gomock.Controller) *MockTaxOverrideRepo {
+	mock := &MockTaxOverrideRepo{ctrl: ctrl}
+	mock.recorder = &MockTaxOverrideRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxOverrideRepo) EXPECT() *MockTaxOverrideRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTaxOverrideRepo) Create(ctx context.Context, override entity.TaxOverride) (entity.TaxOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, override)
+	ret0, _ := ret[0].(entity.TaxOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTaxOverrideRepoMockRecorder) Create(ctx, override any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaxOverrideRepo)(nil).Create), ctx, override)
+}
+
+// Delete mocks base method.
+func (m *MockTaxOverrideRepo) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTaxOverrideRepoMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaxOverrideRepo)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockTaxOverrideRepo) GetAll(ctx context.Context) ([]entity.TaxOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entity.TaxOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTaxOverrideRepoMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTaxOverrideRepo)(nil).GetAll), ctx)
+}
+
+// MockExemptionRepo is a mock of ExemptionRepo interface.
+type MockExemptionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockExemptionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockExemptionRepoMockRecorder is the mock recorder for MockExemptionRepo.
+type MockExemptionRepoMockRecorder struct {
+	mock *MockExemptionRepo
+}
+
+// NewMockExemptionRepo creates a new mock instance.
+func NewMockExemptionRepo(ctrl *gomock.Controller) *MockExemptionRepo {
+	mock := &MockExemptionRepo{ctrl: ctrl}
+	mock.recorder = &MockExemptionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExemptionRepo) EXPECT() *MockExemptionRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockExemptionRepo) Create(ctx context.Context, customer entity.Customer, cert entity.ExemptionCertificate) (entity.ExemptionCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, customer, cert)
+	ret0, _ := ret[0].(entity.ExemptionCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockExemptionRepoMockRecorder) Create(ctx, customer, cert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExemptionRepo)(nil).Create), ctx, customer, cert)
+}
+
+// GetByCustomerRef mocks base method.
+func (m *MockExemptionRepo) GetByCustomerRef(ctx context.Context, customerRef string) ([]entity.ExemptionCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCustomerRef", ctx, customerRef)
+	ret0, _ := ret[0].([]entity.ExemptionCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCustomerRef indicates an expected call of GetByCustomerRef.
+func (mr *MockExemptionRepoMockRecorder) GetByCustomerRef(ctx, customerRef any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCustomerRef", reflect.TypeOf((*MockExemptionRepo)(nil).GetByCustomerRef), ctx, customerRef)
+}
+
+// MockCustomerRepo is a mock of CustomerRepo interface.
+type MockCustomerRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomerRepoMockRecorder is the mock recorder for MockCustomerRepo.
+type MockCustomerRepoMockRecorder struct {
+	mock *MockCustomerRepo
+}
+
+// NewMockCustomerRepo creates a new mock instance.
+func NewMockCustomerRepo(ctrl *gomock.Controller) *MockCustomerRepo {
+	mock := &MockCustomerRepo{ctrl: ctrl}
+	mock.recorder = &MockCustomerRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerRepo) EXPECT() *MockCustomerRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCustomerRepo) Create(ctx context.Context, customer entity.Customer) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, customer)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCustomerRepoMockRecorder) Create(ctx, customer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerRepo)(nil).Create), ctx, customer)
+}
+
+// Delete mocks base method.
+func (m *MockCustomerRepo) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCustomerRepoMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerRepo)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockCustomerRepo) GetAll(ctx context.Context, limit, offset int) (entity.CustomerList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, limit, offset)
+	ret0, _ := ret[0].(entity.CustomerList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCustomerRepoMockRecorder) GetAll(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCustomerRepo)(nil).GetAll), ctx, limit, offset)
+}
+
+// GetByExternalRef mocks base method.
+func (m *MockCustomerRepo) GetByExternalRef(ctx context.Context, externalRef string) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalRef", ctx, externalRef)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalRef indicates an expected call of GetByExternalRef.
+func (mr *MockCustomerRepoMockRecorder) GetByExternalRef(ctx, externalRef any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalRef", reflect.TypeOf((*MockCustomerRepo)(nil).GetByExternalRef), ctx, externalRef)
+}
+
+// GetById mocks base method.
+func (m *MockCustomerRepo) GetById(ctx context.Context, id int) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockCustomerRepoMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCustomerRepo)(nil).GetById), ctx, id)
+}
+
+// GetTotals mocks base method.
+func (m *MockCustomerRepo) GetTotals(ctx context.Context, id int) (entity.CustomerTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotals", ctx, id)
+	ret0, _ := ret[0].(entity.CustomerTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotals indicates an expected call of GetTotals.
+func (mr *MockCustomerRepoMockRecorder) GetTotals(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotals", reflect.TypeOf((*MockCustomerRepo)(nil).GetTotals), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockCustomerRepo) Update(ctx context.Context, customer entity.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, customer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCustomerRepoMockRecorder) Update(ctx, customer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepo)(nil).Update), ctx, customer)
+}
+
+// MockBoundaryRepo is a mock of BoundaryRepo interface.
+type MockBoundaryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBoundaryRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBoundaryRepoMockRecorder is the mock recorder for MockBoundaryRepo.
+type MockBoundaryRepoMockRecorder struct {
+	mock *MockBoundaryRepo
+}
+
+// NewMockBoundaryRepo creates a new mock instance.
+func NewMockBoundaryRepo(ctrl *gomock.Controller) *MockBoundaryRepo {
+	mock := &MockBoundaryRepo{ctrl: ctrl}
+	mock.recorder = &MockBoundaryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBoundaryRepo) EXPECT() *MockBoundaryRepoMockRecorder {
+	return m.recorder
+}
+
+// DiffFeatures mocks base method.
+func (m *MockBoundaryRepo) DiffFeatures(ctx context.Context, state string, features []*geojson.Feature) entity.BoundaryDiff {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffFeatures", ctx, state, features)
+	ret0, _ := ret[0].(entity.BoundaryDiff)
+	return ret0
+}
+
+// DiffFeatures indicates an expected call of DiffFeatures.
+func (mr *MockBoundaryRepoMockRecorder) DiffFeatures(ctx, state, features any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffFeatures", reflect.TypeOf((*MockBoundaryRepo)(nil).DiffFeatures), ctx, state, features)
+}
+
+// GetActiveFeatures mocks base method.
+func (m *MockBoundaryRepo) GetActiveFeatures(ctx context.Context, state string) []*geojson.Feature {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveFeatures", ctx, state)
+	ret0, _ := ret[0].([]*geojson.Feature)
+	return ret0
+}
+
+// GetActiveFeatures indicates an expected call of GetActiveFeatures.
+func (mr *MockBoundaryRepoMockRecorder) GetActiveFeatures(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveFeatures", reflect.TypeOf((*MockBoundaryRepo)(nil).GetActiveFeatures), ctx, state)
+}
+
+// HasState mocks base method.
+func (m *MockBoundaryRepo) HasState(ctx context.Context, state string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasState", ctx, state)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasState indicates an expected call of HasState.
+func (mr *MockBoundaryRepoMockRecorder) HasState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasState", reflect.TypeOf((*MockBoundaryRepo)(nil).HasState), ctx, state)
+}
+
+// PrimaryState mocks base method.
+func (m *MockBoundaryRepo) PrimaryState(ctx context.Context) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrimaryState", ctx)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// PrimaryState indicates an expected call of PrimaryState.
+func (mr *MockBoundaryRepoMockRecorder) PrimaryState(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrimaryState", reflect.TypeOf((*MockBoundaryRepo)(nil).PrimaryState), ctx)
+}
+
+// ReplaceFeatures mocks base method.
+func (m *MockBoundaryRepo) ReplaceFeatures(ctx context.Context, state string, features []*geojson.Feature) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReplaceFeatures", ctx, state, features)
+}
+
+// ReplaceFeatures indicates an expected call of ReplaceFeatures.
+func (mr *MockBoundaryRepoMockRecorder) ReplaceFeatures(ctx, state, features any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceFeatures", reflect.TypeOf((*MockBoundaryRepo)(nil).ReplaceFeatures), ctx, state, features)
+}
+
+// ValidateFeatures mocks base method.
+func (m *MockBoundaryRepo) ValidateFeatures(ctx context.Context, state string, features []*geojson.Feature) entity.BoundaryValidationReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateFeatures", ctx, state, features)
+	ret0, _ := ret[0].(entity.BoundaryValidationReport)
+	return ret0
+}
+
+// ValidateFeatures indicates an expected call of ValidateFeatures.
+func (mr *MockBoundaryRepoMockRecorder) ValidateFeatures(ctx, state, features any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateFeatures", reflect.TypeOf((*MockBoundaryRepo)(nil).ValidateFeatures), ctx, state, features)
+}
+
+// MockBoundarySetRepo is a mock of BoundarySetRepo interface.
+type MockBoundarySetRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBoundarySetRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBoundarySetRepoMockRecorder is the mock recorder for MockBoundarySetRepo.
+type MockBoundarySetRepoMockRecorder struct {
+	mock *MockBoundarySetRepo
+}
+
+// NewMockBoundarySetRepo creates a new mock instance.
+func NewMockBoundarySetRepo(ctrl *gomock.Controller) *MockBoundarySetRepo {
+	mock := &MockBoundarySetRepo{ctrl: ctrl}
+	mock.recorder = &MockBoundarySetRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBoundarySetRepo) EXPECT() *MockBoundarySetRepoMockRecorder {
+	return m.recorder
+}
+
+// Activate mocks base method.
+func (m *MockBoundarySetRepo) Activate(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Activate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Activate indicates an expected call of Activate.
+func (mr *MockBoundarySetRepoMockRecorder) Activate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Activate", reflect.TypeOf((*MockBoundarySetRepo)(nil).Activate), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockBoundarySetRepo) Create(ctx context.Context, set entity.BoundarySet) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, set)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBoundarySetRepoMockRecorder) Create(ctx, set any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBoundarySetRepo)(nil).Create), ctx, set)
+}
+
+// GetActive mocks base method.
+func (m *MockBoundarySetRepo) GetActive(ctx context.Context) ([]entity.BoundarySet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActive", ctx)
+	ret0, _ := ret[0].([]entity.BoundarySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActive indicates an expected call of GetActive.
+func (mr *MockBoundarySetRepoMockRecorder) GetActive(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockBoundarySetRepo)(nil).GetActive), ctx)
+}
+
+// GetById mocks base method.
+func (m *MockBoundarySetRepo) GetById(ctx context.Context, id int) (entity.BoundarySet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(entity.BoundarySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockBoundarySetRepoMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockBoundarySetRepo)(nil).GetById), ctx, id)
+}
@@ -0,0 +1,164 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/goccy/go-json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BoundarySetRepo implements persistence logic for uploaded boundary sets.
+// Features are stored as JSONB together with their validation report
+// and diff so an activated set survives application restarts.
+type BoundarySetRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewBoundarySetRepo(pool *pgxpool.Pool) *BoundarySetRepo {
+	return &BoundarySetRepo{pool: pool}
+}
+
+// Create inserts a staged boundary set and returns its generated id.
+func (r *BoundarySetRepo) Create(ctx context.Context, set entity.BoundarySet) (int, error) {
+	geoJSON, err := json.Marshal(set.GeoJSON)
+	if err != nil {
+		return 0, fmt.Errorf("marshal geojson: %w", err)
+	}
+
+	reportJSON, err := json.Marshal(set.Report)
+	if err != nil {
+		return 0, fmt.Errorf("marshal report: %w", err)
+	}
+
+	diffJSON, err := json.Marshal(set.Diff)
+	if err != nil {
+		return 0, fmt.Errorf("marshal diff: %w", err)
+	}
+
+	query := `
+INSERT INTO boundary_sets (status, state, feature_count, geojson, report, diff, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id`
+
+	var generatedID int
+	err = r.pool.QueryRow(ctx, query,
+		set.Status,
+		set.State,
+		set.FeatureCount,
+		geoJSON,
+		reportJSON,
+		diffJSON,
+		set.CreatedAt,
+	).Scan(&generatedID)
+	if err != nil {
+		return 0, fmt.Errorf("query row insert: %w", err)
+	}
+
+	return generatedID, nil
+}
+
+// GetById retrieves a boundary set including its features.
+// If no record is found, it returns ErrBoundarySetNotFound.
+func (r *BoundarySetRepo) GetById(ctx context.Context, id int) (entity.BoundarySet, error) {
+	query := `
+SELECT id, status, state, feature_count, geojson, report, diff, created_at, activated_at
+FROM boundary_sets
+WHERE id = $1`
+
+	return r.scanOne(r.pool.QueryRow(ctx, query, id))
+}
+
+// GetActive retrieves the currently active boundary set of every state
+// that has one, ordered by id.
+func (r *BoundarySetRepo) GetActive(ctx context.Context) ([]entity.BoundarySet, error) {
+	query := `
+SELECT id, status, state, feature_count, geojson, report, diff, created_at, activated_at
+FROM boundary_sets
+WHERE status = 'active'
+ORDER BY id`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active boundary sets: %w", err)
+	}
+	defer rows.Close()
+
+	var sets []entity.BoundarySet
+	for rows.Next() {
+		set, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate active boundary sets: %w", err)
+	}
+
+	return sets, nil
+}
+
+// Activate marks the given set as active and supersedes the previously
+// active one of the same state within a single transaction.
+func (r *BoundarySetRepo) Activate(ctx context.Context, id int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+UPDATE boundary_sets SET status = 'superseded'
+WHERE status = 'active' AND id <> $1
+	AND state = (SELECT state FROM boundary_sets WHERE id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("supersede active set: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE boundary_sets SET status = 'active', activated_at = now() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("activate set: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrBoundarySetNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *BoundarySetRepo) scanOne(row pgx.Row) (entity.BoundarySet, error) {
+	var set entity.BoundarySet
+	var geoJSON, reportJSON, diffJSON []byte
+
+	err := row.Scan(
+		&set.Id, &set.Status, &set.State, &set.FeatureCount, &geoJSON,
+		&reportJSON, &diffJSON, &set.CreatedAt, &set.ActivatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.BoundarySet{}, entity.ErrBoundarySetNotFound
+		}
+		return entity.BoundarySet{}, fmt.Errorf("failed to query and scan row: %w", err)
+	}
+
+	if err := json.Unmarshal(geoJSON, &set.GeoJSON); err != nil {
+		return entity.BoundarySet{}, fmt.Errorf("failed to unmarshal geojson: %w", err)
+	}
+	if err := json.Unmarshal(reportJSON, &set.Report); err != nil {
+		return entity.BoundarySet{}, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+	if err := json.Unmarshal(diffJSON, &set.Diff); err != nil {
+		return entity.BoundarySet{}, fmt.Errorf("failed to unmarshal diff: %w", err)
+	}
+
+	return set, nil
+}
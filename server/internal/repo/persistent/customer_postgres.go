@@ -0,0 +1,189 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CustomerRepo implements persistence logic for customers using PostgreSQL.
+type CustomerRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewCustomerRepo(pool *pgxpool.Pool) *CustomerRepo {
+	return &CustomerRepo{pool: pool}
+}
+
+// customerColumns selects a customer together with its exemption status,
+// derived from the validity of its certificates at the time of the query.
+const customerColumns = `
+	c.id, c.external_ref, c.name, c.address, c.zip, c.latitude, c.longitude,
+	CASE
+		WHEN EXISTS (
+			SELECT 1 FROM exemption_certificates ec
+			WHERE ec.customer_id = c.id AND ec.valid_from <= now() AND (ec.valid_to IS NULL OR ec.valid_to > now())
+		) THEN 'active'
+		WHEN EXISTS (SELECT 1 FROM exemption_certificates ec WHERE ec.customer_id = c.id) THEN 'expired'
+		ELSE 'none'
+	END,
+	c.created_at, c.updated_at`
+
+// Create stores a customer and returns it with its generated fields.
+// It returns ErrCustomerAlreadyExists if the external reference is taken.
+func (r *CustomerRepo) Create(ctx context.Context, customer entity.Customer) (entity.Customer, error) {
+	query := `
+INSERT INTO customers (external_ref, name, address, zip, latitude, longitude)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at, updated_at`
+
+	err := r.pool.QueryRow(ctx, query,
+		customer.ExternalRef,
+		customer.Name,
+		customer.Address,
+		customer.Zip,
+		customer.Latitude,
+		customer.Longitude,
+	).Scan(&customer.Id, &customer.CreatedAt, &customer.UpdatedAt)
+	if err != nil {
+		if isUniqueKeyViolation(err) {
+			return entity.Customer{}, entity.ErrCustomerAlreadyExists
+		}
+		return entity.Customer{}, fmt.Errorf("insert customer: %w", err)
+	}
+
+	customer.ExemptionStatus = entity.CustomerExemptionStatusNone
+	return customer, nil
+}
+
+// GetById retrieves a customer by its identifier.
+// If no record is found, it returns ErrCustomerNotFound.
+func (r *CustomerRepo) GetById(ctx context.Context, id int) (entity.Customer, error) {
+	return r.get(ctx, `SELECT`+customerColumns+` FROM customers c WHERE c.id = $1`, id)
+}
+
+// GetByExternalRef retrieves a customer by its external reference.
+// If no record is found, it returns ErrCustomerNotFound.
+func (r *CustomerRepo) GetByExternalRef(ctx context.Context, externalRef string) (entity.Customer, error) {
+	return r.get(ctx, `SELECT`+customerColumns+` FROM customers c WHERE c.external_ref = $1`, externalRef)
+}
+
+func (r *CustomerRepo) get(ctx context.Context, query string, arg any) (entity.Customer, error) {
+	var c entity.Customer
+	err := r.pool.QueryRow(ctx, query, arg).Scan(
+		&c.Id, &c.ExternalRef, &c.Name, &c.Address, &c.Zip, &c.Latitude, &c.Longitude,
+		&c.ExemptionStatus, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entity.Customer{}, entity.ErrCustomerNotFound
+		}
+		return entity.Customer{}, fmt.Errorf("failed to query and scan row: %w", err)
+	}
+	return c, nil
+}
+
+// GetAll returns a page of customers ordered by id
+// together with the total number of customers.
+func (r *CustomerRepo) GetAll(ctx context.Context, limit, offset int) (entity.CustomerList, error) {
+	query := `SELECT` + customerColumns + `, COUNT(*) OVER() AS total_count
+FROM customers c
+ORDER BY c.id
+LIMIT $1 OFFSET $2`
+
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return entity.CustomerList{}, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	list := entity.CustomerList{Customers: []entity.Customer{}}
+	for rows.Next() {
+		var c entity.Customer
+		err := rows.Scan(
+			&c.Id, &c.ExternalRef, &c.Name, &c.Address, &c.Zip, &c.Latitude, &c.Longitude,
+			&c.ExemptionStatus, &c.CreatedAt, &c.UpdatedAt, &list.Total,
+		)
+		if err != nil {
+			return entity.CustomerList{}, fmt.Errorf("failed to scan customer: %w", err)
+		}
+		list.Customers = append(list.Customers, c)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.CustomerList{}, fmt.Errorf("failed while iterating rows: %w", err)
+	}
+
+	return list, nil
+}
+
+// Update replaces the name and default location of the customer,
+// keeping its external reference.
+// It returns ErrCustomerNotFound if there is no such customer.
+func (r *CustomerRepo) Update(ctx context.Context, customer entity.Customer) error {
+	query := `
+UPDATE customers SET
+	name = $2, address = $3, zip = $4, latitude = $5, longitude = $6, updated_at = now()
+WHERE id = $1`
+
+	tag, err := r.pool.Exec(ctx, query,
+		customer.Id,
+		customer.Name,
+		customer.Address,
+		customer.Zip,
+		customer.Latitude,
+		customer.Longitude,
+	)
+	if err != nil {
+		return fmt.Errorf("update customer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCustomerNotFound
+	}
+	return nil
+}
+
+// Delete removes the customer together with its exemption certificates.
+// Its orders are kept and no longer linked to it.
+// If no record is found, it returns ErrCustomerNotFound.
+func (r *CustomerRepo) Delete(ctx context.Context, id int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM customers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCustomerNotFound
+	}
+	return nil
+}
+
+// GetTotals sums up the orders linked to the customer that are not deleted.
+func (r *CustomerRepo) GetTotals(ctx context.Context, id int) (entity.CustomerTotals, error) {
+	query := `
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE status = 'voided'),
+	COALESCE(SUM(net_total_amount) FILTER (WHERE status <> 'voided'), 0),
+	COALESCE(SUM(net_tax_amount) FILTER (WHERE status <> 'voided'), 0),
+	MIN(created_at),
+	MAX(created_at)
+FROM orders
+WHERE customer_id = $1 AND deleted_at IS NULL`
+
+	totals := entity.CustomerTotals{CustomerId: id}
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&totals.OrderCount,
+		&totals.VoidedCount,
+		&totals.NetTotalAmount,
+		&totals.NetTaxAmount,
+		&totals.FirstOrderAt,
+		&totals.LastOrderAt,
+	)
+	if err != nil {
+		return entity.CustomerTotals{}, fmt.Errorf("failed to query customer totals: %w", err)
+	}
+	return totals, nil
+}
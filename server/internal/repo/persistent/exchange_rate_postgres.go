@@ -0,0 +1,74 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExchangeRateRepo implements persistence logic for exchange rates
+// set through the admin API.
+type ExchangeRateRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExchangeRateRepo(pool *pgxpool.Pool) *ExchangeRateRepo {
+	return &ExchangeRateRepo{pool: pool}
+}
+
+// Upsert stores the rates, replacing the stored rate
+// of a currency at the same date, within a single transaction.
+func (r *ExchangeRateRepo) Upsert(ctx context.Context, rates []entity.ExchangeRate) error {
+	query := `
+INSERT INTO exchange_rates (currency, date, rate)
+VALUES ($1, $2, $3)
+ON CONFLICT (currency, date) DO UPDATE SET rate = EXCLUDED.rate, created_at = now()`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, rate := range rates {
+		if _, err := tx.Exec(ctx, query, rate.Currency, rate.Date, rate.Rate); err != nil {
+			return fmt.Errorf("upsert exchange rate: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetAll returns all stored rates sorted by currency and date.
+func (r *ExchangeRateRepo) GetAll(ctx context.Context) ([]entity.ExchangeRate, error) {
+	query := `
+SELECT currency, date, rate
+FROM exchange_rates
+ORDER BY currency, date`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []entity.ExchangeRate{}
+	for rows.Next() {
+		rate := entity.ExchangeRate{Source: entity.ExchangeRateSourceApi}
+		if err := rows.Scan(&rate.Currency, &rate.Date, &rate.Rate); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed while iterating rows: %w", err)
+	}
+
+	return rates, nil
+}
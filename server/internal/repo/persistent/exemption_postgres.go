@@ -0,0 +1,134 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/goccy/go-json"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExemptionRepo implements persistence logic for customers
+// and their tax exemption certificates using PostgreSQL.
+type ExemptionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExemptionRepo(pool *pgxpool.Pool) *ExemptionRepo {
+	return &ExemptionRepo{pool: pool}
+}
+
+// Create stores an exemption certificate for the given customer.
+// The customer is created on first use and identified by its external reference;
+// a non-empty name updates the stored one.
+// It returns ErrExemptionCertificateAlreadyExists if the customer
+// already has a certificate with the same number.
+func (r *ExemptionRepo) Create(ctx context.Context, customer entity.Customer, cert entity.ExemptionCertificate) (entity.ExemptionCertificate, error) {
+	jurisdictionsJSON, err := json.Marshal(cert.Jurisdictions)
+	if err != nil {
+		return entity.ExemptionCertificate{}, fmt.Errorf("marshal jurisdictions: %w", err)
+	}
+
+	componentsJSON, err := json.Marshal(cert.Components)
+	if err != nil {
+		return entity.ExemptionCertificate{}, fmt.Errorf("marshal components: %w", err)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return entity.ExemptionCertificate{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	customerQuery := `
+INSERT INTO customers (external_ref, name)
+VALUES ($1, $2)
+ON CONFLICT (external_ref) DO UPDATE
+SET name = COALESCE(NULLIF(EXCLUDED.name, ''), customers.name), updated_at = now()
+RETURNING id`
+
+	err = tx.QueryRow(ctx, customerQuery, customer.ExternalRef, customer.Name).Scan(&cert.CustomerId)
+	if err != nil {
+		return entity.ExemptionCertificate{}, fmt.Errorf("upsert customer: %w", err)
+	}
+
+	certQuery := `
+INSERT INTO exemption_certificates (
+	customer_id, number, type, state, jurisdictions, components, valid_from, valid_to
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at`
+
+	err = tx.QueryRow(ctx, certQuery,
+		cert.CustomerId,
+		cert.Number,
+		cert.Type,
+		cert.State,
+		jurisdictionsJSON,
+		componentsJSON,
+		cert.ValidFrom,
+		cert.ValidTo,
+	).Scan(&cert.Id, &cert.CreatedAt)
+	if err != nil {
+		if isUniqueKeyViolation(err) {
+			return entity.ExemptionCertificate{}, entity.ErrExemptionCertificateAlreadyExists
+		}
+		return entity.ExemptionCertificate{}, fmt.Errorf("insert certificate: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.ExemptionCertificate{}, fmt.Errorf("commit tx: %w", err)
+	}
+
+	cert.CustomerRef = customer.ExternalRef
+	return cert, nil
+}
+
+// GetByCustomerRef returns all certificates of the customer
+// with the given external reference, ordered by validity start.
+func (r *ExemptionRepo) GetByCustomerRef(ctx context.Context, customerRef string) ([]entity.ExemptionCertificate, error) {
+	query := `
+SELECT
+	ec.id, ec.customer_id, c.external_ref, ec.number, ec.type, ec.state,
+	ec.jurisdictions, ec.components, ec.valid_from, ec.valid_to, ec.created_at
+FROM exemption_certificates ec
+JOIN customers c ON c.id = ec.customer_id
+WHERE c.external_ref = $1
+ORDER BY ec.valid_from, ec.id`
+
+	rows, err := r.pool.Query(ctx, query, customerRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	certs := []entity.ExemptionCertificate{}
+	for rows.Next() {
+		var c entity.ExemptionCertificate
+		var jurisdictionsJSON, componentsJSON []byte
+
+		err := rows.Scan(
+			&c.Id, &c.CustomerId, &c.CustomerRef, &c.Number, &c.Type, &c.State,
+			&jurisdictionsJSON, &componentsJSON, &c.ValidFrom, &c.ValidTo, &c.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan certificate: %w", err)
+		}
+
+		if err := json.Unmarshal(jurisdictionsJSON, &c.Jurisdictions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal jurisdictions: %w", err)
+		}
+		if err := json.Unmarshal(componentsJSON, &c.Components); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal components: %w", err)
+		}
+
+		certs = append(certs, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed while iterating rows: %w", err)
+	}
+
+	return certs, nil
+}
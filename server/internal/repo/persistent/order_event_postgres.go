@@ -0,0 +1,140 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/goccy/go-json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OrderEventRepo implements persistence logic for the history of orders.
+// Events are only appended and keep the order snapshots as JSONB.
+// They are written by OrderRepo in the transaction of the change
+// they record, so OrderEventRepo only reads them.
+type OrderEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOrderEventRepo(pool *pgxpool.Pool) *OrderEventRepo {
+	return &OrderEventRepo{pool: pool}
+}
+
+// copyEvents appends the events within the transaction of the changes they
+// record using PostgreSQL COPY protocol, so that the events of a whole
+// import batch are stored at once and no change is stored without its event.
+func copyEvents(ctx context.Context, tx pgx.Tx, events []entity.OrderEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	columns := []string{
+		"order_id", "type", "actor_key", "actor_user",
+		"source", "source_id", "before", "after", "created_at",
+	}
+
+	_, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"order_events"},
+		columns,
+		pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+			before, err := marshalSnapshot(events[i].Before)
+			if err != nil {
+				return nil, fmt.Errorf("marshal before at index %d: %w", i, err)
+			}
+
+			after, err := marshalSnapshot(events[i].After)
+			if err != nil {
+				return nil, fmt.Errorf("marshal after at index %d: %w", i, err)
+			}
+
+			return []any{
+				events[i].OrderId,
+				string(events[i].Type),
+				events[i].Actor.Key,
+				events[i].Actor.User,
+				string(events[i].Source),
+				events[i].SourceId,
+				before,
+				after,
+				events[i].CreatedAt,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("copy from order events: %w", err)
+	}
+	return nil
+}
+
+// GetByOrderId returns the events of the order from the oldest.
+// An order without events returns an empty list.
+func (r *OrderEventRepo) GetByOrderId(ctx context.Context, orderID int) ([]entity.OrderEvent, error) {
+	query := `
+SELECT id, order_id, type, actor_key, actor_user, source, source_id, before, after, created_at
+FROM order_events
+WHERE order_id = $1
+ORDER BY id`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []entity.OrderEvent{}
+	for rows.Next() {
+		var e entity.OrderEvent
+		var before, after []byte
+
+		err := rows.Scan(
+			&e.Id, &e.OrderId, &e.Type, &e.Actor.Key, &e.Actor.User,
+			&e.Source, &e.SourceId, &before, &after, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order event: %w", err)
+		}
+
+		if before != nil {
+			if err := json.Unmarshal(before, &e.Before); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal before: %w", err)
+			}
+		}
+		if after != nil {
+			if err := json.Unmarshal(after, &e.After); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal after: %w", err)
+			}
+		}
+
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed while iterating order events: %w", err)
+	}
+
+	return events, nil
+}
+
+// eventsFor returns the event for every order id
+// of the orders changed alike, e.g. by a deletion.
+func eventsFor(event entity.OrderEvent, ids []int) []entity.OrderEvent {
+	events := make([]entity.OrderEvent, len(ids))
+	for i, id := range ids {
+		events[i] = event
+		events[i].OrderId = id
+	}
+	return events
+}
+
+// marshalSnapshot serializes an order snapshot.
+// A missing snapshot is stored as NULL.
+func marshalSnapshot(order *entity.Order) ([]byte, error) {
+	if order == nil {
+		return nil, nil
+	}
+	return json.Marshal(order)
+}
@@ -3,6 +3,8 @@ package persistent
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
 	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
@@ -24,26 +26,70 @@ func NewOrderRepo(pool *pgxpool.Pool) *OrderRepo {
 	return &OrderRepo{pool: pool}
 }
 
-// Create inserts a single order into the database.
+// Create inserts a single order, its items and the event recording
+// its creation into the database in one transaction.
 // It serializes jurisdictions into JSON format,
 // executes an INSERT query with RETURNING id,
 // and returns the generated primary key.
-func (r *OrderRepo) Create(ctx context.Context, order entity.Order) (int, error) {
+// The event is stored with the generated id, which is set
+// on its snapshot of the order as well.
+func (r *OrderRepo) Create(ctx context.Context, order entity.Order, event entity.OrderEvent) (int, error) {
 	jurisdictionsJSON, err := json.Marshal(order.Jurisdictions)
 	if err != nil {
 		return 0, fmt.Errorf("marshal jurisdictions: %w", err)
 	}
 
+	explainJSON, err := marshalExplanation(order.Explain)
+	if err != nil {
+		return 0, fmt.Errorf("marshal explanation: %w", err)
+	}
+
+	ambiguousJSON, err := marshalNames(order.AmbiguousJurisdictions)
+	if err != nil {
+		return 0, fmt.Errorf("marshal ambiguous jurisdictions: %w", err)
+	}
+
+	componentsJSON, err := marshalComponents(order.Components)
+	if err != nil {
+		return 0, fmt.Errorf("marshal components: %w", err)
+	}
+
+	originalJSON, err := marshalOriginalAmounts(order.Original)
+	if err != nil {
+		return 0, fmt.Errorf("marshal original amounts: %w", err)
+	}
+
+	metadataJSON, err := marshalMetadata(order.Metadata)
+	if err != nil {
+		return 0, fmt.Errorf("marshal metadata: %w", err)
+	}
+
+	transitionsJSON, err := marshalTransitions(order.StatusTransitions)
+	if err != nil {
+		return 0, fmt.Errorf("marshal status transitions: %w", err)
+	}
+
 	query := `
 INSERT INTO orders (
 	latitude, longitude, total_amount, tax_amount, 
 	composite_tax_rate, state_rate, county_rate, city_rate, 
-	special_rates, jurisdictions, reporting_code, status, created_at, updated_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	special_rates, jurisdictions, reporting_code, status, created_at, updated_at,
+	category, customer_ref, exemption_certificate, tax_override, explain,
+	boundary_resolved, boundary_distance, warning, ambiguous_jurisdictions,
+	zip, geocoding_method, geocoding_precision, state,
+	subtotal, shipping, handling, discount, components, tax_inclusive, import_id,
+	currency, exchange_rate, original_amounts, metadata, tags, customer_id, status_transitions
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41)
 RETURNING id`
 
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	var generatedID int
-	err = r.pool.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		order.Latitude,
 		order.Longitude,
 		order.TotalAmount,
@@ -58,27 +104,101 @@ RETURNING id`
 		order.Status,
 		order.CreatedAt,
 		order.UpdatedAt,
+		order.Category,
+		order.CustomerRef,
+		order.ExemptionCertificate,
+		order.TaxOverride,
+		explainJSON,
+		order.BoundaryResolved,
+		order.BoundaryDistance,
+		order.Warning,
+		ambiguousJSON,
+		order.Zip,
+		order.GeocodingMethod,
+		order.GeocodingPrecision,
+		order.State,
+		order.Subtotal,
+		order.Shipping,
+		order.Handling,
+		order.Discount,
+		componentsJSON,
+		order.TaxInclusive,
+		order.ImportId,
+		order.Currency,
+		order.ExchangeRate,
+		originalJSON,
+		metadataJSON,
+		tagsOrEmpty(order.Tags),
+		order.CustomerId,
+		transitionsJSON,
 	).Scan(&generatedID)
 
 	if err != nil {
 		return 0, fmt.Errorf("query row insert: %w", err)
 	}
 
+	order.Id = generatedID
+	if err := copyItems(ctx, tx, []entity.Order{order}); err != nil {
+		return 0, err
+	}
+
+	event.OrderId = generatedID
+	if event.After != nil {
+		after := *event.After
+		after.Id = generatedID
+		event.After = &after
+	}
+	if err := copyEvents(ctx, tx, []entity.OrderEvent{event}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
 	return generatedID, nil
 }
 
 // BatchCreate performs bulk insertion of orders using PostgreSQL COPY protocol.
 // It serializes jurisdictions for each order and streams
 // data efficiently using pgx.CopyFrom.
+// Order ids are reserved from the sequence upfront and set on the orders,
+// so that the items of all orders can be copied right after them in the same transaction,
+// together with the event recording the creation of every order,
+// which gets the id of the order and a snapshot of it.
 // This method is optimized for high-volume inserts.
-func (r *OrderRepo) BatchCreate(ctx context.Context, orders []entity.Order) error {
+func (r *OrderRepo) BatchCreate(ctx context.Context, orders []entity.Order, event entity.OrderEvent) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT nextval(pg_get_serial_sequence('orders', 'id')) FROM generate_series(1, $1)`, len(orders))
+	if err != nil {
+		return fmt.Errorf("reserve order ids: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return fmt.Errorf("collect order ids: %w", err)
+	}
+	for i := range orders {
+		orders[i].Id = ids[i]
+	}
+
 	columns := []string{
-		"latitude", "longitude", "total_amount", "tax_amount",
+		"id", "latitude", "longitude", "total_amount", "tax_amount",
 		"composite_tax_rate", "state_rate", "county_rate", "city_rate",
 		"special_rates", "jurisdictions", "reporting_code", "status", "created_at", "updated_at",
+		"category", "customer_ref", "exemption_certificate", "tax_override", "explain",
+		"boundary_resolved", "boundary_distance", "warning", "ambiguous_jurisdictions",
+		"zip", "geocoding_method", "geocoding_precision", "state",
+		"subtotal", "shipping", "handling", "discount", "components", "tax_inclusive", "import_id",
+		"currency", "exchange_rate", "original_amounts", "metadata", "tags", "customer_id",
+		"status_transitions",
 	}
 
-	_, err := r.pool.CopyFrom(
+	_, err = tx.CopyFrom(
 		ctx,
 		pgx.Identifier{"orders"},
 		columns,
@@ -88,7 +208,38 @@ func (r *OrderRepo) BatchCreate(ctx context.Context, orders []entity.Order) erro
 				return nil, fmt.Errorf("marshal jurisdictions at index %d: %w", i, err)
 			}
 
+			explainJSON, err := marshalExplanation(orders[i].Explain)
+			if err != nil {
+				return nil, fmt.Errorf("marshal explanation at index %d: %w", i, err)
+			}
+
+			ambiguousJSON, err := marshalNames(orders[i].AmbiguousJurisdictions)
+			if err != nil {
+				return nil, fmt.Errorf("marshal ambiguous jurisdictions at index %d: %w", i, err)
+			}
+
+			componentsJSON, err := marshalComponents(orders[i].Components)
+			if err != nil {
+				return nil, fmt.Errorf("marshal components at index %d: %w", i, err)
+			}
+
+			originalJSON, err := marshalOriginalAmounts(orders[i].Original)
+			if err != nil {
+				return nil, fmt.Errorf("marshal original amounts at index %d: %w", i, err)
+			}
+
+			metadataJSON, err := marshalMetadata(orders[i].Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("marshal metadata at index %d: %w", i, err)
+			}
+
+			transitionsJSON, err := marshalTransitions(orders[i].StatusTransitions)
+			if err != nil {
+				return nil, fmt.Errorf("marshal status transitions at index %d: %w", i, err)
+			}
+
 			return []any{
+				orders[i].Id,
 				orders[i].Latitude,
 				orders[i].Longitude,
 				orders[i].TotalAmount,
@@ -103,6 +254,33 @@ func (r *OrderRepo) BatchCreate(ctx context.Context, orders []entity.Order) erro
 				string(orders[i].Status),
 				orders[i].CreatedAt,
 				orders[i].UpdatedAt,
+				orders[i].Category,
+				orders[i].CustomerRef,
+				orders[i].ExemptionCertificate,
+				orders[i].TaxOverride,
+				explainJSON,
+				orders[i].BoundaryResolved,
+				orders[i].BoundaryDistance,
+				string(orders[i].Warning),
+				ambiguousJSON,
+				orders[i].Zip,
+				string(orders[i].GeocodingMethod),
+				string(orders[i].GeocodingPrecision),
+				orders[i].State,
+				orders[i].Subtotal,
+				orders[i].Shipping,
+				orders[i].Handling,
+				orders[i].Discount,
+				componentsJSON,
+				orders[i].TaxInclusive,
+				orders[i].ImportId,
+				orders[i].Currency,
+				orders[i].ExchangeRate,
+				originalJSON,
+				metadataJSON,
+				tagsOrEmpty(orders[i].Tags),
+				orders[i].CustomerId,
+				transitionsJSON,
 			}, nil
 		}),
 	)
@@ -111,6 +289,62 @@ func (r *OrderRepo) BatchCreate(ctx context.Context, orders []entity.Order) erro
 		return fmt.Errorf("copy from orders: %w", err)
 	}
 
+	if err := copyItems(ctx, tx, orders); err != nil {
+		return err
+	}
+
+	events := make([]entity.OrderEvent, len(orders))
+	for i := range orders {
+		order := orders[i]
+		events[i] = event
+		events[i].OrderId = order.Id
+		events[i].After = &order
+	}
+	if err := copyEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// copyItems inserts the items of the orders, which must have their ids set,
+// using PostgreSQL COPY protocol.
+func copyItems(ctx context.Context, tx pgx.Tx, orders []entity.Order) error {
+	var rows [][]any
+	for _, o := range orders {
+		for _, item := range o.Items {
+			rows = append(rows, []any{
+				o.Id,
+				item.Line,
+				item.SKU,
+				item.Description,
+				item.Quantity,
+				item.UnitPrice,
+				item.Category,
+				item.Discount,
+				item.Amount,
+				item.TaxOverride,
+				item.TaxRate,
+				item.TaxAmount,
+			})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	columns := []string{
+		"order_id", "line", "sku", "description", "quantity", "unit_price",
+		"category", "discount", "amount", "tax_override", "tax_rate", "tax_amount",
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"order_items"}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("copy from order items: %w", err)
+	}
 	return nil
 }
 
@@ -125,55 +359,26 @@ SELECT
 	id, latitude, longitude, total_amount, tax_amount, 
 	composite_tax_rate, state_rate, county_rate, city_rate, 
 	special_rates, jurisdictions, reporting_code, status, 
-	created_at, updated_at, 
+	created_at, updated_at, category, customer_ref, exemption_certificate,
+	tax_override, boundary_resolved, boundary_distance, warning, ambiguous_jurisdictions,
+	zip, geocoding_method, geocoding_precision, state,
+	subtotal, shipping, handling, discount, components, tax_inclusive,
+	refunded_amount, refunded_tax_amount, net_total_amount, net_tax_amount, version, import_id,
+	currency, exchange_rate, original_amounts, metadata, tags, customer_id, status_transitions,
 	COUNT(*) OVER() AS total_count
 FROM orders
-WHERE 1=1` // initial setup for where statement so following should not care
-
-	args := []any{}
-	argID := 1
+WHERE deleted_at IS NULL`
 
-	if filter.Status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argID)
-		args = append(args, filter.Status)
-		argID++
-	}
-
-	if filter.ReportingCode != "" {
-		query += fmt.Sprintf(" AND reporting_code = $%d", argID)
-		args = append(args, filter.ReportingCode)
-		argID++
-	}
-
-	if filter.TotalAmountMin != nil {
-		query += fmt.Sprintf(" AND total_amount >= $%d", argID)
-		args = append(args, *filter.TotalAmountMin)
-		argID++
-	}
-
-	if filter.TotalAmountMax != nil {
-		query += fmt.Sprintf(" AND total_amount <= $%d", argID)
-		args = append(args, *filter.TotalAmountMax)
-		argID++
-	}
-
-	if filter.FromDate != nil {
-		query += fmt.Sprintf(" AND created_at >= $%d", argID)
-		args = append(args, *filter.FromDate)
-		argID++
-	}
-
-	if filter.ToDate != nil {
-		query += fmt.Sprintf(" AND created_at <= $%d", argID)
-		args = append(args, *filter.ToDate)
-		argID++
-	}
+	conditions, args := orderConditions(filter, nil)
+	query += conditions
+	argID := len(args) + 1
 
 	allowedSortColumns := map[string]string{
-		"created_at":   "created_at",
-		"total_amount": "total_amount",
-		"id":           "id",
-		"status":       "status",
+		"created_at":       "created_at",
+		"total_amount":     "total_amount",
+		"net_total_amount": "net_total_amount",
+		"id":               "id",
+		"status":           "status",
 	}
 
 	sortBy, ok := allowedSortColumns[filter.SortBy]
@@ -201,14 +406,19 @@ WHERE 1=1` // initial setup for where statement so following should not care
 
 	for rows.Next() {
 		var o entity.Order
-		var jurisdictionsJSON []byte
+		var jurisdictionsJSON, ambiguousJSON, componentsJSON, originalJSON, metadataJSON, transitionsJSON []byte
 
 		err := rows.Scan(
 			&o.Id, &o.Latitude, &o.Longitude, &o.TotalAmount, &o.TaxAmount,
 			&o.CompositeTaxRate, &o.Breakdown.StateRate, &o.Breakdown.CountyRate,
 			&o.Breakdown.CityRate, &o.Breakdown.SpecialRate, &jurisdictionsJSON,
-			&o.ReportingCode, &o.Status, &o.CreatedAt, &o.UpdatedAt,
-			&total,
+			&o.ReportingCode, &o.Status, &o.CreatedAt, &o.UpdatedAt, &o.Category,
+			&o.CustomerRef, &o.ExemptionCertificate, &o.TaxOverride,
+			&o.BoundaryResolved, &o.BoundaryDistance, &o.Warning, &ambiguousJSON,
+			&o.Zip, &o.GeocodingMethod, &o.GeocodingPrecision, &o.State,
+			&o.Subtotal, &o.Shipping, &o.Handling, &o.Discount, &componentsJSON,
+			&o.TaxInclusive, &o.RefundedAmount, &o.RefundedTaxAmount, &o.NetTotalAmount, &o.NetTaxAmount,
+			&o.Version, &o.ImportId, &o.Currency, &o.ExchangeRate, &originalJSON, &metadataJSON, &o.Tags, &o.CustomerId, &transitionsJSON, &total,
 		)
 		if err != nil {
 			return entity.OrderList{}, fmt.Errorf("failed to scan order: %w", err)
@@ -218,6 +428,28 @@ WHERE 1=1` // initial setup for where statement so following should not care
 			return entity.OrderList{}, fmt.Errorf("failed to unmarshal jurisdictions: %w", err)
 		}
 
+		if err := json.Unmarshal(ambiguousJSON, &o.AmbiguousJurisdictions); err != nil {
+			return entity.OrderList{}, fmt.Errorf("failed to unmarshal ambiguous jurisdictions: %w", err)
+		}
+
+		if err := json.Unmarshal(componentsJSON, &o.Components); err != nil {
+			return entity.OrderList{}, fmt.Errorf("failed to unmarshal components: %w", err)
+		}
+
+		if originalJSON != nil {
+			if err := json.Unmarshal(originalJSON, &o.Original); err != nil {
+				return entity.OrderList{}, fmt.Errorf("failed to unmarshal original amounts: %w", err)
+			}
+		}
+
+		if err := json.Unmarshal(metadataJSON, &o.Metadata); err != nil {
+			return entity.OrderList{}, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+
+		if err := json.Unmarshal(transitionsJSON, &o.StatusTransitions); err != nil {
+			return entity.OrderList{}, fmt.Errorf("failed to unmarshal status transitions: %w", err)
+		}
+
 		orders = append(orders, o)
 	}
 	if err := rows.Err(); err != nil {
@@ -230,39 +462,143 @@ WHERE 1=1` // initial setup for where statement so following should not care
 	}, nil
 }
 
-// DeleteAll removes all records from the orders table.
+// SoftDelete marks the orders matching the filter as deleted at the given
+// time and returns their ids. Pagination and sorting of the filter are ignored.
+// The event is recorded for every deleted order in the same transaction.
+func (r *OrderRepo) SoftDelete(ctx context.Context, filter dto.OrderFilters, at time.Time, event entity.OrderEvent) ([]int, error) {
+	conditions, args := orderConditions(filter, []any{at})
+	query := `UPDATE orders SET deleted_at = $1, version = version + 1 WHERE deleted_at IS NULL` + conditions + ` RETURNING id`
+
+	return r.updateIds(ctx, query, args, event)
+}
+
+// Restore brings back the deleted orders matching the filter
+// and returns their ids. Pagination and sorting of the filter are ignored.
+// The event is recorded for every restored order in the same transaction.
+func (r *OrderRepo) Restore(ctx context.Context, filter dto.OrderFilters, event entity.OrderEvent) ([]int, error) {
+	conditions, args := orderConditions(filter, nil)
+	query := `UPDATE orders SET deleted_at = NULL, version = version + 1 WHERE deleted_at IS NOT NULL` + conditions + ` RETURNING id`
+
+	return r.updateIds(ctx, query, args, event)
+}
+
+// PurgeDeleted removes for good the orders deleted before the given time,
+// together with their items and refunds, and returns how many were removed.
+func (r *OrderRepo) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM orders WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted orders: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// updateIds runs an update returning order ids, records the event
+// for every returned order and collects the ids in one transaction.
+func (r *OrderRepo) updateIds(ctx context.Context, query string, args []any, event entity.OrderEvent) ([]int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("collect order ids: %w", err)
+	}
+
+	if err := copyEvents(ctx, tx, eventsFor(event, ids)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return ids, nil
+}
+
+// CreateWipeConfirmation stores the hash of the token that confirms a wipe
+// of all orders until it expires, replacing any earlier one, so that only
+// the latest token is valid on every instance.
+func (r *OrderRepo) CreateWipeConfirmation(ctx context.Context, tokenHash string, expiresAt time.Time) error {
+	query := `
+INSERT INTO wipe_confirmations (id, token_hash, expires_at) VALUES (TRUE, $1, $2)
+ON CONFLICT (id) DO UPDATE SET token_hash = EXCLUDED.token_hash, expires_at = EXCLUDED.expires_at`
+
+	if _, err := r.pool.Exec(ctx, query, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("failed to create wipe confirmation: %w", err)
+	}
+	return nil
+}
+
+// DeleteAll removes all records from the orders table once confirmed by the
+// hash of a token stored by CreateWipeConfirmation that has not expired at
+// the given time. The confirmation is used up in the same transaction, so it
+// confirms a single wipe, otherwise ErrInvalidConfirmationToken is returned.
 // Intended primarily for administrative or testing use cases.
-func (r *OrderRepo) DeleteAll(ctx context.Context) error {
-	query := `TRUNCATE TABLE orders RESTART IDENTITY CASCADE`
+// The id sequence is not restarted, so ids of wiped orders are never
+// reused and their history is not mixed with the history of new orders.
+func (r *OrderRepo) DeleteAll(ctx context.Context, tokenHash string, at time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	_, err := r.pool.Exec(ctx, query)
+	tag, err := tx.Exec(ctx, `DELETE FROM wipe_confirmations WHERE token_hash = $1 AND expires_at > $2`, tokenHash, at)
 	if err != nil {
+		return fmt.Errorf("failed to use wipe confirmation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrInvalidConfirmationToken
+	}
+
+	if _, err := tx.Exec(ctx, `TRUNCATE TABLE orders CASCADE`); err != nil {
 		return fmt.Errorf("failed to delete all orders: %w", err)
 	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
 	return nil
 }
 
-// GetById retrieves a single order by its identifier.
-// If no record is found, it returns a domain-level ErrOrderNotFound error.
-// Jurisdictions are deserialized from JSON into the domain model.
+// GetById retrieves a single order by its identifier together with its items and refunds.
+// If no record is found or the order is deleted, it returns a domain-level ErrOrderNotFound error.
+// Jurisdictions and the stored explanation, if any,
+// are deserialized from JSON into the domain model.
 func (r *OrderRepo) GetById(ctx context.Context, id int) (entity.Order, error) {
 	query := `
 SELECT 
 	id, latitude, longitude, total_amount, tax_amount, 
 	composite_tax_rate, state_rate, county_rate, city_rate, 
 	special_rates, jurisdictions, reporting_code, status, 
-	created_at, updated_at
+	created_at, updated_at, category, customer_ref, exemption_certificate,
+	tax_override, boundary_resolved, boundary_distance, warning, ambiguous_jurisdictions,
+	zip, geocoding_method, geocoding_precision, state,
+	subtotal, shipping, handling, discount, components, tax_inclusive,
+	refunded_amount, refunded_tax_amount, net_total_amount, net_tax_amount, version, import_id,
+	currency, exchange_rate, original_amounts, metadata, tags, customer_id, status_transitions, explain
 FROM orders
-WHERE id = $1`
+WHERE id = $1 AND deleted_at IS NULL`
 
 	var o entity.Order
-	var jurisdictionsJSON []byte
+	var jurisdictionsJSON, ambiguousJSON, componentsJSON, originalJSON, metadataJSON, transitionsJSON, explainJSON []byte
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&o.Id, &o.Latitude, &o.Longitude, &o.TotalAmount, &o.TaxAmount,
 		&o.CompositeTaxRate, &o.Breakdown.StateRate, &o.Breakdown.CountyRate,
 		&o.Breakdown.CityRate, &o.Breakdown.SpecialRate, &jurisdictionsJSON,
-		&o.ReportingCode, &o.Status, &o.CreatedAt, &o.UpdatedAt,
+		&o.ReportingCode, &o.Status, &o.CreatedAt, &o.UpdatedAt, &o.Category,
+		&o.CustomerRef, &o.ExemptionCertificate, &o.TaxOverride,
+		&o.BoundaryResolved, &o.BoundaryDistance, &o.Warning, &ambiguousJSON,
+		&o.Zip, &o.GeocodingMethod, &o.GeocodingPrecision, &o.State,
+		&o.Subtotal, &o.Shipping, &o.Handling, &o.Discount, &componentsJSON,
+		&o.TaxInclusive, &o.RefundedAmount, &o.RefundedTaxAmount, &o.NetTotalAmount, &o.NetTaxAmount,
+		&o.Version, &o.ImportId, &o.Currency, &o.ExchangeRate, &originalJSON, &metadataJSON, &o.Tags, &o.CustomerId, &transitionsJSON, &explainJSON,
 	)
 
 	if err != nil {
@@ -276,5 +612,581 @@ WHERE id = $1`
 		return entity.Order{}, fmt.Errorf("failed to unmarshal jurisdictions: %w", err)
 	}
 
+	if err := json.Unmarshal(ambiguousJSON, &o.AmbiguousJurisdictions); err != nil {
+		return entity.Order{}, fmt.Errorf("failed to unmarshal ambiguous jurisdictions: %w", err)
+	}
+
+	if err := json.Unmarshal(componentsJSON, &o.Components); err != nil {
+		return entity.Order{}, fmt.Errorf("failed to unmarshal components: %w", err)
+	}
+
+	if originalJSON != nil {
+		if err := json.Unmarshal(originalJSON, &o.Original); err != nil {
+			return entity.Order{}, fmt.Errorf("failed to unmarshal original amounts: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(metadataJSON, &o.Metadata); err != nil {
+		return entity.Order{}, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	if err := json.Unmarshal(transitionsJSON, &o.StatusTransitions); err != nil {
+		return entity.Order{}, fmt.Errorf("failed to unmarshal status transitions: %w", err)
+	}
+
+	if explainJSON != nil {
+		if err := json.Unmarshal(explainJSON, &o.Explain); err != nil {
+			return entity.Order{}, fmt.Errorf("failed to unmarshal explanation: %w", err)
+		}
+	}
+
+	o.Items, err = r.getItems(ctx, id)
+	if err != nil {
+		return entity.Order{}, err
+	}
+
+	o.Refunds, err = r.getRefunds(ctx, id)
+	if err != nil {
+		return entity.Order{}, err
+	}
+
 	return o, nil
 }
+
+// updateOrderQuery replaces the location, amounts, tax and status of an
+// order at the expected version and increments the version.
+const updateOrderQuery = `
+UPDATE orders SET
+	latitude = $3, longitude = $4, total_amount = $5, tax_amount = $6,
+	composite_tax_rate = $7, state_rate = $8, county_rate = $9, city_rate = $10,
+	special_rates = $11, jurisdictions = $12, reporting_code = $13, status = $14,
+	created_at = $15, updated_at = $16, exemption_certificate = $17, tax_override = $18,
+	explain = $19, boundary_resolved = $20, boundary_distance = $21, warning = $22,
+	ambiguous_jurisdictions = $23, zip = $24, geocoding_method = $25, geocoding_precision = $26,
+	state = $27, subtotal = $28, shipping = $29, handling = $30, discount = $31, components = $32,
+	currency = $33, exchange_rate = $34, original_amounts = $35, status_transitions = $36,
+	version = version + 1
+WHERE id = $1 AND version = $2`
+
+// Update replaces the location, amounts, tax and items of the order,
+// increments its version and records the event of the change
+// within a single transaction.
+// The order must still be at its version, otherwise ErrOrderVersionConflict
+// is returned.
+func (r *OrderRepo) Update(ctx context.Context, order entity.Order, event entity.OrderEvent) error {
+	return r.BatchUpdate(ctx, []entity.Order{order}, []entity.OrderEvent{event})
+}
+
+// BatchUpdate updates every order like Update, recording the event of the
+// same index, within a single transaction: either all of the orders are
+// updated or none. The statements are sent in one round trip, and the items
+// and events are copied for all orders at once.
+func (r *OrderRepo) BatchUpdate(ctx context.Context, orders []entity.Order, events []entity.OrderEvent) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	ids := make([]int, len(orders))
+	for i, order := range orders {
+		args, err := updateOrderArgs(order)
+		if err != nil {
+			return err
+		}
+		batch.Queue(updateOrderQuery, args...)
+		ids[i] = order.Id
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	results := tx.SendBatch(ctx, batch)
+	for range orders {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return fmt.Errorf("update order: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			results.Close()
+			return entity.ErrOrderVersionConflict
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("close batch: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM order_items WHERE order_id = ANY($1)`, ids); err != nil {
+		return fmt.Errorf("delete order items: %w", err)
+	}
+	if err := copyItems(ctx, tx, orders); err != nil {
+		return err
+	}
+
+	if err := copyEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// updateOrderArgs returns the arguments of updateOrderQuery for the order.
+func updateOrderArgs(order entity.Order) ([]any, error) {
+	jurisdictionsJSON, err := json.Marshal(order.Jurisdictions)
+	if err != nil {
+		return nil, fmt.Errorf("marshal jurisdictions: %w", err)
+	}
+
+	explainJSON, err := marshalExplanation(order.Explain)
+	if err != nil {
+		return nil, fmt.Errorf("marshal explanation: %w", err)
+	}
+
+	ambiguousJSON, err := marshalNames(order.AmbiguousJurisdictions)
+	if err != nil {
+		return nil, fmt.Errorf("marshal ambiguous jurisdictions: %w", err)
+	}
+
+	componentsJSON, err := marshalComponents(order.Components)
+	if err != nil {
+		return nil, fmt.Errorf("marshal components: %w", err)
+	}
+
+	originalJSON, err := marshalOriginalAmounts(order.Original)
+	if err != nil {
+		return nil, fmt.Errorf("marshal original amounts: %w", err)
+	}
+
+	transitionsJSON, err := marshalTransitions(order.StatusTransitions)
+	if err != nil {
+		return nil, fmt.Errorf("marshal status transitions: %w", err)
+	}
+
+	return []any{
+		order.Id,
+		order.Version,
+		order.Latitude,
+		order.Longitude,
+		order.TotalAmount,
+		order.TaxAmount,
+		order.CompositeTaxRate,
+		order.Breakdown.StateRate,
+		order.Breakdown.CountyRate,
+		order.Breakdown.CityRate,
+		order.Breakdown.SpecialRate,
+		jurisdictionsJSON,
+		order.ReportingCode,
+		order.Status,
+		order.CreatedAt,
+		order.UpdatedAt,
+		order.ExemptionCertificate,
+		order.TaxOverride,
+		explainJSON,
+		order.BoundaryResolved,
+		order.BoundaryDistance,
+		order.Warning,
+		ambiguousJSON,
+		order.Zip,
+		order.GeocodingMethod,
+		order.GeocodingPrecision,
+		order.State,
+		order.Subtotal,
+		order.Shipping,
+		order.Handling,
+		order.Discount,
+		componentsJSON,
+		order.Currency,
+		order.ExchangeRate,
+		originalJSON,
+		transitionsJSON,
+	}, nil
+}
+
+// UpdateStatus sets the status, its transitions and the update time
+// of the order, increments its version and records the event of the change
+// within a single transaction. The order must still be at its version,
+// otherwise ErrOrderVersionConflict is returned.
+func (r *OrderRepo) UpdateStatus(ctx context.Context, order entity.Order, event entity.OrderEvent) error {
+	transitionsJSON, err := marshalTransitions(order.StatusTransitions)
+	if err != nil {
+		return fmt.Errorf("marshal status transitions: %w", err)
+	}
+
+	query := `
+UPDATE orders SET status = $3, status_transitions = $4, updated_at = $5, version = version + 1
+WHERE id = $1 AND version = $2`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, query, order.Id, order.Version, order.Status, transitionsJSON, order.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update order status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrOrderVersionConflict
+	}
+
+	if err := copyEvents(ctx, tx, []entity.OrderEvent{event}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// CreateRefund stores the refund and adds it to the refunded amounts of its
+// order, which takes the given status, within a single transaction.
+// A change of the status is recorded in the transitions of the order.
+// The order must still be at the version the refund was computed from,
+// otherwise ErrOrderVersionConflict is returned. Refunds that would take
+// more than the pre-tax amount of the order return ErrRefundExceedsOrder.
+// The event of the refund is recorded in the same transaction; the last
+// refund of its snapshot of the order gets the id of the stored refund.
+func (r *OrderRepo) CreateRefund(ctx context.Context, refund entity.OrderRefund, version int, status entity.OrderStatus, event entity.OrderEvent) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the cent fraction absorbs the rounding of the amount refunded in full
+	tag, err := tx.Exec(ctx, `
+UPDATE orders SET
+	refunded_amount = refunded_amount + $2,
+	refunded_tax_amount = refunded_tax_amount + $3,
+	status_transitions = CASE WHEN status = $4 THEN status_transitions
+		ELSE status_transitions || jsonb_build_array(jsonb_build_object('from', status, 'to', $4::order_status, 'at', $5::timestamptz)) END,
+	status = $4,
+	updated_at = $5,
+	version = version + 1
+WHERE id = $1 AND version = $6 AND deleted_at IS NULL AND refunded_amount + $2 <= total_amount - tax_amount + 0.005`,
+		refund.OrderId, refund.Amount, refund.TaxAmount, status, refund.CreatedAt, version,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("update refunded amounts: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		var current int
+		err := tx.QueryRow(ctx, `SELECT version FROM orders WHERE id = $1 AND deleted_at IS NULL`, refund.OrderId).Scan(&current)
+		if err == pgx.ErrNoRows {
+			return 0, entity.ErrOrderNotFound
+		}
+		if err != nil {
+			return 0, fmt.Errorf("query order version: %w", err)
+		}
+		if current != version {
+			return 0, entity.ErrOrderVersionConflict
+		}
+		return 0, entity.ErrRefundExceedsOrder
+	}
+
+	linesJSON, err := json.Marshal(refund.Lines)
+	if err != nil {
+		return 0, fmt.Errorf("marshal refund lines: %w", err)
+	}
+
+	var generatedID int
+	err = tx.QueryRow(ctx, `
+INSERT INTO order_refunds (order_id, amount, tax_amount, lines, reason, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id`,
+		refund.OrderId, refund.Amount, refund.TaxAmount, linesJSON, refund.Reason, refund.CreatedAt,
+	).Scan(&generatedID)
+	if err != nil {
+		return 0, fmt.Errorf("query row insert: %w", err)
+	}
+
+	if event.After != nil && len(event.After.Refunds) > 0 {
+		after := *event.After
+		after.Refunds = slices.Clone(after.Refunds)
+		after.Refunds[len(after.Refunds)-1].Id = generatedID
+		event.After = &after
+	}
+	if err := copyEvents(ctx, tx, []entity.OrderEvent{event}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return generatedID, nil
+}
+
+// getRefunds returns the refunds of the order from the oldest.
+// An order without refunds returns nil.
+func (r *OrderRepo) getRefunds(ctx context.Context, orderID int) ([]entity.OrderRefund, error) {
+	query := `
+SELECT id, order_id, amount, tax_amount, lines, reason, created_at
+FROM order_refunds
+WHERE order_id = $1
+ORDER BY id`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order refunds: %w", err)
+	}
+	defer rows.Close()
+
+	var refunds []entity.OrderRefund
+	for rows.Next() {
+		var refund entity.OrderRefund
+		var linesJSON []byte
+		err := rows.Scan(
+			&refund.Id, &refund.OrderId, &refund.Amount,
+			&refund.TaxAmount, &linesJSON, &refund.Reason, &refund.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order refund: %w", err)
+		}
+		if err := json.Unmarshal(linesJSON, &refund.Lines); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal refund lines: %w", err)
+		}
+		refunds = append(refunds, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed while iterating order refunds: %w", err)
+	}
+
+	return refunds, nil
+}
+
+// orderConditions returns the SQL conditions selecting the orders
+// that match the filter, each starting with AND, and the arguments
+// appended to args that they refer to.
+func orderConditions(filter dto.OrderFilters, args []any) (string, []any) {
+	var conditions string
+	argID := len(args) + 1
+
+	if len(filter.Ids) > 0 {
+		conditions += fmt.Sprintf(" AND id = ANY($%d)", argID)
+		args = append(args, filter.Ids)
+		argID++
+	}
+
+	if filter.ImportId != "" {
+		conditions += fmt.Sprintf(" AND import_id = $%d", argID)
+		args = append(args, filter.ImportId)
+		argID++
+	}
+
+	if filter.CustomerId != nil {
+		conditions += fmt.Sprintf(" AND customer_id = $%d", argID)
+		args = append(args, *filter.CustomerId)
+		argID++
+	}
+
+	if len(filter.Statuses) > 0 {
+		conditions += fmt.Sprintf(" AND status = ANY($%d::order_status[])", argID)
+		args = append(args, filter.Statuses)
+		argID++
+	}
+
+	if filter.State != "" {
+		conditions += fmt.Sprintf(" AND state = $%d", argID)
+		args = append(args, filter.State)
+		argID++
+	}
+
+	if filter.ReportingCode != "" {
+		conditions += fmt.Sprintf(" AND reporting_code = $%d", argID)
+		args = append(args, filter.ReportingCode)
+		argID++
+	}
+
+	if filter.Category != "" {
+		conditions += fmt.Sprintf(" AND category = $%d", argID)
+		args = append(args, filter.Category)
+		argID++
+	}
+
+	if filter.CustomerRef != "" {
+		conditions += fmt.Sprintf(" AND customer_ref = $%d", argID)
+		args = append(args, filter.CustomerRef)
+		argID++
+	}
+
+	if filter.TaxOverride != "" {
+		conditions += fmt.Sprintf(" AND tax_override = $%d", argID)
+		args = append(args, filter.TaxOverride)
+		argID++
+	}
+
+	if filter.Warning != "" {
+		conditions += fmt.Sprintf(" AND warning = $%d", argID)
+		args = append(args, filter.Warning)
+		argID++
+	}
+
+	if filter.GeocodingMethod != "" {
+		conditions += fmt.Sprintf(" AND geocoding_method = $%d", argID)
+		args = append(args, filter.GeocodingMethod)
+		argID++
+	}
+
+	if filter.BoundaryResolved != nil {
+		conditions += fmt.Sprintf(" AND boundary_resolved = $%d", argID)
+		args = append(args, *filter.BoundaryResolved)
+		argID++
+	}
+
+	if filter.TotalAmountMin != nil {
+		conditions += fmt.Sprintf(" AND total_amount >= $%d", argID)
+		args = append(args, *filter.TotalAmountMin)
+		argID++
+	}
+
+	if filter.TotalAmountMax != nil {
+		conditions += fmt.Sprintf(" AND total_amount <= $%d", argID)
+		args = append(args, *filter.TotalAmountMax)
+		argID++
+	}
+
+	if filter.FromDate != nil {
+		conditions += fmt.Sprintf(" AND created_at >= $%d", argID)
+		args = append(args, *filter.FromDate)
+		argID++
+	}
+
+	if filter.ToDate != nil {
+		conditions += fmt.Sprintf(" AND created_at <= $%d", argID)
+		args = append(args, *filter.ToDate)
+		argID++
+	}
+
+	// containment is answered by the GIN indexes of both columns
+	if len(filter.Metadata) > 0 {
+		metadataJSON, _ := json.Marshal(filter.Metadata)
+		conditions += fmt.Sprintf(" AND metadata @> $%d::jsonb", argID)
+		args = append(args, string(metadataJSON))
+		argID++
+	}
+
+	if len(filter.Tags) > 0 {
+		conditions += fmt.Sprintf(" AND tags @> $%d", argID)
+		args = append(args, filter.Tags)
+	}
+
+	return conditions, args
+}
+
+// getItems returns the items of the order by line number.
+// An order without items returns nil.
+func (r *OrderRepo) getItems(ctx context.Context, orderID int) ([]entity.OrderItem, error) {
+	items, err := r.GetItems(ctx, []int{orderID})
+	if err != nil {
+		return nil, err
+	}
+	return items[orderID], nil
+}
+
+// GetItems returns the items of the orders by line number, keyed
+// by order id, in a single query. Orders without items are left out.
+func (r *OrderRepo) GetItems(ctx context.Context, orderIDs []int) (map[int][]entity.OrderItem, error) {
+	query := `
+SELECT
+	order_id, id, line, sku, description, quantity, unit_price,
+	category, discount, amount, tax_override, tax_rate, tax_amount
+FROM order_items
+WHERE order_id = ANY($1)
+ORDER BY order_id, line`
+
+	rows, err := r.pool.Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[int][]entity.OrderItem)
+	for rows.Next() {
+		var orderID int
+		var item entity.OrderItem
+		err := rows.Scan(
+			&orderID, &item.Id, &item.Line, &item.SKU, &item.Description, &item.Quantity, &item.UnitPrice,
+			&item.Category, &item.Discount, &item.Amount, &item.TaxOverride, &item.TaxRate, &item.TaxAmount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order item: %w", err)
+		}
+		items[orderID] = append(items[orderID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed while iterating order items: %w", err)
+	}
+
+	return items, nil
+}
+
+// marshalExplanation serializes the tax explanation of an order.
+// Orders without an explanation store NULL.
+func marshalExplanation(explanation *entity.TaxExplanation) ([]byte, error) {
+	if explanation == nil {
+		return nil, nil
+	}
+	return json.Marshal(explanation)
+}
+
+// marshalOriginalAmounts serializes the amounts of an order placed
+// in another currency. Orders in USD have none and store NULL.
+func marshalOriginalAmounts(amounts *entity.OrderAmounts) ([]byte, error) {
+	if amounts == nil {
+		return nil, nil
+	}
+	return json.Marshal(amounts)
+}
+
+// marshalMetadata serializes the metadata of an order.
+// A nil map is stored as an empty JSON object.
+func marshalMetadata(metadata map[string]string) ([]byte, error) {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return json.Marshal(metadata)
+}
+
+// marshalTransitions serializes the status transitions of an order.
+// Nil transitions are stored as an empty JSON array.
+func marshalTransitions(transitions []entity.StatusTransition) ([]byte, error) {
+	if transitions == nil {
+		transitions = []entity.StatusTransition{}
+	}
+	return json.Marshal(transitions)
+}
+
+// tagsOrEmpty returns the tags of an order, stored as an empty array when nil.
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// marshalNames serializes a list of jurisdiction names.
+// A nil list is stored as an empty JSON array.
+func marshalNames(names []string) ([]byte, error) {
+	if names == nil {
+		names = []string{}
+	}
+	return json.Marshal(names)
+}
+
+// marshalComponents serializes the tax of the order components.
+// A nil list is stored as an empty JSON array.
+func marshalComponents(components []entity.OrderComponentTax) ([]byte, error) {
+	if components == nil {
+		components = []entity.OrderComponentTax{}
+	}
+	return json.Marshal(components)
+}
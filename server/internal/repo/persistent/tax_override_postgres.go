@@ -0,0 +1,117 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/goccy/go-json"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TaxOverrideRepo implements persistence logic for tax overrides
+// created through the admin API.
+type TaxOverrideRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTaxOverrideRepo(pool *pgxpool.Pool) *TaxOverrideRepo {
+	return &TaxOverrideRepo{pool: pool}
+}
+
+// Create inserts a tax override and returns it with the generated id.
+// It returns ErrTaxOverrideAlreadyExists if the name is already taken.
+func (r *TaxOverrideRepo) Create(ctx context.Context, override entity.TaxOverride) (entity.TaxOverride, error) {
+	jurisdictionsJSON, err := json.Marshal(override.Jurisdictions)
+	if err != nil {
+		return entity.TaxOverride{}, fmt.Errorf("marshal jurisdictions: %w", err)
+	}
+
+	componentsJSON, err := json.Marshal(override.Components)
+	if err != nil {
+		return entity.TaxOverride{}, fmt.Errorf("marshal components: %w", err)
+	}
+
+	query := `
+INSERT INTO tax_overrides (
+	name, state, jurisdictions, category, components, rate, starts_at, ends_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at`
+
+	err = r.pool.QueryRow(ctx, query,
+		override.Name,
+		override.State,
+		jurisdictionsJSON,
+		override.Category,
+		componentsJSON,
+		override.Rate,
+		override.StartsAt,
+		override.EndsAt,
+	).Scan(&override.Id, &override.CreatedAt)
+	if err != nil {
+		if isUniqueKeyViolation(err) {
+			return entity.TaxOverride{}, entity.ErrTaxOverrideAlreadyExists
+		}
+		return entity.TaxOverride{}, fmt.Errorf("query row insert: %w", err)
+	}
+
+	return override, nil
+}
+
+// GetAll returns all stored overrides, newest first.
+func (r *TaxOverrideRepo) GetAll(ctx context.Context) ([]entity.TaxOverride, error) {
+	query := `
+SELECT id, name, state, jurisdictions, category, components, rate, starts_at, ends_at, created_at
+FROM tax_overrides
+ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []entity.TaxOverride{}
+	for rows.Next() {
+		var o entity.TaxOverride
+		var jurisdictionsJSON, componentsJSON []byte
+
+		err := rows.Scan(
+			&o.Id, &o.Name, &o.State, &jurisdictionsJSON, &o.Category, &componentsJSON,
+			&o.Rate, &o.StartsAt, &o.EndsAt, &o.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tax override: %w", err)
+		}
+
+		if err := json.Unmarshal(jurisdictionsJSON, &o.Jurisdictions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal jurisdictions: %w", err)
+		}
+		if err := json.Unmarshal(componentsJSON, &o.Components); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal components: %w", err)
+		}
+
+		o.Source = entity.TaxOverrideSourceApi
+		overrides = append(overrides, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed while iterating rows: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// Delete removes a stored override.
+// If no record is found, it returns ErrTaxOverrideNotFound.
+func (r *TaxOverrideRepo) Delete(ctx context.Context, id int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM tax_overrides WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tax override: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrTaxOverrideNotFound
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/goccy/go-json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TaxRecalculationRepo implements persistence logic for tax recalculations.
+// The filter and the previewed changes are stored as JSONB so a preview
+// can be reviewed and applied after application restarts.
+type TaxRecalculationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTaxRecalculationRepo(pool *pgxpool.Pool) *TaxRecalculationRepo {
+	return &TaxRecalculationRepo{pool: pool}
+}
+
+// Create inserts a previewed recalculation and returns its generated id.
+func (r *TaxRecalculationRepo) Create(ctx context.Context, rec entity.TaxRecalculation) (int, error) {
+	filterJSON, err := json.Marshal(rec.Filter)
+	if err != nil {
+		return 0, fmt.Errorf("marshal filter: %w", err)
+	}
+
+	changesJSON, err := json.Marshal(rec.Changes)
+	if err != nil {
+		return 0, fmt.Errorf("marshal changes: %w", err)
+	}
+
+	query := `
+INSERT INTO tax_recalculations (status, rates_version, filter, changes, unchanged, skipped, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id`
+
+	var generatedID int
+	err = r.pool.QueryRow(ctx, query,
+		rec.Status,
+		rec.RatesVersion,
+		filterJSON,
+		changesJSON,
+		rec.Unchanged,
+		rec.Skipped,
+		rec.CreatedAt,
+	).Scan(&generatedID)
+	if err != nil {
+		return 0, fmt.Errorf("query row insert: %w", err)
+	}
+
+	return generatedID, nil
+}
+
+// GetById retrieves a recalculation including its changes.
+// If no record is found, it returns ErrTaxRecalculationNotFound.
+func (r *TaxRecalculationRepo) GetById(ctx context.Context, id int) (entity.TaxRecalculation, error) {
+	query := `
+SELECT id, status, rates_version, filter, changes, unchanged, skipped, applied, conflicts, created_at, applied_at
+FROM tax_recalculations
+WHERE id = $1`
+
+	var rec entity.TaxRecalculation
+	var filterJSON, changesJSON []byte
+
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&rec.Id, &rec.Status, &rec.RatesVersion, &filterJSON, &changesJSON,
+		&rec.Unchanged, &rec.Skipped, &rec.Applied, &rec.Conflicts, &rec.CreatedAt, &rec.AppliedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.TaxRecalculation{}, entity.ErrTaxRecalculationNotFound
+		}
+		return entity.TaxRecalculation{}, fmt.Errorf("failed to query and scan row: %w", err)
+	}
+
+	if err := json.Unmarshal(filterJSON, &rec.Filter); err != nil {
+		return entity.TaxRecalculation{}, fmt.Errorf("failed to unmarshal filter: %w", err)
+	}
+	if err := json.Unmarshal(changesJSON, &rec.Changes); err != nil {
+		return entity.TaxRecalculation{}, fmt.Errorf("failed to unmarshal changes: %w", err)
+	}
+
+	return rec, nil
+}
+
+// StartApply marks a previewed recalculation as being applied.
+// It returns ErrTaxRecalculationNotPending when the recalculation
+// is already applied or being applied, so it is applied only once.
+func (r *TaxRecalculationRepo) StartApply(ctx context.Context, id int) error {
+	query := `
+UPDATE tax_recalculations SET status = 'applying'
+WHERE id = $1 AND status = 'previewed'`
+
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("start apply: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrTaxRecalculationNotPending
+	}
+	return nil
+}
+
+// UpdateProgress stores the status and counters of a recalculation being applied.
+func (r *TaxRecalculationRepo) UpdateProgress(ctx context.Context, rec entity.TaxRecalculation) error {
+	query := `
+UPDATE tax_recalculations SET status = $2, applied = $3, conflicts = $4, applied_at = $5
+WHERE id = $1`
+
+	tag, err := r.pool.Exec(ctx, query, rec.Id, rec.Status, rec.Applied, rec.Conflicts, rec.AppliedAt)
+	if err != nil {
+		return fmt.Errorf("update progress: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrTaxRecalculationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package tax
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
+	"github.com/tidwall/rtree"
+)
+
+// benchGeoJSONFilePath is the committed boundary fixture benchmarks run against:
+// 62 county-sized polygons of 400 vertices each in the NY area.
+// GEOJSON_FILE_PATH overrides it, e.g. with the counties file the server loads.
+const benchGeoJSONFilePath = "testdata/counties.geojson"
+
+// benchFeatures loads the benchmark boundary set and a tax config
+// with an entry for every feature.
+func benchFeatures(b *testing.B) ([]*geojson.Feature, map[string]entity.JurisdictionTax) {
+	b.Helper()
+
+	path := os.Getenv("GEOJSON_FILE_PATH")
+	if path == "" {
+		path = benchGeoJSONFilePath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		b.Skipf("boundary file %s is not available: %v", path, err)
+	}
+	fc, err := geojson.UnmarshalFeatureCollection(data)
+	if err != nil {
+		b.Fatalf("failed to parse %s: %v", path, err)
+	}
+	features := fc.Features
+	b.Logf("benchmarking %d features of %s", len(features), path)
+
+	config := make(map[string]entity.JurisdictionTax, len(features))
+	for _, f := range features {
+		name := f.Properties.MustString(entity.NamePropertyKey, entity.UnknownName)
+		config[name] = entity.JurisdictionTax{CompositeRate: 0.08, Names: []string{name}}
+	}
+	return features, config
+}
+
+// benchPoints returns n random points within the bounds of the features.
+func benchPoints(features []*geojson.Feature, n int) []orb.Point {
+	bound := features[0].Geometry.Bound()
+	for _, f := range features[1:] {
+		bound = bound.Union(f.Geometry.Bound())
+	}
+
+	rnd := rand.New(rand.NewSource(2))
+	points := make([]orb.Point, n)
+	for i := range points {
+		points[i] = orb.Point{
+			bound.Min.X() + rnd.Float64()*(bound.Max.X()-bound.Min.X()),
+			bound.Min.Y() + rnd.Float64()*(bound.Max.Y()-bound.Min.Y()),
+		}
+	}
+	return points
+}
+
+// planarContaining is the containment check without prepared geometries:
+// every candidate feature is converted and checked with planar utilities.
+func planarContaining(tree *rtree.RTreeG[int], features []*geojson.Feature, point orb.Point) []int {
+	var containing []int
+
+	tree.Search([2]float64{point.X(), point.Y()}, [2]float64{point.X(), point.Y()},
+		func(min, max [2]float64, featureIdx int) bool {
+			multiPoly := entity.GetMultiPolygon(features[featureIdx])
+			if multiPoly != nil && planar.MultiPolygonContains(multiPoly, point) {
+				containing = append(containing, featureIdx)
+			}
+			return true
+		},
+	)
+
+	slices.Sort(containing)
+	return containing
+}
+
+func reportLookups(b *testing.B) {
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "lookups/s")
+}
+
+func BenchmarkContainingFeatures(b *testing.B) {
+	features, _ := benchFeatures(b)
+	points := benchPoints(features, 10000)
+	tree := buildIndex(features)
+	geometries := prepareGeometries(features)
+
+	b.Run("planar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			planarContaining(&tree, features, points[i%len(points)])
+		}
+		reportLookups(b)
+	})
+
+	b.Run("prepared", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			containingFeatures(&tree, geometries, points[i%len(points)])
+		}
+		reportLookups(b)
+	})
+}
+
+func BenchmarkGetTaxByLocation(b *testing.B) {
+	features, config := benchFeatures(b)
+	ctx := context.Background()
+
+	for _, bc := range []struct {
+		name   string
+		points int
+		opts   Options
+	}{
+		{name: "uncached", points: 10000},
+		{name: "cached_hits", points: 1000, opts: Options{CacheSize: 10000}},
+		{name: "cached_misses", points: 100000, opts: Options{CacheSize: 10000}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			tx := New(features, config, nil, bc.opts)
+			points := benchPoints(features, bc.points)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p := points[i%len(points)]
+				tx.GetTaxByLocation(ctx, p.Y(), p.X())
+			}
+			reportLookups(b)
+		})
+	}
+}
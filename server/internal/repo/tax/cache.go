@@ -0,0 +1,132 @@
+package tax
+
+import (
+	"container/list"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+)
+
+// defaultCachePrecision is the number of decimal places lookup coordinates
+// are rounded to when the cache is enabled, about 0.11 m of latitude.
+const defaultCachePrecision = 6
+
+// cacheKey identifies a cached lookup by its rounded coordinates.
+type cacheKey struct {
+	lon, lat int64
+	snap     bool
+}
+
+// cacheEntry is a cached lookup result, including out-of-scope results.
+type cacheEntry struct {
+	key cacheKey
+	tax *entity.LocationTax
+	ok  bool
+}
+
+// lookupCache is a least recently used cache of lookup results
+// safe for concurrent use.
+type lookupCache struct {
+	mu      sync.Mutex
+	size    int
+	scale   float64
+	entries map[cacheKey]*list.Element
+	order   *list.List
+}
+
+// newLookupCache returns a cache of the given number of entries keyed by
+// coordinates rounded to precision decimal places, or nil if size is not
+// positive. A nil cache is valid and caches nothing.
+func newLookupCache(size, precision int) *lookupCache {
+	if size <= 0 {
+		return nil
+	}
+
+	return &lookupCache{
+		size:    size,
+		scale:   math.Pow10(precision),
+		entries: make(map[cacheKey]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// key returns the cache key of the point, its coordinates rounded to the
+// cache precision. A cached result is shared by every point of the rounding
+// cell, so only results holding for the whole cell are put in the cache.
+func (c *lookupCache) key(point orb.Point, snap bool) cacheKey {
+	return cacheKey{
+		lon:  int64(math.Round(point.X() * c.scale)),
+		lat:  int64(math.Round(point.Y() * c.scale)),
+		snap: snap,
+	}
+}
+
+// cell returns the bounds of the points sharing the key, widened
+// slightly against rounding errors.
+func (c *lookupCache) cell(key cacheKey) orb.Bound {
+	half := 0.51 / c.scale
+	lon, lat := float64(key.lon)/c.scale, float64(key.lat)/c.scale
+	return orb.Bound{Min: orb.Point{lon - half, lat - half}, Max: orb.Point{lon + half, lat + half}}
+}
+
+func (c *lookupCache) get(key cacheKey) (*entity.LocationTax, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, false
+	}
+	c.order.MoveToFront(el)
+
+	entry := el.Value.(*cacheEntry)
+	return cloneLocationTax(entry.tax), entry.ok, true
+}
+
+func (c *lookupCache) put(key cacheKey, tax *entity.LocationTax, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, exists := c.entries[key]; exists {
+		el.Value = &cacheEntry{key: key, tax: cloneLocationTax(tax), ok: ok}
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, tax: cloneLocationTax(tax), ok: ok})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// purge drops every cached result, e.g. after the boundaries change.
+func (c *lookupCache) purge() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.order.Init()
+}
+
+// cloneLocationTax copies the result, so that callers modifying
+// a returned tax never affect the cached one.
+func cloneLocationTax(tax *entity.LocationTax) *entity.LocationTax {
+	if tax == nil {
+		return nil
+	}
+
+	clone := *tax
+	clone.Names = slices.Clone(tax.Names)
+	clone.Matches = slices.Clone(tax.Matches)
+	return &clone
+}
@@ -0,0 +1,45 @@
+package tax
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/tidwall/rtree"
+)
+
+// newExplanation returns an empty explanation of the location.
+func newExplanation(lat, lon float64) entity.TaxExplanation {
+	return entity.TaxExplanation{
+		Latitude:  lat,
+		Longitude: lon,
+		Layers:    []entity.LayerMatch{},
+		Steps:     []entity.ComputationStep{},
+	}
+}
+
+// explainCandidates returns every feature whose bounding box contains
+// the point, ordered by index, and whether the feature itself contains it.
+func explainCandidates(tree *rtree.RTreeG[int], features []*geojson.Feature, geometries []*preparedGeometry, point orb.Point, propertyKey string) []entity.FeatureMatch {
+	candidates := []entity.FeatureMatch{}
+
+	tree.Search([2]float64{point.X(), point.Y()}, [2]float64{point.X(), point.Y()},
+		func(min, max [2]float64, featureIdx int) bool {
+			candidates = append(candidates, entity.FeatureMatch{
+				Index:    featureIdx,
+				Name:     features[featureIdx].Properties.MustString(propertyKey, entity.UnknownName),
+				Contains: geometries[featureIdx].contains(point),
+			})
+			return true
+		},
+	)
+
+	slices.SortFunc(candidates, func(a, b entity.FeatureMatch) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	return candidates
+}
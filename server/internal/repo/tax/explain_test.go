@@ -0,0 +1,63 @@
+package tax
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestMultiState_ExplainTaxByLocation(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{
+		"A": {CompositeRate: 0.08, Breakdown: entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04}, Code: "0001"},
+		"B": {CompositeRate: 0.07, Breakdown: entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.03}, Code: "0002"},
+	}
+
+	// the bounding box of the triangle contains the point, the triangle does not
+	triangle := geojson.NewFeature(orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 0}}})
+	triangle.Properties[entity.NamePropertyKey] = "Triangle"
+
+	states := NewMultiState(New(
+		[]*geojson.Feature{triangle, squareFeature("B", 0, 0, 2, 2), squareFeature("A", 0, 0, 1, 1)},
+		config,
+		[]entity.TaxLayer{{
+			Name:    "cities",
+			Level:   entity.TaxLayerLevelCity,
+			Rates:   map[string]entity.LayerRate{"Town": {Rate: 0.01}},
+			GeoJSON: &entity.GeoJSON{Features: []*geojson.Feature{squareFeature("Town", 0, 0, 1, 1)}},
+		}},
+		Options{},
+	))
+
+	tax, explanation, ok := states.ExplainTaxByLocation(context.Background(), 0.75, 0.25)
+	if !ok {
+		t.Fatal("expected tax to be found")
+	}
+
+	if len(explanation.Candidates) != 3 {
+		t.Fatalf("expected 3 candidates, got %+v", explanation.Candidates)
+	}
+	if explanation.Candidates[0].Contains || !explanation.Candidates[1].Contains || !explanation.Candidates[2].Contains {
+		t.Errorf("unexpected containment %+v", explanation.Candidates)
+	}
+	if explanation.Winner == nil || explanation.Winner.Index != 1 || explanation.Winner.Name != "B" {
+		t.Errorf("expected B to win by index priority, got %+v", explanation.Winner)
+	}
+	if explanation.Jurisdiction == nil || explanation.Jurisdiction.Code != "0002" {
+		t.Errorf("unexpected jurisdiction %+v", explanation.Jurisdiction)
+	}
+	if len(explanation.Layers) != 1 || !explanation.Layers[0].Applied {
+		t.Errorf("expected city layer to be applied, got %+v", explanation.Layers)
+	}
+	if len(explanation.Steps) != 2 || explanation.Steps[1].CompositeRate != tax.CompositeRate {
+		t.Errorf("unexpected steps %+v", explanation.Steps)
+	}
+
+	_, explanation, ok = states.ExplainTaxByLocation(context.Background(), 10, 10)
+	if ok || len(explanation.Candidates) != 0 || explanation.Winner != nil {
+		t.Errorf("expected out of scope explanation without candidates, got %+v", explanation)
+	}
+}
@@ -0,0 +1,165 @@
+package tax
+
+import (
+	"math"
+	"slices"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
+	"github.com/tidwall/rtree"
+)
+
+const (
+	// metersPerDegree is the length of one degree of latitude.
+	metersPerDegree = 111_320.0
+
+	// edgeNudge is the offset in degrees (about 0.1 mm) used to decide
+	// on which side of a shared edge a point lies.
+	edgeNudge = 1e-9
+)
+
+// findContaining returns the index of the feature containing the point.
+// Candidates are narrowed down by the R-tree before the exact
+// point-in-polygon check, then resolved by pickContaining.
+func findContaining(tree *rtree.RTreeG[int], geometries []*preparedGeometry, point orb.Point) (idx int, onEdge bool, ok bool) {
+	return pickContaining(geometries, containingFeatures(tree, geometries, point), point)
+}
+
+// pickContaining picks one of the features containing the point.
+// When several features contain it, the one with the lowest index wins
+// unless the point lies on an edge they share; such points are resolved
+// by resolveEdge and reported as onEdge.
+func pickContaining(geometries []*preparedGeometry, containing []int, point orb.Point) (idx int, onEdge bool, ok bool) {
+	switch len(containing) {
+	case 0:
+		return 0, false, false
+	case 1:
+		return containing[0], false, true
+	}
+
+	if idx, ok := resolveEdge(geometries, containing, point); ok {
+		return idx, true, true
+	}
+	return containing[0], false, true
+}
+
+// containingFeatures returns the indexes of all features containing the point
+// in ascending order. Points on a polygon boundary are considered in.
+func containingFeatures(tree *rtree.RTreeG[int], geometries []*preparedGeometry, point orb.Point) []int {
+	var containing []int
+
+	tree.Search([2]float64{point.X(), point.Y()}, [2]float64{point.X(), point.Y()},
+		func(min, max [2]float64, featureIdx int) bool {
+			if geometries[featureIdx].contains(point) {
+				containing = append(containing, featureIdx)
+			}
+			return true
+		},
+	)
+
+	slices.Sort(containing)
+	return containing
+}
+
+// crossesBoundary reports whether the boundary of any feature
+// touches the bounds.
+func crossesBoundary(tree *rtree.RTreeG[int], geometries []*preparedGeometry, bound orb.Bound) bool {
+	crosses := false
+
+	tree.Search([2]float64{bound.Min.X(), bound.Min.Y()}, [2]float64{bound.Max.X(), bound.Max.Y()},
+		func(min, max [2]float64, featureIdx int) bool {
+			crosses = geometries[featureIdx].crosses(bound)
+			return !crosses
+		},
+	)
+
+	return crosses
+}
+
+// resolveEdge picks the owner of a point lying on an edge shared by
+// several containing features, independently of the feature order:
+// the point belongs to the feature that contains it when moved slightly
+// to the north-east. It returns false when the point is inside all
+// of the features, i.e. they overlap rather than touch.
+func resolveEdge(geometries []*preparedGeometry, containing []int, point orb.Point) (int, bool) {
+	nudged := orb.Point{point.X() + edgeNudge, point.Y() + edgeNudge}
+
+	owner, owners := 0, 0
+	for _, idx := range containing {
+		if geometries[idx].contains(nudged) {
+			if owners == 0 {
+				owner = idx
+			}
+			owners++
+		}
+	}
+
+	if owners == 0 || owners == len(containing) {
+		return 0, false
+	}
+	return owner, true
+}
+
+// featureNames returns the names of the features at the given indexes.
+func featureNames(features []*geojson.Feature, indexes []int, propertyKey string) []string {
+	names := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		names = append(names, features[idx].Properties.MustString(propertyKey, entity.UnknownName))
+	}
+	return names
+}
+
+// findNearest returns the index of the feature nearest to the point
+// within toleranceMeters and the distance to it in meters.
+// Ties are resolved by the lowest feature index.
+func findNearest(tree *rtree.RTreeG[int], geometries []*preparedGeometry, point orb.Point, toleranceMeters float64) (int, float64, bool) {
+	dLat := toleranceMeters / metersPerDegree
+	dLon := dLat / math.Max(math.Cos(point.Y()*math.Pi/180), 1e-6)
+
+	bestIdx, bestDistance := len(geometries), math.Inf(1)
+
+	tree.Search([2]float64{point.X() - dLon, point.Y() - dLat}, [2]float64{point.X() + dLon, point.Y() + dLat},
+		func(min, max [2]float64, featureIdx int) bool {
+			geometry := geometries[featureIdx]
+			if geometry == nil {
+				return true
+			}
+
+			distance := distanceMeters(geometry.multiPoly, point)
+			if distance < bestDistance || (distance == bestDistance && featureIdx < bestIdx) {
+				bestIdx, bestDistance = featureIdx, distance
+			}
+			return true
+		},
+	)
+
+	if bestIdx == len(geometries) || bestDistance > toleranceMeters {
+		return 0, 0, false
+	}
+	return bestIdx, bestDistance, true
+}
+
+// distanceMeters approximates the distance in meters from the point to the
+// nearest polygon boundary. Coordinates are projected equirectangularly
+// around the point, which is accurate enough for distances of a few kilometers.
+func distanceMeters(multiPoly orb.MultiPolygon, point orb.Point) float64 {
+	scale := math.Cos(point.Y() * math.Pi / 180)
+	project := func(p orb.Point) orb.Point {
+		return orb.Point{p.X() * scale, p.Y()}
+	}
+
+	p := project(point)
+	best := math.Inf(1)
+	for _, poly := range multiPoly {
+		for _, ring := range poly {
+			for i := 0; i+1 < len(ring); i++ {
+				best = math.Min(best, planar.DistanceFromSegment(project(ring[i]), project(ring[i+1]), p))
+			}
+		}
+	}
+
+	return best * metersPerDegree
+}
@@ -0,0 +1,228 @@
+package tax
+
+import (
+	"math"
+	"slices"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/tidwall/rtree"
+)
+
+// overlayTolerance is the distance (in degrees) from a boundary at which
+// the sides of the boundary are sampled when comparing geometries. Overlaps
+// and gaps narrower than that are treated as a shared border.
+const overlayTolerance = 1e-7
+
+// segment is a ring edge oriented so that the interior
+// of its polygon lies on its left.
+type segment struct {
+	a, b orb.Point
+}
+
+// overlayGeometry is a geometry prepared for comparison with others:
+// its boundary segments are indexed by bounding box, so that only the
+// segments near the other geometry are visited.
+type overlayGeometry struct {
+	prepared *preparedGeometry
+	segments rtree.RTreeG[segment]
+}
+
+func newOverlayGeometry(multiPoly orb.MultiPolygon) *overlayGeometry {
+	g := &overlayGeometry{prepared: prepareGeometry(multiPoly)}
+	insertSegments(&g.segments, multiPoly)
+	return g
+}
+
+// insertSegments adds every ring edge of the geometry to the tree,
+// outer rings counter-clockwise and holes clockwise.
+func insertSegments(tree *rtree.RTreeG[segment], multiPoly orb.MultiPolygon) {
+	for _, poly := range multiPoly {
+		for ri, ring := range poly {
+			reverse := (ri == 0) != (ring.Orientation() == orb.CCW)
+			for i := 0; i+1 < len(ring); i++ {
+				s := segment{ring[i], ring[i+1]}
+				if reverse {
+					s.a, s.b = s.b, s.a
+				}
+				if s.a == s.b {
+					continue
+				}
+				min, max := s.bound()
+				tree.Insert(min, max, s)
+			}
+		}
+	}
+}
+
+func (s segment) bound() ([2]float64, [2]float64) {
+	return [2]float64{math.Min(s.a.X(), s.b.X()), math.Min(s.a.Y(), s.b.Y())},
+		[2]float64{math.Max(s.a.X(), s.b.X()), math.Max(s.a.Y(), s.b.Y())}
+}
+
+// sidePoints splits the segment at its crossings with the segments of
+// other and returns a point next to the middle of every piece, at
+// overlayTolerance on the inner side of the segment, or on the outer
+// side when outer is set. A piece does not cross the other boundary,
+// so its sample tells on which side of it the whole piece lies.
+func sidePoints(s segment, other *rtree.RTreeG[segment], outer bool) []orb.Point {
+	ts := []float64{0, 1}
+	min, max := s.bound()
+	other.Search(min, max, func(_, _ [2]float64, o segment) bool {
+		ts = appendCrossings(ts, s, o)
+		return true
+	})
+	slices.Sort(ts)
+
+	dx, dy := s.b.X()-s.a.X(), s.b.Y()-s.a.Y()
+	length := math.Hypot(dx, dy)
+	nx, ny := -dy/length*overlayTolerance, dx/length*overlayTolerance
+	if outer {
+		nx, ny = -nx, -ny
+	}
+
+	points := make([]orb.Point, 0, len(ts)-1)
+	for i := 1; i < len(ts); i++ {
+		if ts[i] == ts[i-1] {
+			continue
+		}
+		t := (ts[i-1] + ts[i]) / 2
+		points = append(points, orb.Point{s.a.X() + dx*t + nx, s.a.Y() + dy*t + ny})
+	}
+
+	return points
+}
+
+// appendCrossings appends the positions along s, as a fraction of its
+// length, where o crosses or touches it. A collinear segment splits s
+// at its endpoints.
+func appendCrossings(ts []float64, s, o segment) []float64 {
+	dx, dy := s.b.X()-s.a.X(), s.b.Y()-s.a.Y()
+	ex, ey := o.b.X()-o.a.X(), o.b.Y()-o.a.Y()
+	wx, wy := o.a.X()-s.a.X(), o.a.Y()-s.a.Y()
+
+	if denom := dx*ey - dy*ex; denom != 0 {
+		t := (wx*ey - wy*ex) / denom
+		u := (wx*dy - wy*dx) / denom
+		if t > 0 && t < 1 && u >= 0 && u <= 1 {
+			ts = append(ts, t)
+		}
+		return ts
+	}
+
+	length2 := dx*dx + dy*dy
+	for _, p := range [2]orb.Point{o.a, o.b} {
+		vx, vy := p.X()-s.a.X(), p.Y()-s.a.Y()
+		if math.Abs(vx*dy-vy*dx) > overlayTolerance*math.Sqrt(length2) {
+			continue
+		}
+		if t := (vx*dx + vy*dy) / length2; t > 0 && t < 1 {
+			ts = append(ts, t)
+		}
+	}
+
+	return ts
+}
+
+// overlap reports whether the interiors of two geometries intersect.
+// Where they do, the boundary of the common area is made of pieces of
+// their boundaries with both interiors on the inner side, so the sides
+// of every boundary piece near the other geometry are sampled.
+func overlap(first, second *overlayGeometry) bool {
+	return hasCommonInterior(first, second) || hasCommonInterior(second, first)
+}
+
+func hasCommonInterior(g, other *overlayGeometry) bool {
+	found := false
+	bound := other.prepared.bound
+
+	g.segments.Search([2]float64{bound.Min.X(), bound.Min.Y()}, [2]float64{bound.Max.X(), bound.Max.Y()},
+		func(_, _ [2]float64, s segment) bool {
+			for _, p := range sidePoints(s, &other.segments, false) {
+				if g.prepared.contains(p) && other.prepared.contains(p) {
+					found = true
+					return false
+				}
+			}
+			return true
+		},
+	)
+
+	return found
+}
+
+// findGaps reports areas inside the outline covered by no feature.
+// Such an area is bordered by pieces of feature boundaries with the area
+// on their outer side, or by pieces of the outline with the area on the
+// inner side, so the sides of every boundary piece are sampled. At most
+// one gap is reported per bordering feature, and one for the outline.
+func findGaps(features []*geojson.Feature, propertyKey string, geometries []*overlayGeometry, outline orb.MultiPolygon) []entity.BoundaryGap {
+	gaps := []entity.BoundaryGap{}
+	if len(outline) == 0 {
+		return gaps
+	}
+
+	boundary := newOverlayGeometry(outline)
+
+	var bounds rtree.RTreeG[int]
+	var segments rtree.RTreeG[segment]
+	boundary.segments.Scan(func(min, max [2]float64, s segment) bool {
+		segments.Insert(min, max, s)
+		return true
+	})
+	for i, g := range geometries {
+		if g == nil {
+			continue
+		}
+		g.segments.Scan(func(min, max [2]float64, s segment) bool {
+			segments.Insert(min, max, s)
+			return true
+		})
+		bounds.Insert([2]float64{g.prepared.bound.Min.X(), g.prepared.bound.Min.Y()}, [2]float64{g.prepared.bound.Max.X(), g.prepared.bound.Max.Y()}, i)
+	}
+
+	uncovered := func(p orb.Point) bool {
+		if !boundary.prepared.contains(p) {
+			return false
+		}
+		covered := false
+		bounds.Search([2]float64{p.X(), p.Y()}, [2]float64{p.X(), p.Y()}, func(_, _ [2]float64, i int) bool {
+			covered = geometries[i].prepared.contains(p)
+			return !covered
+		})
+		return !covered
+	}
+
+	// firstGap returns a point of an uncovered area bordering the geometry.
+	firstGap := func(g *overlayGeometry, outer bool) (orb.Point, bool) {
+		var gap orb.Point
+		found := false
+		g.segments.Scan(func(_, _ [2]float64, s segment) bool {
+			for _, p := range sidePoints(s, &segments, outer) {
+				if uncovered(p) {
+					gap, found = p, true
+					return false
+				}
+			}
+			return true
+		})
+		return gap, found
+	}
+
+	for i, g := range geometries {
+		if g == nil {
+			continue
+		}
+		if p, ok := firstGap(g, true); ok {
+			gaps = append(gaps, entity.BoundaryGap{Name: featureName(features[i], propertyKey), FeatureIndex: i, Latitude: p.Y(), Longitude: p.X()})
+		}
+	}
+
+	if p, ok := firstGap(boundary, false); ok {
+		gaps = append(gaps, entity.BoundaryGap{FeatureIndex: -1, Latitude: p.Y(), Longitude: p.X()})
+	}
+
+	return gaps
+}
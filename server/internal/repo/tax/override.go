@@ -0,0 +1,44 @@
+package tax
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+// GetOverride returns the first active override matching the reporting code
+// and category at the given time. Overrides are evaluated in the order
+// they were passed to ReplaceOverrides.
+func (r *Tax) GetOverride(ctx context.Context, reportingCode, category string, at time.Time) (*entity.TaxOverride, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := range r.overrides {
+		if r.overrides[i].Matches(reportingCode, category, at) {
+			override := r.overrides[i]
+			return &override, true
+		}
+	}
+	return nil, false
+}
+
+// GetActiveOverrides returns the overrides currently evaluated by lookups.
+// The returned slice must be treated as read-only.
+func (r *Tax) GetActiveOverrides(ctx context.Context) []entity.TaxOverride {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.overrides
+}
+
+// ReplaceOverrides atomically swaps the overrides evaluated by lookups.
+func (r *Tax) ReplaceOverrides(ctx context.Context, overrides []entity.TaxOverride) {
+	version := fingerprint(overrides)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.overrides = overrides
+	r.overridesVersion = version
+}
@@ -0,0 +1,270 @@
+package tax
+
+import (
+	"math"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// edgesPerSlab is the average number of ring edges per vertical slab
+// of a prepared ring. Rings with fewer edges are kept in a single slab.
+const edgesPerSlab = 8
+
+// preparedGeometry is a feature geometry prepared once for repeated
+// point-in-polygon checks. Every ring is split into vertical slabs of equal
+// width, each holding the edges whose x range overlaps it, so that a check
+// only visits the edges of a single slab instead of the whole ring.
+type preparedGeometry struct {
+	// multiPoly is the original geometry, used for distance computations.
+	multiPoly orb.MultiPolygon
+
+	bound    orb.Bound
+	polygons [][]preparedRing
+}
+
+// preparedRing is a ring whose edges are indexed by vertical slab.
+type preparedRing struct {
+	bound orb.Bound
+	width float64
+	slabs [][]edge
+}
+
+// edge is a ring segment ordered by x coordinate.
+type edge struct {
+	s, e orb.Point
+}
+
+// prepareGeometries prepares the geometry of every feature. Features without
+// a polygonal geometry get a nil entry and never contain any point.
+func prepareGeometries(features []*geojson.Feature) []*preparedGeometry {
+	prepared := make([]*preparedGeometry, len(features))
+	for i, f := range features {
+		if multiPoly := entity.GetMultiPolygon(f); multiPoly != nil {
+			prepared[i] = prepareGeometry(multiPoly)
+		}
+	}
+	return prepared
+}
+
+func prepareGeometry(multiPoly orb.MultiPolygon) *preparedGeometry {
+	g := &preparedGeometry{
+		multiPoly: multiPoly,
+		bound:     multiPoly.Bound(),
+		polygons:  make([][]preparedRing, 0, len(multiPoly)),
+	}
+
+	for _, poly := range multiPoly {
+		if len(poly) == 0 {
+			continue
+		}
+
+		rings := make([]preparedRing, 0, len(poly))
+		for _, ring := range poly {
+			rings = append(rings, prepareRing(ring))
+		}
+		g.polygons = append(g.polygons, rings)
+	}
+
+	return g
+}
+
+func prepareRing(ring orb.Ring) preparedRing {
+	r := preparedRing{bound: ring.Bound()}
+	if len(ring) == 0 {
+		return r
+	}
+
+	slabs := max(1, len(ring)/edgesPerSlab)
+	if r.bound.Max.X() > r.bound.Min.X() {
+		r.width = (r.bound.Max.X() - r.bound.Min.X()) / float64(slabs)
+	} else {
+		slabs = 1
+	}
+	r.slabs = make([][]edge, slabs)
+
+	// The closing segment is checked as well, like planar.RingContains does.
+	add := func(s, e orb.Point) {
+		if s.X() > e.X() {
+			s, e = e, s
+		}
+		for i := r.slab(s.X()); i <= r.slab(e.X()); i++ {
+			r.slabs[i] = append(r.slabs[i], edge{s, e})
+		}
+	}
+
+	add(ring[0], ring[len(ring)-1])
+	for i := 0; i+1 < len(ring); i++ {
+		add(ring[i], ring[i+1])
+	}
+
+	return r
+}
+
+// slab returns the index of the slab covering the x coordinate.
+func (r *preparedRing) slab(x float64) int {
+	if r.width == 0 {
+		return 0
+	}
+	return min(max(int((x-r.bound.Min.X())/r.width), 0), len(r.slabs)-1)
+}
+
+// contains reports whether the point is inside the geometry.
+// Points on the boundary are considered in, and the result is the same
+// as of planar.MultiPolygonContains on the original geometry.
+func (g *preparedGeometry) contains(point orb.Point) bool {
+	if g == nil || !g.bound.Contains(point) {
+		return false
+	}
+
+	for _, rings := range g.polygons {
+		if polygonContains(rings, point) {
+			return true
+		}
+	}
+	return false
+}
+
+func polygonContains(rings []preparedRing, point orb.Point) bool {
+	if !rings[0].contains(point) {
+		return false
+	}
+
+	for i := 1; i < len(rings); i++ {
+		if rings[i].contains(point) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains casts a ray from the point through the edges of its slab only.
+// Edges outside of the slab cannot cross the ray nor hold the point,
+// since their x range does not include the x coordinate of the point.
+func (r *preparedRing) contains(point orb.Point) bool {
+	if len(r.slabs) == 0 || !r.bound.Contains(point) {
+		return false
+	}
+
+	in := false
+	for _, e := range r.slabs[r.slab(point.X())] {
+		intersects, on := rayIntersect(point, e.s, e.e)
+		if on {
+			return true
+		}
+		if intersects {
+			in = !in
+		}
+	}
+	return in
+}
+
+// crosses reports whether any ring edge of the geometry touches the bounds.
+// Only the edges of the slabs overlapping the bounds are visited.
+func (g *preparedGeometry) crosses(bound orb.Bound) bool {
+	if g == nil || !g.bound.Intersects(bound) {
+		return false
+	}
+
+	for _, rings := range g.polygons {
+		for i := range rings {
+			r := &rings[i]
+			if len(r.slabs) == 0 || !r.bound.Intersects(bound) {
+				continue
+			}
+			for s := r.slab(bound.Min.X()); s <= r.slab(bound.Max.X()); s++ {
+				for _, e := range r.slabs[s] {
+					if e.intersects(bound) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// intersects reports whether the edge touches the bounds,
+// by clipping it to the bounds (Liang-Barsky).
+func (e edge) intersects(bound orb.Bound) bool {
+	dx, dy := e.e.X()-e.s.X(), e.e.Y()-e.s.Y()
+	t0, t1 := 0.0, 1.0
+
+	for _, c := range [4][2]float64{
+		{-dx, e.s.X() - bound.Min.X()},
+		{dx, bound.Max.X() - e.s.X()},
+		{-dy, e.s.Y() - bound.Min.Y()},
+		{dy, bound.Max.Y() - e.s.Y()},
+	} {
+		p, q := c[0], c[1]
+		switch {
+		case p == 0 && q < 0:
+			return false
+		case p < 0:
+			t0 = max(t0, q/p)
+		case p > 0:
+			t1 = min(t1, q/p)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+// rayIntersect reports whether a vertical ray from p crosses the edge
+// from s to e, where s.X() <= e.X(), and whether p lies on the edge.
+// It follows the ray casting of planar.RingContains, including its handling
+// of degenerate cases, so that prepared and plain checks always agree.
+func rayIntersect(p, s, e orb.Point) (intersects, on bool) {
+	if p[0] == s[0] {
+		if p[1] == s[1] {
+			return false, true
+		} else if s[0] == e[0] {
+			if s[1] > e[1] && s[1] >= p[1] && p[1] >= e[1] {
+				return false, true
+			}
+			if e[1] > s[1] && e[1] >= p[1] && p[1] >= s[1] {
+				return false, true
+			}
+		}
+
+		// Move the point off the vertex to deal with the degenerate case.
+		p[0] = math.Nextafter(p[0], math.Inf(1))
+	} else if p[0] == e[0] {
+		if p[1] == e[1] {
+			return false, true
+		}
+
+		p[0] = math.Nextafter(p[0], math.Inf(1))
+	}
+
+	if p[0] < s[0] || p[0] > e[0] {
+		return false, false
+	}
+
+	if s[1] > e[1] {
+		if p[1] > s[1] {
+			return false, false
+		} else if p[1] < e[1] {
+			return true, false
+		}
+	} else {
+		if p[1] > e[1] {
+			return false, false
+		} else if p[1] < s[1] {
+			return true, false
+		}
+	}
+
+	rs := (p[1] - s[1]) / (p[0] - s[0])
+	ds := (e[1] - s[1]) / (e[0] - s[0])
+
+	if rs == ds {
+		return false, true
+	}
+
+	return rs <= ds, false
+}
@@ -0,0 +1,153 @@
+package tax
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
+)
+
+// jaggedFeature returns a polygon of n vertices with a random radius
+// around the center and a square hole in the middle.
+func jaggedFeature(name string, rnd *rand.Rand, cx, cy, radius float64, n int) *geojson.Feature {
+	outer := make(orb.Ring, 0, n+1)
+	for i := 0; i < n; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		r := radius * (0.6 + 0.4*rnd.Float64())
+		outer = append(outer, orb.Point{cx + r*math.Cos(angle), cy + r*math.Sin(angle)})
+	}
+	outer = append(outer, outer[0])
+
+	h := radius / 10
+	hole := orb.Ring{{cx - h, cy - h}, {cx - h, cy + h}, {cx + h, cy + h}, {cx + h, cy - h}, {cx - h, cy - h}}
+
+	f := geojson.NewFeature(orb.Polygon{outer, hole})
+	f.Properties[entity.NamePropertyKey] = name
+	return f
+}
+
+func TestPreparedGeometry_MatchesPlanar(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	features := []*geojson.Feature{
+		jaggedFeature("Jagged", rnd, 0, 0, 1, 500),
+		squareFeature("Square", 2, 2, 3, 3),
+		squareFeature("Flat", 4, 4, 4, 5),
+	}
+
+	for _, f := range features {
+		multiPoly := entity.GetMultiPolygon(f)
+		prepared := prepareGeometry(multiPoly)
+
+		var points []orb.Point
+		for _, poly := range multiPoly {
+			for _, ring := range poly {
+				for i := 0; i+1 < len(ring); i++ {
+					// vertices, edge midpoints and points next to them
+					mid := orb.Point{(ring[i].X() + ring[i+1].X()) / 2, (ring[i].Y() + ring[i+1].Y()) / 2}
+					points = append(points, ring[i], mid, orb.Point{mid.X() + 1e-9, mid.Y()}, orb.Point{ring[i].X(), ring[i].Y() - 1e-9})
+				}
+			}
+		}
+		bound := multiPoly.Bound().Pad(0.1)
+		for i := 0; i < 10000; i++ {
+			points = append(points, orb.Point{
+				bound.Min.X() + rnd.Float64()*(bound.Max.X()-bound.Min.X()),
+				bound.Min.Y() + rnd.Float64()*(bound.Max.Y()-bound.Min.Y()),
+			})
+		}
+
+		for _, p := range points {
+			if got, want := prepared.contains(p), planar.MultiPolygonContains(multiPoly, p); got != want {
+				t.Fatalf("%s: contains(%v) = %v, want %v", f.Properties[entity.NamePropertyKey], p, got, want)
+			}
+		}
+	}
+
+	var missing *preparedGeometry
+	if missing.contains(orb.Point{0, 0}) {
+		t.Error("expected geometry of a feature without polygon to contain nothing")
+	}
+}
+
+func TestGetTaxByLocation_Cache(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{
+		"A": {CompositeRate: 0.04, Names: []string{"A"}, Code: "A"},
+		"B": {CompositeRate: 0.08, Names: []string{"B"}, Code: "B"},
+	}
+	tx := New([]*geojson.Feature{squareFeature("A", 0, 0, 1, 1)}, config, nil, Options{CacheSize: 2, CachePrecision: 3})
+	ctx := context.Background()
+
+	got, ok := tx.GetTaxByLocation(ctx, 0.5, 0.5)
+	if !ok || got.Code != "A" {
+		t.Fatalf("expected A, got %+v", got)
+	}
+	got.Names[0] = "modified"
+
+	// rounds to the same cell as the first lookup
+	got, ok = tx.GetTaxByLocation(ctx, 0.5001, 0.4999)
+	if !ok || got.Names[0] != "A" {
+		t.Fatalf("expected cached A unaffected by callers, got %+v", got)
+	}
+
+	// rounds onto the boundary, but lies outside of the square
+	if _, ok := tx.GetTaxByLocation(ctx, 1.0004, 0.5); ok {
+		t.Error("expected location outside of the square not to be found")
+	}
+
+	if _, ok := tx.GetTaxByLocation(ctx, 5, 5); ok {
+		t.Error("expected out-of-scope location not to be found")
+	}
+	if _, ok := tx.GetTaxByLocation(ctx, 5, 5); ok {
+		t.Error("expected cached out-of-scope location not to be found")
+	}
+
+	tx.ReplaceFeatures(ctx, []*geojson.Feature{squareFeature("B", 0, 0, 1, 1)})
+	if got, ok := tx.GetTaxByLocation(ctx, 0.5, 0.5); !ok || got.Code != "B" {
+		t.Errorf("expected B after boundaries changed, got %+v", got)
+	}
+	if tx.cache.order.Len() > 2 {
+		t.Errorf("expected at most 2 cached lookups, got %d", tx.cache.order.Len())
+	}
+}
+
+func TestGetTaxByLocation_CacheNearBoundary(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{
+		"A": {CompositeRate: 0.04, Code: "A"},
+		"B": {CompositeRate: 0.08, Code: "B"},
+	}
+	features := []*geojson.Feature{squareFeature("A", 0, 0, 1, 1), squareFeature("B", 1, 0, 2, 1)}
+	tx := New(features, config, nil, Options{CacheSize: 10, CachePrecision: 1, ToleranceMeters: 50_000})
+	ctx := context.Background()
+
+	// both round to the cell of (1.0, 0.5), crossed by the shared edge
+	if got, ok := tx.GetTaxByLocation(ctx, 0.5, 0.98); !ok || got.Code != "A" {
+		t.Fatalf("expected A, got %+v", got)
+	}
+	if got, ok := tx.GetTaxByLocation(ctx, 0.5, 1.02); !ok || got.Code != "B" {
+		t.Fatalf("expected B next to the edge, got %+v", got)
+	}
+
+	// both round to the cell of (2.3, 0.5), outside of every square
+	first, ok := tx.GetTaxByLocation(ctx, 0.5, 2.26)
+	if !ok || first.Code != "B" || first.BoundaryDistance == 0 {
+		t.Fatalf("expected snapped B, got %+v", first)
+	}
+	second, ok := tx.GetTaxByLocation(ctx, 0.5, 2.34)
+	if !ok || second.BoundaryDistance <= first.BoundaryDistance {
+		t.Fatalf("expected distance of the location itself, got %+v", second)
+	}
+
+	if got, ok := tx.GetTaxByLocation(ctx, 0.5, 0.5); !ok || got.Code != "A" {
+		t.Fatalf("expected A, got %+v", got)
+	}
+	if tx.cache.order.Len() != 1 {
+		t.Errorf("expected only the lookup away from boundaries cached, got %d", tx.cache.order.Len())
+	}
+}
@@ -0,0 +1,202 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// MultiState resolves taxes across the datasets of several states loaded
+// side by side. Every state is served by its own Tax engine with its own
+// boundaries, property key, rate table, tax layers and overrides.
+// A location belongs to the first state containing it. Snapping to a nearby
+// boundary is only attempted when no state contains the location, so that
+// a point is never snapped across a state line.
+// The first state is the primary one, used where no state is given.
+type MultiState struct {
+	states []*Tax
+}
+
+func NewMultiState(primary *Tax, others ...*Tax) *MultiState {
+	return &MultiState{states: append([]*Tax{primary}, others...)}
+}
+
+// GetTaxByLocation resolves the tax of the location in the first state
+// containing it. If no state contains the location, it returns false;
+// such a location is outside of every configured state.
+func (m *MultiState) GetTaxByLocation(ctx context.Context, lat, lon float64) (*entity.LocationTax, bool) {
+	tax, _, ok := m.resolve(orb.Point{lon, lat}, false)
+	return tax, ok
+}
+
+// ExplainTaxByLocation resolves the tax of the location like GetTaxByLocation
+// and explains the resolution in the state it was found in,
+// or in the primary state for out-of-scope locations.
+func (m *MultiState) ExplainTaxByLocation(ctx context.Context, lat, lon float64) (*entity.LocationTax, entity.TaxExplanation, bool) {
+	tax, explanation, ok := m.resolve(orb.Point{lon, lat}, true)
+	return tax, *explanation, ok
+}
+
+// resolve looks the point up in every state, first by containment only,
+// then with snapping. A state resolves the point when it either finds
+// its tax or reports it as ambiguous under AmbiguityPolicyFail.
+// When explain is set, the explanation of the resolving state is returned,
+// or the one of the primary state if no state resolves the point.
+func (m *MultiState) resolve(point orb.Point, explain bool) (*entity.LocationTax, *entity.TaxExplanation, bool) {
+	var primary *entity.TaxExplanation
+
+	for _, snap := range []bool{false, true} {
+		for i, state := range m.states {
+			if snap && state.opts.ToleranceMeters == 0 {
+				continue
+			}
+
+			var explanation *entity.TaxExplanation
+			if explain {
+				e := newExplanation(point.Y(), point.X())
+				explanation = &e
+				if i == 0 && !snap {
+					primary = explanation
+				}
+			}
+
+			if tax, ok := state.locate(point, snap, explanation); tax != nil {
+				return tax, explanation, ok
+			}
+		}
+	}
+
+	return nil, primary, false
+}
+
+// GetTaxByJurisdiction returns the tax of a jurisdiction of the given state
+// by its tax config name, with the layers of the state at the location.
+// An empty state means the primary state.
+func (m *MultiState) GetTaxByJurisdiction(ctx context.Context, state, name string, lat, lon float64) (*entity.LocationTax, bool) {
+	s, ok := m.state(state)
+	if !ok {
+		return nil, false
+	}
+	return s.GetTaxByJurisdiction(ctx, name, lat, lon)
+}
+
+// GetOverride returns the first active override of the given state
+// matching the reporting code and category at the given time.
+// An empty state means the primary state.
+func (m *MultiState) GetOverride(ctx context.Context, state, reportingCode, category string, at time.Time) (*entity.TaxOverride, bool) {
+	s, ok := m.state(state)
+	if !ok {
+		return nil, false
+	}
+	return s.GetOverride(ctx, reportingCode, category, at)
+}
+
+// GetActiveOverrides returns the overrides currently evaluated by lookups
+// of every state, state by state in the order they were passed
+// to ReplaceOverrides.
+func (m *MultiState) GetActiveOverrides(ctx context.Context) []entity.TaxOverride {
+	var overrides []entity.TaxOverride
+	for _, s := range m.states {
+		overrides = append(overrides, s.GetActiveOverrides(ctx)...)
+	}
+	return overrides
+}
+
+// ReplaceOverrides hands every override to the engine of its state,
+// keeping their order. Overrides without a state go to the primary state;
+// overrides of unknown states are dropped.
+func (m *MultiState) ReplaceOverrides(ctx context.Context, overrides []entity.TaxOverride) {
+	byState := make(map[*Tax][]entity.TaxOverride, len(m.states))
+	for _, o := range overrides {
+		if s, ok := m.state(o.State); ok {
+			byState[s] = append(byState[s], o)
+		}
+	}
+
+	for _, s := range m.states {
+		s.ReplaceOverrides(ctx, byState[s])
+	}
+}
+
+// GetActiveFeatures returns the boundary features of the given state
+// currently used for lookups, or nil for an unknown state.
+// An empty state means the primary state.
+func (m *MultiState) GetActiveFeatures(ctx context.Context, state string) []*geojson.Feature {
+	s, ok := m.state(state)
+	if !ok {
+		return nil
+	}
+	return s.GetActiveFeatures(ctx)
+}
+
+// ValidateFeatures validates boundary features against the tax config
+// of the given state. Every feature of an unknown state is reported invalid.
+// An empty state means the primary state.
+func (m *MultiState) ValidateFeatures(ctx context.Context, state string, features []*geojson.Feature) entity.BoundaryValidationReport {
+	s, ok := m.state(state)
+	if !ok {
+		return entity.BoundaryValidationReport{Errors: []entity.BoundaryIssue{{FeatureIndex: -1, Message: fmt.Sprintf("unknown state %q", state)}}}
+	}
+	return s.ValidateFeatures(ctx, features)
+}
+
+// DiffFeatures compares boundary features with the active ones
+// of the given state. An empty state means the primary state.
+func (m *MultiState) DiffFeatures(ctx context.Context, state string, features []*geojson.Feature) entity.BoundaryDiff {
+	s, ok := m.state(state)
+	if !ok {
+		return entity.BoundaryDiff{}
+	}
+	return s.DiffFeatures(ctx, features)
+}
+
+// ReplaceFeatures swaps the boundary features of the given state,
+// leaving the other states untouched. Features of an unknown state
+// are dropped. An empty state means the primary state.
+func (m *MultiState) ReplaceFeatures(ctx context.Context, state string, features []*geojson.Feature) {
+	if s, ok := m.state(state); ok {
+		s.ReplaceFeatures(ctx, features)
+	}
+}
+
+// PrimaryState returns the code of the primary state.
+func (m *MultiState) PrimaryState(ctx context.Context) string {
+	return m.states[0].opts.State
+}
+
+// HasState reports whether the state is configured.
+// An empty state means the primary state.
+func (m *MultiState) HasState(ctx context.Context, state string) bool {
+	_, ok := m.state(state)
+	return ok
+}
+
+// state returns the engine of the state, or of the primary state
+// when the state is empty.
+func (m *MultiState) state(state string) (*Tax, bool) {
+	if state == "" {
+		return m.states[0], true
+	}
+
+	for _, s := range m.states {
+		if s.opts.State == state {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// DataVersion fingerprints the boundary features and overrides
+// currently used by every state.
+func (m *MultiState) DataVersion(ctx context.Context) string {
+	var version string
+	for _, s := range m.states {
+		version += s.DataVersion(ctx)
+	}
+	return fingerprint(version)
+}
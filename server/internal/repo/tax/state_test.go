@@ -0,0 +1,156 @@
+package tax
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestMultiState(t *testing.T) {
+	ny := New(
+		[]*geojson.Feature{squareFeature("Richmond", 0, 0, 1, 1)},
+		map[string]entity.JurisdictionTax{"Richmond": {CompositeRate: 0.08875, Code: "NY-1"}},
+		nil,
+		Options{State: "NY", ToleranceMeters: 5000},
+	)
+
+	union := squareFeature("", 1.001, 0, 2, 1)
+	union.Properties["COUNTY"] = "Union"
+	nj := New(
+		[]*geojson.Feature{union},
+		map[string]entity.JurisdictionTax{"Union": {CompositeRate: 0.06625, Code: "NJ-1"}},
+		nil,
+		Options{State: "NJ", PropertyKey: "COUNTY"},
+	)
+
+	states := NewMultiState(ny, nj)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		found    bool
+		state    string
+		code     string
+	}{
+		{name: "primary_state", lat: 0.5, lon: 0.5, found: true, state: "NY", code: "NY-1"},
+		{name: "additional_state_property_key", lat: 0.5, lon: 1.5, found: true, state: "NJ", code: "NJ-1"},
+		{name: "contained_before_snapped_across_state_line", lat: 0.5, lon: 1.0015, found: true, state: "NJ", code: "NJ-1"},
+		{name: "snapped_in_primary_state", lat: 0.5, lon: 1.0005, found: true, state: "NY", code: "NY-1"},
+		{name: "no_configured_state", lat: 5, lon: 5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := states.GetTaxByLocation(ctx, tc.lat, tc.lon)
+			if ok != tc.found {
+				t.Fatalf("found %v, want %v", ok, tc.found)
+			}
+			if ok && (got.State != tc.state || got.Code != tc.code) {
+				t.Errorf("got %s/%s, want %s/%s", got.State, got.Code, tc.state, tc.code)
+			}
+
+			_, explanation, ok := states.ExplainTaxByLocation(ctx, tc.lat, tc.lon)
+			if ok != tc.found || explanation.State != tc.state {
+				t.Errorf("explained state %q found %v, want %q %v", explanation.State, ok, tc.state, tc.found)
+			}
+		})
+	}
+
+	if got, ok := states.GetTaxByJurisdiction(ctx, "NJ", "Union", 0.5, 1.5); !ok || got.State != "NJ" {
+		t.Errorf("expected Union of NJ, got %+v", got)
+	}
+	if got, ok := states.GetTaxByJurisdiction(ctx, "", "Richmond", 0.5, 0.5); !ok || got.State != "NY" {
+		t.Errorf("expected Richmond of the primary state, got %+v", got)
+	}
+	if _, ok := states.GetTaxByJurisdiction(ctx, "NJ", "Richmond", 0.5, 0.5); ok {
+		t.Error("expected jurisdiction of another state not to be found")
+	}
+}
+
+func TestMultiState_PerState(t *testing.T) {
+	ny := New(
+		[]*geojson.Feature{squareFeature("Richmond", 0, 0, 1, 1)},
+		map[string]entity.JurisdictionTax{"Richmond": {CompositeRate: 0.08, Code: "0001"}},
+		nil,
+		Options{State: "NY"},
+	)
+	nj := New(
+		[]*geojson.Feature{squareFeature("Union", 2, 0, 3, 1)},
+		map[string]entity.JurisdictionTax{"Union": {CompositeRate: 0.06, Code: "0001"}},
+		[]entity.TaxLayer{{
+			Name:    "cities",
+			Level:   entity.TaxLayerLevelCity,
+			Rates:   map[string]entity.LayerRate{"Elizabeth": {Rate: 0.01, Name: "Elizabeth city"}},
+			GeoJSON: &entity.GeoJSON{Features: []*geojson.Feature{squareFeature("Elizabeth", 2, 0, 2.5, 1)}},
+		}},
+		Options{State: "NJ"},
+	)
+
+	states := NewMultiState(ny, nj)
+	ctx := context.Background()
+	at := time.Date(2026, 5, 11, 12, 0, 0, 0, time.UTC)
+
+	if got, ok := states.GetTaxByLocation(ctx, 0.5, 2.25); !ok || math.Abs(got.CompositeRate-0.07) > 1e-9 {
+		t.Errorf("expected the layer of NJ to apply, got %+v", got)
+	}
+	if got, ok := states.GetTaxByLocation(ctx, 0.5, 0.25); !ok || got.CompositeRate != 0.08 {
+		t.Errorf("expected NY without layers, got %+v", got)
+	}
+
+	holiday := entity.TaxOverride{Name: "holiday", Jurisdictions: []string{"0001"}, StartsAt: at.Add(-time.Hour), EndsAt: at.Add(time.Hour)}
+	njHoliday, unknown := holiday, holiday
+	njHoliday.Name, njHoliday.State = "nj holiday", "NJ"
+	unknown.Name, unknown.State = "unknown", "CA"
+	states.ReplaceOverrides(ctx, []entity.TaxOverride{njHoliday, holiday, unknown})
+
+	if got, ok := states.GetOverride(ctx, "NJ", "0001", "", at); !ok || got.Name != "nj holiday" {
+		t.Errorf("expected the override of NJ, got %+v", got)
+	}
+	for _, state := range []string{"", "NY"} {
+		if got, ok := states.GetOverride(ctx, state, "0001", "", at); !ok || got.Name != "holiday" {
+			t.Errorf("expected the override of the primary state for %q, got %+v", state, got)
+		}
+	}
+	if _, ok := states.GetOverride(ctx, "CA", "0001", "", at); ok {
+		t.Error("expected no override of an unknown state")
+	}
+	if got := states.GetActiveOverrides(ctx); len(got) != 2 || got[0].Name != "holiday" || got[1].Name != "nj holiday" {
+		t.Errorf("unexpected active overrides %+v", got)
+	}
+
+	if !states.HasState(ctx, "") || !states.HasState(ctx, "NJ") || states.HasState(ctx, "CA") {
+		t.Error("unexpected configured states")
+	}
+
+	if got := states.PrimaryState(ctx); got != "NY" {
+		t.Errorf("expected primary state NY, got %q", got)
+	}
+
+	moved := []*geojson.Feature{squareFeature("Union", 4, 0, 5, 1)}
+	if report := states.ValidateFeatures(ctx, "NJ", moved); !report.Valid {
+		t.Errorf("expected features of NJ to be valid, got %+v", report)
+	}
+	if report := states.ValidateFeatures(ctx, "", moved); len(report.UncoveredJurisdictions) != 1 || report.UncoveredJurisdictions[0] != "Richmond" {
+		t.Errorf("expected features to be validated against the primary state, got %+v", report)
+	}
+	if report := states.ValidateFeatures(ctx, "CA", moved); report.Valid {
+		t.Error("expected features of an unknown state to be invalid")
+	}
+
+	states.ReplaceFeatures(ctx, "NJ", moved)
+	if got, ok := states.GetTaxByLocation(ctx, 0.5, 4.5); !ok || got.State != "NJ" {
+		t.Errorf("expected the replaced boundaries of NJ, got %+v", got)
+	}
+	if got, ok := states.GetTaxByLocation(ctx, 0.5, 0.5); !ok || got.State != "NY" {
+		t.Errorf("expected the boundaries of NY to stay, got %+v", got)
+	}
+	if got := states.GetActiveFeatures(ctx, "NJ"); len(got) != 1 || got[0] != moved[0] {
+		t.Errorf("unexpected active features of NJ %+v", got)
+	}
+}
@@ -2,24 +2,41 @@ package tax
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"sync"
 
 	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
 
 	"github.com/paulmach/orb"
 	"github.com/paulmach/orb/geojson"
-	"github.com/paulmach/orb/planar"
 	"github.com/tidwall/rtree"
 )
 
 // Tax implements geospatial tax lookup logic.
 // It determines jurisdiction tax data based on geographic coordinates.
 // The implementation uses an R-tree spatial index for efficient
-// bounding box filtering before performing precise polygon checks.
+// bounding box filtering before performing precise polygon checks
+// against geometries prepared once per boundary set.
 type Tax struct {
+	// mu guards features, geometries, tree and overrides, which are swapped
+	// when a new boundary set is activated or overrides change at runtime.
+	mu sync.RWMutex
+
 	// features contains all geojson features representing
 	// jurisdiction geometries (polygons or multipolygons).
 	features []*geojson.Feature
 
+	// featuresVersion and overridesVersion fingerprint the features
+	// and overrides, which change at runtime unlike the rest of the data.
+	featuresVersion  string
+	overridesVersion string
+
+	// geometries holds the prepared geometry of every feature by index.
+	geometries []*preparedGeometry
+
 	// taxConfig maps jurisdiction names to their corresponding tax configuration.
 	// The key must match the name stored in feature properties.
 	taxConfig map[string]entity.JurisdictionTax
@@ -27,67 +44,383 @@ type Tax struct {
 	// tree is an R-tree spatial index used to quickly narrow down
 	// candidate geometries by bounding box intersection.
 	tree rtree.RTreeG[int]
+
+	// layers are additional boundary layers (e.g. cities or special districts)
+	// whose rates are added on top of the matched jurisdiction tax.
+	layers []layer
+
+	// overrides are time-bounded rate changes, e.g. sales tax holidays,
+	// in the order of their precedence.
+	overrides []entity.TaxOverride
+
+	// cache holds recent lookup results; nil when caching is disabled.
+	cache *lookupCache
+
+	opts Options
+}
+
+// Options configures how locations are matched to boundary features.
+type Options struct {
+	// State is the code of the state whose dataset the engine serves,
+	// recorded on every resolved tax.
+	State string
+
+	// PropertyKey is the feature property holding the jurisdiction name.
+	// Empty means entity.NamePropertyKey.
+	PropertyKey string
+
+	// ToleranceMeters is the maximum distance of a location outside of every
+	// jurisdiction boundary that is still snapped to the nearest jurisdiction.
+	// Zero disables snapping.
+	ToleranceMeters float64
+
+	// AmbiguityPolicy picks the jurisdiction of a location inside several
+	// overlapping jurisdictions. Empty means AmbiguityPolicyFirstWins.
+	AmbiguityPolicy entity.AmbiguityPolicy
+
+	// CacheSize is the number of lookup results kept in an LRU cache
+	// keyed by rounded coordinates. Zero disables the cache.
+	CacheSize int
+
+	// CachePrecision is the number of decimal places coordinates are
+	// rounded to when the cache is enabled. Zero means 6, about 0.11 m.
+	// Lookups near a boundary are never cached, so a coarser precision
+	// only lowers the hit rate.
+	CachePrecision int
+
+	// Outline is the boundary of the state. Areas inside it covered
+	// by no feature are reported on validation; nil skips the check.
+	Outline orb.MultiPolygon
+}
+
+// layer is a tax layer together with its own spatial index.
+type layer struct {
+	entity.TaxLayer
+	features   []*geojson.Feature
+	geometries []*preparedGeometry
+	tree       rtree.RTreeG[int]
 }
 
 // New constructs a Tax service instance.
 // It builds an R-tree index from provided geojson features
-// by inserting their bounding boxes for efficient spatial search.
-func New(features []*geojson.Feature, taxConfig map[string]entity.JurisdictionTax) *Tax {
+// by inserting their bounding boxes for efficient spatial search
+// and prepares every feature geometry for point-in-polygon checks.
+// Every additional layer gets its own index and prepared geometries.
+func New(features []*geojson.Feature, taxConfig map[string]entity.JurisdictionTax, layers []entity.TaxLayer, opts Options) *Tax {
+	if opts.PropertyKey == "" {
+		opts.PropertyKey = entity.NamePropertyKey
+	}
+	if opts.CachePrecision == 0 {
+		opts.CachePrecision = defaultCachePrecision
+	}
+
+	indexed := make([]layer, 0, len(layers))
+	for _, l := range layers {
+		var lf []*geojson.Feature
+		if l.GeoJSON != nil {
+			lf = l.GeoJSON.Features
+		}
+		if l.PropertyKey == "" {
+			l.PropertyKey = entity.NamePropertyKey
+		}
+		indexed = append(indexed, layer{TaxLayer: l, features: lf, geometries: prepareGeometries(lf), tree: buildIndex(lf)})
+	}
+
+	return &Tax{
+		features:         features,
+		featuresVersion:  fingerprint(features),
+		overridesVersion: fingerprint([]entity.TaxOverride(nil)),
+		geometries:       prepareGeometries(features),
+		taxConfig:        taxConfig,
+		tree:             buildIndex(features),
+		layers:           indexed,
+		cache:            newLookupCache(opts.CacheSize, opts.CachePrecision),
+		opts:             opts,
+	}
+}
+
+// buildIndex inserts the bounding box of every feature with geometry
+// into a new R-tree, using the feature index as the stored value.
+func buildIndex(features []*geojson.Feature) rtree.RTreeG[int] {
 	var tr rtree.RTreeG[int]
 
 	for i, f := range features {
-		if f.Geometry == nil {
+		if f == nil || f.Geometry == nil {
 			continue
 		}
 		bound := f.Geometry.Bound()
 		tr.Insert([2]float64{bound.Min.X(), bound.Min.Y()}, [2]float64{bound.Max.X(), bound.Max.Y()}, i)
 	}
 
-	return &Tax{
-		features:  features,
-		taxConfig: taxConfig,
-		tree:      tr,
-	}
+	return tr
 }
 
 // GetTaxByLocation determines tax configuration by geographic coordinates.
 // It performs the following steps:
 // 1. Creates a point from longitude and latitude.
 // 2. Searches the R-tree for candidate geometries whose bounding boxes contain the point.
-// 3. Performs an exact point-in-polygon check using planar geometry utilities.
-// 4. Selects the first matching jurisdiction based on feature index priority.
-// 5. Returns the associated tax configuration if found.
-// If no jurisdiction matches the location or no tax configuration exists
-// for the matched name, the function returns false.
-func (r *Tax) GetTaxByLocation(ctx context.Context, lat, lon float64) (*entity.JurisdictionTax, bool) {
-	point := orb.Point{lon, lat}
+// 3. Performs an exact point-in-polygon check against the prepared geometries.
+// 4. Selects the first matching jurisdiction based on feature index priority;
+// a point on an edge shared by several features is resolved by resolveEdge.
+// A point inside several overlapping features is reported as ambiguous
+// together with all matches, and resolved by the configured ambiguity policy.
+// 5. If no jurisdiction contains the point, snaps it to the nearest one
+// within the configured tolerance.
+// 6. Resolves every additional layer the same way and adds the rate of the
+// matched layer feature to the breakdown component of the layer level.
+// 7. Returns the assembled tax configuration if found.
+// If no jurisdiction matches the location, the function returns nil and false.
+// If no tax configuration exists for the matched name, or under
+// AmbiguityPolicyFail for an ambiguous location, it returns false together
+// with the partial result, e.g. the matches, so that the lookup is told
+// apart from a location outside of the state.
+// With the cache enabled, the result is cached by the location rounded
+// to the cache precision and reused for locations rounding alike, unless
+// a boundary crosses the rounding cell or the location was snapped.
+func (r *Tax) GetTaxByLocation(ctx context.Context, lat, lon float64) (*entity.LocationTax, bool) {
+	return r.locate(orb.Point{lon, lat}, true, nil)
+}
+
+// locate resolves the tax of the point under the read lock.
+// Lookups without explanation go through the cache when it is enabled.
+func (r *Tax) locate(point orb.Point, snap bool, explanation *entity.TaxExplanation) (*entity.LocationTax, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.cache == nil || explanation != nil {
+		return r.lookup(point, snap, explanation)
+	}
+
+	key := r.cache.key(point, snap)
+	if tax, ok, hit := r.cache.get(key); hit {
+		return tax, ok
+	}
+
+	tax, ok := r.lookup(point, snap, nil)
+	if r.cacheable(r.cache.cell(key), tax, ok, snap) {
+		r.cache.put(key, tax, ok)
+	}
+	return tax, ok
+}
+
+// cacheable reports whether the result of a lookup holds for every point
+// of the cell: no boundary of a jurisdiction or layer crosses the cell, and
+// the location was neither resolved on an edge nor snapped, nor left out of
+// scope while snapping, since the distance to the nearest jurisdiction
+// varies within the cell.
+func (r *Tax) cacheable(cell orb.Bound, tax *entity.LocationTax, ok, snap bool) bool {
+	if ok && tax.BoundaryResolved {
+		return false
+	}
+	if !ok && snap && r.opts.ToleranceMeters > 0 {
+		return false
+	}
+
+	if crossesBoundary(&r.tree, r.geometries, cell) {
+		return false
+	}
+	for i := range r.layers {
+		if crossesBoundary(&r.layers[i].tree, r.layers[i].geometries, cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup resolves the tax of the point as described in GetTaxByLocation.
+// Snapping to the nearest jurisdiction is only attempted when snap is set.
+// When explanation is not nil, the candidates, matches and rate steps
+// of the resolution are recorded in it.
+func (r *Tax) lookup(point orb.Point, snap bool, explanation *entity.TaxExplanation) (*entity.LocationTax, bool) {
+	result := entity.LocationTax{State: r.opts.State}
 	foundName := entity.UnknownName
-	bestIdx := len(r.features)
 
-	r.tree.Search([2]float64{point.X(), point.Y()}, [2]float64{point.X(), point.Y()},
-		func(min, max [2]float64, featureIdx int) bool {
-			if featureIdx >= bestIdx {
-				return true
-			}
+	containing := containingFeatures(&r.tree, r.geometries, point)
+	idx, onEdge, found := pickContaining(r.geometries, containing, point)
+	switch {
+	case found && len(containing) > 1 && !onEdge:
+		result.Ambiguous = true
+		result.Matches = featureNames(r.features, containing, r.opts.PropertyKey)
 
-			feature := r.features[featureIdx]
-			multiPoly := entity.GetMultiPolygon(feature)
-			if multiPoly == nil {
-				return true
-			}
+		switch r.opts.AmbiguityPolicy {
+		case entity.AmbiguityPolicyHighestRate:
+			idx = r.highestRate(containing)
+		case entity.AmbiguityPolicyFail:
+			found = false
+		}
+	case found:
+		result.BoundaryResolved = onEdge
+	case snap && r.opts.ToleranceMeters > 0:
+		var distance float64
+		idx, distance, found = findNearest(&r.tree, r.geometries, point, r.opts.ToleranceMeters)
+		result.BoundaryResolved = found
+		result.BoundaryDistance = distance
+	}
+	if found {
+		foundName = r.features[idx].Properties.MustString(r.opts.PropertyKey, entity.UnknownName)
+	}
 
-			if planar.MultiPolygonContains(multiPoly, point) {
-				bestIdx = featureIdx
-				foundName = feature.Properties.MustString(entity.NamePropertyKey, entity.UnknownName)
+	if explanation != nil {
+		explanation.Candidates = explainCandidates(&r.tree, r.features, r.geometries, point, r.opts.PropertyKey)
+		if found {
+			explanation.State = r.opts.State
+			explanation.Winner = &entity.FeatureMatch{
+				Index:    idx,
+				Name:     foundName,
+				Contains: result.BoundaryDistance == 0,
+				Distance: result.BoundaryDistance,
 			}
-			return true
-		},
-	)
+		}
+		explanation.BoundaryResolved = result.BoundaryResolved
+		explanation.Ambiguous = result.Ambiguous
+	}
 
 	tax, ok := r.taxConfig[foundName]
 	if !ok {
+		// a matched jurisdiction without a tax is a failed lookup
+		// in this state rather than no match at all
+		if result.Ambiguous || found {
+			return &result, false
+		}
 		return nil, false
 	}
 
-	return &tax, true
+	if explanation != nil {
+		jurisdiction := tax
+		explanation.Jurisdiction = &jurisdiction
+	}
+	explanation.AddStep(entity.ComputationStepJurisdiction, foundName, tax.Breakdown, tax.CompositeRate)
+
+	result.JurisdictionTax = r.applyLayers(tax, point, explanation)
+	return &result, true
+}
+
+// applyLayers resolves every additional layer at the point and adds
+// the rate of the matched layer feature to the tax, as described
+// in GetTaxByLocation. When explanation is not nil, the layer matches
+// and rate steps are recorded in it.
+func (r *Tax) applyLayers(tax entity.JurisdictionTax, point orb.Point, explanation *entity.TaxExplanation) entity.JurisdictionTax {
+	tax.Names = slices.Clone(tax.Names)
+	for i := range r.layers {
+		l := &r.layers[i]
+
+		var match *entity.LayerMatch
+		if explanation != nil {
+			explanation.Layers = append(explanation.Layers, entity.LayerMatch{
+				Layer:      l.Name,
+				Level:      l.Level,
+				Candidates: explainCandidates(&l.tree, l.features, l.geometries, point, l.PropertyKey),
+			})
+			match = &explanation.Layers[len(explanation.Layers)-1]
+		}
+
+		idx, _, ok := findContaining(&l.tree, l.geometries, point)
+		if !ok {
+			continue
+		}
+
+		name := l.features[idx].Properties.MustString(l.PropertyKey, entity.UnknownName)
+		if match != nil {
+			match.Winner = &entity.FeatureMatch{Index: idx, Name: name, Contains: true}
+		}
+
+		rate, ok := l.Rates[name]
+		if !ok || !tax.Breakdown.AddLayerRate(l.Level, rate.Rate) {
+			continue
+		}
+
+		tax.CompositeRate += rate.Rate
+		if rate.Name != "" {
+			tax.Names = append(tax.Names, rate.Name)
+		}
+
+		if match != nil {
+			match.Rate = rate.Rate
+			match.Applied = true
+		}
+		explanation.AddStep(entity.ComputationStepLayer, l.Name, tax.Breakdown, tax.CompositeRate)
+	}
+
+	return tax
+}
+
+// GetTaxByJurisdiction returns the tax of a jurisdiction by its tax config name,
+// e.g. for orders geocoded by a ZIP-to-jurisdiction mapping. Additional layers
+// are composed like in GetTaxByLocation, resolved at the given location,
+// e.g. the centroid of the ZIP code.
+func (r *Tax) GetTaxByJurisdiction(ctx context.Context, name string, lat, lon float64) (*entity.LocationTax, bool) {
+	tax, ok := r.taxConfig[name]
+	if !ok {
+		return nil, false
+	}
+
+	return &entity.LocationTax{JurisdictionTax: r.applyLayers(tax, orb.Point{lon, lat}, nil), State: r.opts.State}, true
+}
+
+// State returns the code of the state served by the engine.
+func (r *Tax) State() string {
+	return r.opts.State
+}
+
+// highestRate returns the containing feature with the highest composite
+// rate in the tax config. Ties are resolved by the lowest feature index.
+func (r *Tax) highestRate(containing []int) int {
+	best, bestRate := containing[0], -1.0
+	for _, idx := range containing {
+		name := r.features[idx].Properties.MustString(r.opts.PropertyKey, entity.UnknownName)
+		if tax, ok := r.taxConfig[name]; ok && tax.CompositeRate > bestRate {
+			best, bestRate = idx, tax.CompositeRate
+		}
+	}
+	return best
+}
+
+// GetActiveFeatures returns the boundary features currently used for lookups.
+// The returned slice must be treated as read-only.
+func (r *Tax) GetActiveFeatures(ctx context.Context) []*geojson.Feature {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.features
+}
+
+// ReplaceFeatures atomically swaps the boundary features used for lookups.
+// The spatial index and the prepared geometries are rebuilt before the swap,
+// so concurrent lookups observe either the previous or the new boundary set,
+// never a mix. Cached lookup results are dropped together with the swap.
+func (r *Tax) ReplaceFeatures(ctx context.Context, features []*geojson.Feature) {
+	tree := buildIndex(features)
+	geometries := prepareGeometries(features)
+	version := fingerprint(features)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.features = features
+	r.featuresVersion = version
+	r.geometries = geometries
+	r.tree = tree
+	r.cache.purge()
+}
+
+// DataVersion fingerprints the boundary features and the overrides
+// currently used for lookups, which change at runtime when a boundary set
+// is activated or overrides are created or deleted.
+func (r *Tax) DataVersion(ctx context.Context) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.featuresVersion + r.overridesVersion
+}
+
+// fingerprint returns a short hash of the JSON encoding of v.
+// Values that cannot be encoded all share the empty fingerprint.
+func fingerprint(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
 }
@@ -0,0 +1,237 @@
+package tax
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestGetTaxByLocation_Layers(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{
+		"County": {
+			CompositeRate: 0.08,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+			Names:         []string{"State", "County"},
+			Code:          "0001",
+		},
+	}
+
+	city := squareFeature("Town", 0, 0, 1, 1)
+	district := squareFeature("District", 0, 0, 2, 2)
+	district.Properties["DISTRICT"] = "District"
+
+	tx := New(
+		[]*geojson.Feature{squareFeature("County", 0, 0, 4, 4)},
+		config,
+		[]entity.TaxLayer{
+			{
+				Name:    "cities",
+				Level:   entity.TaxLayerLevelCity,
+				Rates:   map[string]entity.LayerRate{"Town": {Rate: 0.01, Name: "Town city"}},
+				GeoJSON: &entity.GeoJSON{Features: []*geojson.Feature{city}},
+			},
+			{
+				Name:        "districts",
+				Level:       entity.TaxLayerLevelSpecial,
+				PropertyKey: "DISTRICT",
+				Rates:       map[string]entity.LayerRate{"District": {Rate: 0.00375, Name: "Transit District"}},
+				GeoJSON:     &entity.GeoJSON{Features: []*geojson.Feature{district}},
+			},
+		},
+		Options{},
+	)
+
+	tests := []struct {
+		name      string
+		lat, lon  float64
+		composite float64
+		names     []string
+	}{
+		{name: "all_layers", lat: 0.5, lon: 0.5, composite: 0.09375, names: []string{"State", "County", "Town city", "Transit District"}},
+		{name: "district_only", lat: 1.5, lon: 1.5, composite: 0.08375, names: []string{"State", "County", "Transit District"}},
+		{name: "base_only", lat: 3, lon: 3, composite: 0.08, names: []string{"State", "County"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tx.GetTaxByLocation(context.Background(), tc.lat, tc.lon)
+			if !ok {
+				t.Fatal("expected tax to be found")
+			}
+			if math.Abs(got.CompositeRate-tc.composite) > 1e-9 {
+				t.Errorf("composite rate %v, want %v", got.CompositeRate, tc.composite)
+			}
+			if !slices.Equal(got.Names, tc.names) {
+				t.Errorf("names %v, want %v", got.Names, tc.names)
+			}
+
+			byName, ok := tx.GetTaxByJurisdiction(context.Background(), "County", tc.lat, tc.lon)
+			if !ok || byName.CompositeRate != got.CompositeRate || !slices.Equal(byName.Names, tc.names) {
+				t.Errorf("jurisdiction tax %+v, want the tax of the location %+v", byName, got)
+			}
+		})
+	}
+
+	if !slices.Equal(config["County"].Names, []string{"State", "County"}) {
+		t.Errorf("tax config was mutated: %v", config["County"].Names)
+	}
+
+	if _, ok := tx.GetTaxByLocation(context.Background(), 10, 10); ok {
+		t.Error("expected location outside of boundaries to be out of scope")
+	}
+}
+
+func TestGetTaxByLocation_Boundaries(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{
+		"West": {CompositeRate: 0.04, Names: []string{"West"}, Code: "0001"},
+		"East": {CompositeRate: 0.08, Names: []string{"East"}, Code: "0002"},
+	}
+	west := squareFeature("West", 0, 0, 1, 1)
+	east := squareFeature("East", 1, 0, 2, 1)
+
+	t.Run("shared_edge_independent_of_feature_order", func(t *testing.T) {
+		for _, features := range [][]*geojson.Feature{{west, east}, {east, west}} {
+			tx := New(features, config, nil, Options{})
+
+			got, ok := tx.GetTaxByLocation(context.Background(), 0.5, 1)
+			if !ok {
+				t.Fatal("expected tax to be found")
+			}
+			if got.Code != "0002" {
+				t.Errorf("code %q, want the north-east feature 0002", got.Code)
+			}
+			if !got.BoundaryResolved || got.BoundaryDistance != 0 {
+				t.Errorf("boundary resolved %v at %v, want resolved on the edge", got.BoundaryResolved, got.BoundaryDistance)
+			}
+		}
+	})
+
+	t.Run("inside_is_not_boundary_resolved", func(t *testing.T) {
+		tx := New([]*geojson.Feature{west, east}, config, nil, Options{ToleranceMeters: 5})
+
+		got, ok := tx.GetTaxByLocation(context.Background(), 0.5, 0.5)
+		if !ok {
+			t.Fatal("expected tax to be found")
+		}
+		if got.Code != "0001" || got.BoundaryResolved {
+			t.Errorf("code %q resolved %v, want 0001 not resolved", got.Code, got.BoundaryResolved)
+		}
+	})
+
+	t.Run("snaps_within_tolerance", func(t *testing.T) {
+		tx := New([]*geojson.Feature{west, east}, config, nil, Options{ToleranceMeters: 5})
+
+		// About 1.1 m east of the East feature.
+		got, ok := tx.GetTaxByLocation(context.Background(), 0.5, 2.00001)
+		if !ok {
+			t.Fatal("expected location to be snapped")
+		}
+		if got.Code != "0002" || !got.BoundaryResolved {
+			t.Errorf("code %q resolved %v, want 0002 resolved", got.Code, got.BoundaryResolved)
+		}
+		if math.Abs(got.BoundaryDistance-1.1132) > 0.01 {
+			t.Errorf("distance %v, want about 1.11 m", got.BoundaryDistance)
+		}
+	})
+
+	t.Run("out_of_scope_beyond_tolerance", func(t *testing.T) {
+		strict := New([]*geojson.Feature{west, east}, config, nil, Options{})
+		if _, ok := strict.GetTaxByLocation(context.Background(), 0.5, 2.00001); ok {
+			t.Error("expected location to be out of scope without tolerance")
+		}
+
+		tolerant := New([]*geojson.Feature{west, east}, config, nil, Options{ToleranceMeters: 5})
+		if _, ok := tolerant.GetTaxByLocation(context.Background(), 0.5, 2.001); ok {
+			t.Error("expected location about 111 m away to be out of scope")
+		}
+	})
+
+	t.Run("matched_without_tax", func(t *testing.T) {
+		unknown := squareFeature("North", 0, 1, 1, 2)
+		tx := New([]*geojson.Feature{west, east, unknown}, config, nil, Options{})
+
+		got, ok := tx.GetTaxByLocation(context.Background(), 1.5, 0.5)
+		if ok || got == nil {
+			t.Errorf("got %+v, %v, want a failed lookup rather than no match", got, ok)
+		}
+		if got, _ := tx.GetTaxByLocation(context.Background(), 3, 3); got != nil {
+			t.Errorf("got %+v outside every feature, want nil", got)
+		}
+	})
+}
+
+func TestGetTaxByLocation_Ambiguity(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{
+		"Low":  {CompositeRate: 0.04, Names: []string{"Low"}, Code: "0001"},
+		"High": {CompositeRate: 0.08, Names: []string{"High"}, Code: "0002"},
+	}
+	features := []*geojson.Feature{
+		squareFeature("Low", 0, 0, 2, 2),
+		squareFeature("High", 1, 1, 3, 3),
+	}
+
+	tests := []struct {
+		name   string
+		policy entity.AmbiguityPolicy
+		found  bool
+		code   string
+	}{
+		{name: "default", found: true, code: "0001"},
+		{name: "first_wins", policy: entity.AmbiguityPolicyFirstWins, found: true, code: "0001"},
+		{name: "highest_rate", policy: entity.AmbiguityPolicyHighestRate, found: true, code: "0002"},
+		{name: "fail", policy: entity.AmbiguityPolicyFail, found: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := New(features, config, nil, Options{AmbiguityPolicy: tc.policy})
+
+			got, ok := tx.GetTaxByLocation(context.Background(), 1.5, 1.5)
+			if ok != tc.found {
+				t.Fatalf("found %v, want %v", ok, tc.found)
+			}
+			if got == nil || !got.Ambiguous {
+				t.Fatalf("expected ambiguous result, got %+v", got)
+			}
+			if !slices.Equal(got.Matches, []string{"Low", "High"}) {
+				t.Errorf("matches %v, want [Low High]", got.Matches)
+			}
+			if ok && got.Code != tc.code {
+				t.Errorf("code %q, want %q", got.Code, tc.code)
+			}
+
+			single, ok := tx.GetTaxByLocation(context.Background(), 0.5, 0.5)
+			if !ok || single.Ambiguous || single.Matches != nil {
+				t.Errorf("expected unambiguous match outside of the overlap, got %+v", single)
+			}
+		})
+	}
+}
+
+func TestDataVersion(t *testing.T) {
+	ctx := context.Background()
+	west := squareFeature("West", 0, 0, 1, 1)
+	east := squareFeature("East", 1, 0, 2, 1)
+
+	tx := New([]*geojson.Feature{west}, nil, nil, Options{})
+	version := tx.DataVersion(ctx)
+	if New([]*geojson.Feature{west}, nil, nil, Options{}).DataVersion(ctx) != version {
+		t.Fatal("expected the same boundaries to have the same version")
+	}
+
+	tx.ReplaceFeatures(ctx, []*geojson.Feature{west, east})
+	activated := tx.DataVersion(ctx)
+	if activated == version {
+		t.Error("expected the version to change with the boundaries")
+	}
+
+	tx.ReplaceOverrides(ctx, []entity.TaxOverride{{Name: "holiday", Jurisdictions: []string{"*"}}})
+	if tx.DataVersion(ctx) == activated {
+		t.Error("expected the version to change with the overrides")
+	}
+}
@@ -0,0 +1,273 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
+)
+
+// ValidateFeatures checks a boundary set against the tax configuration.
+// Every feature must carry a name with a matching tax config entry and
+// a Polygon or MultiPolygon geometry made of closed, non-degenerate rings.
+// Tax config entries without a feature, duplicate feature names,
+// overlapping features and areas of the state outline covered by no
+// feature are reported but do not make the set invalid.
+func (r *Tax) ValidateFeatures(ctx context.Context, features []*geojson.Feature) entity.BoundaryValidationReport {
+	known := make(map[string]struct{}, len(r.taxConfig))
+	for name := range r.taxConfig {
+		known[name] = struct{}{}
+	}
+
+	return validateFeatures(features, r.opts.PropertyKey, known, r.opts.Outline)
+}
+
+// ValidateLayers checks every additional tax layer against its rate table
+// using the same rules as ValidateFeatures. Reports are keyed by layer name.
+func (r *Tax) ValidateLayers(ctx context.Context) map[string]entity.BoundaryValidationReport {
+	reports := make(map[string]entity.BoundaryValidationReport, len(r.layers))
+
+	for _, l := range r.layers {
+		known := make(map[string]struct{}, len(l.Rates))
+		for name := range l.Rates {
+			known[name] = struct{}{}
+		}
+
+		report := validateFeatures(l.features, l.PropertyKey, known, nil)
+		if !l.Level.Valid() {
+			report.Errors = append(report.Errors, entity.BoundaryIssue{FeatureIndex: -1, Message: fmt.Sprintf("unknown layer level %q", l.Level)})
+			report.Valid = false
+		}
+		reports[l.Name] = report
+	}
+
+	return reports
+}
+
+// validateFeatures checks the features against the names of the rate table.
+// Gaps are searched for only when an outline is given.
+func validateFeatures(features []*geojson.Feature, propertyKey string, known map[string]struct{}, outline orb.MultiPolygon) entity.BoundaryValidationReport {
+	report := entity.BoundaryValidationReport{
+		Errors:                 []entity.BoundaryIssue{},
+		UncoveredJurisdictions: []string{},
+		DuplicateNames:         []string{},
+		Overlaps:               []entity.BoundaryOverlap{},
+		Gaps:                   []entity.BoundaryGap{},
+	}
+
+	if len(features) == 0 {
+		report.Errors = append(report.Errors, entity.BoundaryIssue{FeatureIndex: -1, Message: "boundary set has no features"})
+	}
+
+	covered := make(map[string]struct{}, len(features))
+	seen := make(map[string]int, len(features))
+	geometries := make([]*overlayGeometry, len(features))
+
+	for i, f := range features {
+		if f == nil {
+			report.Errors = append(report.Errors, entity.BoundaryIssue{FeatureIndex: i, Name: entity.UnknownName, Message: "feature is null"})
+			continue
+		}
+
+		name := featureName(f, propertyKey)
+		if name != entity.UnknownName {
+			seen[name]++
+		}
+		addIssue := func(msg string) {
+			report.Errors = append(report.Errors, entity.BoundaryIssue{FeatureIndex: i, Name: name, Message: msg})
+		}
+
+		if name == entity.UnknownName {
+			addIssue(fmt.Sprintf("missing %q property", propertyKey))
+		} else if _, ok := known[name]; !ok {
+			addIssue("no tax config entry for feature name")
+		} else {
+			covered[name] = struct{}{}
+		}
+
+		if f.Geometry == nil {
+			addIssue("missing geometry")
+			continue
+		}
+
+		multiPoly := entity.GetMultiPolygon(f)
+		if multiPoly == nil {
+			addIssue(fmt.Sprintf("unsupported geometry type %s", f.Geometry.GeoJSONType()))
+			continue
+		}
+
+		for _, msg := range validateMultiPolygon(multiPoly) {
+			addIssue(msg)
+		}
+		geometries[i] = newOverlayGeometry(multiPoly)
+	}
+
+	for name := range known {
+		if _, ok := covered[name]; !ok {
+			report.UncoveredJurisdictions = append(report.UncoveredJurisdictions, name)
+		}
+	}
+	slices.Sort(report.UncoveredJurisdictions)
+
+	for name, count := range seen {
+		if count > 1 {
+			report.DuplicateNames = append(report.DuplicateNames, name)
+		}
+	}
+	slices.Sort(report.DuplicateNames)
+
+	report.Overlaps = findOverlaps(features, propertyKey, geometries)
+	report.Gaps = findGaps(features, propertyKey, geometries, outline)
+	report.Valid = len(report.Errors) == 0
+
+	return report
+}
+
+// DiffFeatures compares a boundary set with the currently active one.
+// Features are matched by name; a matched feature is considered changed
+// when its geometry differs.
+func (r *Tax) DiffFeatures(ctx context.Context, features []*geojson.Feature) entity.BoundaryDiff {
+	active := fingerprints(r.GetActiveFeatures(ctx), r.opts.PropertyKey)
+	next := fingerprints(features, r.opts.PropertyKey)
+
+	diff := entity.BoundaryDiff{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []string{},
+	}
+
+	for name, fp := range next {
+		activeFp, ok := active[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case activeFp != fp:
+			diff.Changed = append(diff.Changed, name)
+		default:
+			diff.Unchanged++
+		}
+	}
+
+	for name := range active {
+		if _, ok := next[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.Sort(diff.Changed)
+
+	return diff
+}
+
+// featureName returns the name of the feature, or entity.UnknownName
+// for a feature without one, including a null feature.
+func featureName(f *geojson.Feature, propertyKey string) string {
+	if f == nil {
+		return entity.UnknownName
+	}
+	return f.Properties.MustString(propertyKey, entity.UnknownName)
+}
+
+// validateMultiPolygon returns a description of every invalid ring.
+func validateMultiPolygon(mp orb.MultiPolygon) []string {
+	var issues []string
+
+	for pi, poly := range mp {
+		if len(poly) == 0 {
+			issues = append(issues, fmt.Sprintf("polygon %d has no rings", pi))
+			continue
+		}
+
+		for ri, ring := range poly {
+			if msg := validateRing(ring); msg != "" {
+				issues = append(issues, fmt.Sprintf("polygon %d ring %d: %s", pi, ri, msg))
+			}
+		}
+	}
+
+	return issues
+}
+
+func validateRing(ring orb.Ring) string {
+	if len(ring) < 4 {
+		return "ring must have at least 4 positions"
+	}
+
+	for _, p := range ring {
+		if math.IsNaN(p.X()) || math.IsNaN(p.Y()) || math.IsInf(p.X(), 0) || math.IsInf(p.Y(), 0) {
+			return "ring contains non-finite coordinates"
+		}
+	}
+
+	if !ring.Closed() {
+		return "ring is not closed"
+	}
+
+	if planar.Area(ring) == 0 {
+		return "ring has zero area"
+	}
+
+	return ""
+}
+
+// findOverlaps reports feature pairs whose interiors intersect.
+// Candidate pairs are narrowed down by bounding box intersection first.
+func findOverlaps(features []*geojson.Feature, propertyKey string, geometries []*overlayGeometry) []entity.BoundaryOverlap {
+	overlaps := []entity.BoundaryOverlap{}
+	tree := buildIndex(features)
+
+	for i, first := range geometries {
+		if first == nil {
+			continue
+		}
+
+		bound := first.prepared.bound
+		tree.Search([2]float64{bound.Min.X(), bound.Min.Y()}, [2]float64{bound.Max.X(), bound.Max.Y()},
+			func(min, max [2]float64, j int) bool {
+				if j <= i || geometries[j] == nil {
+					return true
+				}
+
+				if overlap(first, geometries[j]) {
+					overlaps = append(overlaps, entity.BoundaryOverlap{
+						First:       featureName(features[i], propertyKey),
+						FirstIndex:  i,
+						Second:      featureName(features[j], propertyKey),
+						SecondIndex: j,
+					})
+				}
+				return true
+			},
+		)
+	}
+
+	return overlaps
+}
+
+// fingerprints maps feature names to a hash of their geometry.
+func fingerprints(features []*geojson.Feature, propertyKey string) map[string]uint64 {
+	out := make(map[string]uint64, len(features))
+
+	for _, f := range features {
+		if f == nil {
+			continue
+		}
+
+		h := fnv.New64a()
+		if f.Geometry != nil {
+			fmt.Fprintf(h, "%s:%v", f.Geometry.GeoJSONType(), f.Geometry)
+		}
+		out[featureName(f, propertyKey)] = h.Sum64()
+	}
+
+	return out
+}
@@ -0,0 +1,201 @@
+package tax
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func squareFeature(name string, minX, minY, maxX, maxY float64) *geojson.Feature {
+	f := geojson.NewFeature(orb.Polygon{{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}, {minX, minY}}})
+	f.Properties[entity.NamePropertyKey] = name
+	return f
+}
+
+func TestValidateFeatures(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{"A": {}, "B": {}, "C": {}, "Unused": {}}
+
+	point := geojson.NewFeature(orb.Point{0, 0})
+	point.Properties[entity.NamePropertyKey] = "C"
+
+	features := []*geojson.Feature{
+		squareFeature("A", 0, 0, 1, 1),
+		squareFeature("B", 1, 0, 2, 1), // shares an edge with A
+		squareFeature("A", 0.5, 0.5, 1.5, 1.5),
+		squareFeature("Orphan", 5, 5, 6, 6),
+		point,
+	}
+
+	report := New(nil, config, nil, Options{}).ValidateFeatures(context.Background(), features)
+
+	if report.Valid {
+		t.Fatal("expected invalid report")
+	}
+	if report.Consistent() {
+		t.Fatal("expected inconsistent report")
+	}
+	if len(report.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %+v", report.Errors)
+	}
+	if report.Errors[0].Name != "Orphan" || report.Errors[1].FeatureIndex != 4 {
+		t.Errorf("unexpected errors %+v", report.Errors)
+	}
+	if !slices.Equal(report.UncoveredJurisdictions, []string{"Unused"}) {
+		t.Errorf("unexpected uncovered jurisdictions %v", report.UncoveredJurisdictions)
+	}
+	if !slices.Equal(report.DuplicateNames, []string{"A"}) {
+		t.Errorf("unexpected duplicate names %v", report.DuplicateNames)
+	}
+	if len(report.Overlaps) != 2 {
+		t.Errorf("expected overlaps A/A and B/A only, got %+v", report.Overlaps)
+	}
+}
+
+func TestValidateFeatures_Consistent(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{"A": {}, "B": {}}
+	features := []*geojson.Feature{
+		squareFeature("A", 0, 0, 1, 1),
+		squareFeature("B", 1, 0, 2, 1),
+	}
+
+	report := New(features, config, nil, Options{}).ValidateFeatures(context.Background(), features)
+	if !report.Consistent() {
+		t.Fatalf("expected consistent report, got %+v", report)
+	}
+}
+
+func TestValidateFeatures_NullFeature(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{"A": {}}
+
+	var uploaded entity.GeoJSON
+	if err := json.Unmarshal([]byte(`{"features":[null]}`), &uploaded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	features := append([]*geojson.Feature{squareFeature("A", 0, 0, 1, 1)}, uploaded.Features...)
+
+	tx := New(nil, config, nil, Options{})
+	report := tx.ValidateFeatures(context.Background(), features)
+	if report.Valid {
+		t.Fatal("expected invalid report")
+	}
+	if len(report.Errors) != 1 || report.Errors[0].FeatureIndex != 1 || report.Errors[0].Message != "feature is null" {
+		t.Errorf("unexpected errors %+v", report.Errors)
+	}
+
+	if diff := tx.DiffFeatures(context.Background(), features); !slices.Equal(diff.Added, []string{"A"}) {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+}
+
+func TestValidateFeatures_Overlaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		features []*geojson.Feature
+		overlap  bool
+	}{
+		{
+			name:     "identical geometries",
+			features: []*geojson.Feature{squareFeature("A", 0, 0, 1, 1), squareFeature("B", 0, 0, 1, 1)},
+			overlap:  true,
+		},
+		{
+			name:     "crossing without interior vertices",
+			features: []*geojson.Feature{squareFeature("A", 0, 1, 3, 2), squareFeature("B", 1, 0, 2, 3)},
+			overlap:  true,
+		},
+		{
+			name:     "vertices on each other's borders",
+			features: []*geojson.Feature{squareFeature("A", 0, 0, 2, 1), squareFeature("B", 1, 0, 3, 1)},
+			overlap:  true,
+		},
+		{
+			name:     "nested",
+			features: []*geojson.Feature{squareFeature("A", 0, 0, 3, 3), squareFeature("B", 1, 1, 2, 2)},
+			overlap:  true,
+		},
+		{
+			name:     "shared corner",
+			features: []*geojson.Feature{squareFeature("A", 0, 0, 1, 1), squareFeature("B", 1, 1, 2, 2)},
+		},
+		{
+			name:     "disjoint",
+			features: []*geojson.Feature{squareFeature("A", 0, 0, 1, 1), squareFeature("B", 2, 0, 3, 1)},
+		},
+	}
+
+	config := map[string]entity.JurisdictionTax{"A": {}, "B": {}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := New(nil, config, nil, Options{}).ValidateFeatures(context.Background(), tt.features)
+			if got := len(report.Overlaps) == 1; got != tt.overlap {
+				t.Errorf("expected overlap %v, got %+v", tt.overlap, report.Overlaps)
+			}
+		})
+	}
+}
+
+func TestValidateFeatures_Gaps(t *testing.T) {
+	config := map[string]entity.JurisdictionTax{"A": {}, "B": {}, "C": {}}
+	outline := orb.MultiPolygon{{{{0, 0}, {3, 0}, {3, 1}, {0, 1}, {0, 0}}}}
+
+	t.Run("covered", func(t *testing.T) {
+		features := []*geojson.Feature{
+			squareFeature("A", 0, 0, 1, 1),
+			squareFeature("B", 1, 0, 2, 1),
+			squareFeature("C", 2, 0, 3, 1),
+		}
+
+		report := New(nil, config, nil, Options{Outline: outline}).ValidateFeatures(context.Background(), features)
+		if len(report.Gaps) != 0 {
+			t.Errorf("expected no gaps, got %+v", report.Gaps)
+		}
+	})
+
+	t.Run("gap between features", func(t *testing.T) {
+		features := []*geojson.Feature{
+			squareFeature("A", 0, 0, 1, 1),
+			squareFeature("C", 2, 0, 3, 1),
+		}
+
+		report := New(nil, config, nil, Options{Outline: outline}).ValidateFeatures(context.Background(), features)
+		if len(report.Gaps) != 3 {
+			t.Fatalf("expected gaps next to A, C and the outline, got %+v", report.Gaps)
+		}
+		if report.Gaps[0].Name != "A" || report.Gaps[1].Name != "C" || report.Gaps[2].FeatureIndex != -1 {
+			t.Errorf("unexpected gaps %+v", report.Gaps)
+		}
+		for _, gap := range report.Gaps {
+			if gap.Longitude <= 1 || gap.Longitude >= 2 {
+				t.Errorf("gap location %+v outside of the uncovered area", gap)
+			}
+		}
+	})
+
+	t.Run("hole inside a feature", func(t *testing.T) {
+		holed := geojson.NewFeature(orb.Polygon{
+			{{0, 0}, {3, 0}, {3, 1}, {0, 1}, {0, 0}},
+			{{1, 0.25}, {1, 0.75}, {2, 0.75}, {2, 0.25}, {1, 0.25}},
+		})
+		holed.Properties[entity.NamePropertyKey] = "A"
+
+		report := New(nil, config, nil, Options{Outline: outline}).ValidateFeatures(context.Background(), []*geojson.Feature{holed})
+		if len(report.Gaps) != 1 || report.Gaps[0].Name != "A" {
+			t.Errorf("expected a gap inside A, got %+v", report.Gaps)
+		}
+	})
+
+	t.Run("without outline", func(t *testing.T) {
+		features := []*geojson.Feature{squareFeature("A", 0, 0, 1, 1)}
+
+		report := New(nil, config, nil, Options{}).ValidateFeatures(context.Background(), features)
+		if len(report.Gaps) != 0 {
+			t.Errorf("expected no gaps, got %+v", report.Gaps)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package boundary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+)
+
+// UseCase implements business logic for managing jurisdiction boundaries.
+// It validates uploaded boundary sets against the tax configuration,
+// previews their diff against the active set, persists them,
+// and swaps the active boundaries used for tax lookups.
+// Every set belongs to a single state and only replaces
+// the boundaries of that state.
+type UseCase struct {
+	boundaryRepo    repo.BoundaryRepo
+	boundarySetRepo repo.BoundarySetRepo
+	logger          zerolog.Logger
+}
+
+func New(boundaryRepo repo.BoundaryRepo, boundarySetRepo repo.BoundarySetRepo, logger zerolog.Logger) *UseCase {
+	l := logger.With().Str("usecase", "boundary").Logger()
+	return &UseCase{
+		boundaryRepo:    boundaryRepo,
+		boundarySetRepo: boundarySetRepo,
+		logger:          l,
+	}
+}
+
+// Upload parses a GeoJSON feature collection of the given state, validates
+// it against the tax config of the state, computes the diff against the
+// active boundaries of the state and stores it as staged.
+// Invalid sets are stored as well so the report can be reviewed later,
+// but they cannot be activated. A state that is not configured is
+// a bad request; an empty state means the primary state.
+func (uc *UseCase) Upload(ctx context.Context, state string, r io.Reader) (entity.BoundarySet, error) {
+	state = strings.ToUpper(strings.TrimSpace(state))
+	if !uc.boundaryRepo.HasState(ctx, state) {
+		return entity.BoundarySet{}, entity.ErrBadRequest
+	}
+	if state == uc.boundaryRepo.PrimaryState(ctx) {
+		state = ""
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return entity.BoundarySet{}, fmt.Errorf("failed to read boundary file: %w", err)
+	}
+
+	var geoJSON *entity.GeoJSON
+	if err := json.Unmarshal(data, &geoJSON); err != nil || geoJSON == nil {
+		return entity.BoundarySet{}, errors.Join(entity.ErrInvalidFileFormat, err)
+	}
+
+	set := entity.BoundarySet{
+		Status:       entity.BoundarySetStatusStaged,
+		State:        state,
+		FeatureCount: len(geoJSON.Features),
+		Report:       uc.boundaryRepo.ValidateFeatures(ctx, state, geoJSON.Features),
+		Diff:         uc.boundaryRepo.DiffFeatures(ctx, state, geoJSON.Features),
+		CreatedAt:    time.Now(),
+		GeoJSON:      geoJSON,
+	}
+
+	id, err := uc.boundarySetRepo.Create(ctx, set)
+	if err != nil {
+		return entity.BoundarySet{}, fmt.Errorf("failed to create boundary set: %w", err)
+	}
+	set.Id = id
+
+	return set, nil
+}
+
+// GetById returns a boundary set with its validation report and diff.
+func (uc *UseCase) GetById(ctx context.Context, id int) (entity.BoundarySet, error) {
+	return uc.boundarySetRepo.GetById(ctx, id)
+}
+
+// Activate makes a staged boundary set the active one of its state.
+// The set is re-validated against the current tax configuration
+// of the state before activation; invalid sets are rejected.
+func (uc *UseCase) Activate(ctx context.Context, id int) (entity.BoundarySet, error) {
+	set, err := uc.boundarySetRepo.GetById(ctx, id)
+	if err != nil {
+		return entity.BoundarySet{}, err
+	}
+
+	set.Report = uc.boundaryRepo.ValidateFeatures(ctx, set.State, set.GeoJSON.Features)
+	if !set.Report.Valid {
+		return entity.BoundarySet{}, entity.ErrInvalidBoundarySet
+	}
+
+	if err := uc.boundarySetRepo.Activate(ctx, id); err != nil {
+		return entity.BoundarySet{}, fmt.Errorf("failed to activate boundary set: %w", err)
+	}
+
+	uc.boundaryRepo.ReplaceFeatures(ctx, set.State, set.GeoJSON.Features)
+
+	now := time.Now()
+	set.Status = entity.BoundarySetStatusActive
+	set.ActivatedAt = &now
+
+	uc.logger.Info().Int("id", id).Str("state", set.State).Int("feature_count", set.FeatureCount).Msg("activated boundary set")
+
+	return set, nil
+}
+
+// LoadActive replaces the boundaries loaded from file with the most
+// recently activated set of every state that has one. Sets of states
+// that are no longer configured are skipped.
+func (uc *UseCase) LoadActive(ctx context.Context) error {
+	sets, err := uc.boundarySetRepo.GetActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active boundary sets: %w", err)
+	}
+
+	for _, set := range sets {
+		if !uc.boundaryRepo.HasState(ctx, set.State) {
+			uc.logger.Warn().Int("id", set.Id).Str("state", set.State).Msg("skipped active boundary set of unknown state")
+			continue
+		}
+
+		uc.boundaryRepo.ReplaceFeatures(ctx, set.State, set.GeoJSON.Features)
+		uc.logger.Info().Int("id", set.Id).Str("state", set.State).Int("feature_count", set.FeatureCount).Msg("loaded active boundary set")
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package boundary
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	repomocks "github.com/ryl1k/INT20H-test-task-server/internal/repo/mocks"
+
+	"github.com/paulmach/orb/geojson"
+	"github.com/rs/zerolog"
+)
+
+const testGeoJSON = `{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"NAME":"Albany"},"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}}]}`
+
+func newTestUseCase(t *testing.T) (*UseCase, *repomocks.MockBoundaryRepo, *repomocks.MockBoundarySetRepo) {
+	ctrl := gomock.NewController(t)
+	boundaryRepo := repomocks.NewMockBoundaryRepo(ctrl)
+	boundarySetRepo := repomocks.NewMockBoundarySetRepo(ctrl)
+	return New(boundaryRepo, boundarySetRepo, zerolog.Nop()), boundaryRepo, boundarySetRepo
+}
+
+func TestUpload(t *testing.T) {
+	t.Run("staged", func(t *testing.T) {
+		uc, boundaryRepo, boundarySetRepo := newTestUseCase(t)
+
+		boundaryRepo.EXPECT().HasState(gomock.Any(), "").Return(true)
+		boundaryRepo.EXPECT().PrimaryState(gomock.Any()).Return("NY")
+		boundaryRepo.EXPECT().ValidateFeatures(gomock.Any(), "", gomock.Len(1)).
+			Return(entity.BoundaryValidationReport{Valid: true})
+		boundaryRepo.EXPECT().DiffFeatures(gomock.Any(), "", gomock.Len(1)).
+			Return(entity.BoundaryDiff{Added: []string{"Albany"}})
+		boundarySetRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, set entity.BoundarySet) (int, error) {
+				if set.Status != entity.BoundarySetStatusStaged {
+					t.Errorf("expected staged status, got %s", set.Status)
+				}
+				if set.FeatureCount != 1 {
+					t.Errorf("expected 1 feature, got %d", set.FeatureCount)
+				}
+				return 7, nil
+			})
+
+		set, err := uc.Upload(context.Background(), "", strings.NewReader(testGeoJSON))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if set.Id != 7 {
+			t.Errorf("got id %d", set.Id)
+		}
+	})
+
+	t.Run("other state", func(t *testing.T) {
+		uc, boundaryRepo, boundarySetRepo := newTestUseCase(t)
+
+		boundaryRepo.EXPECT().HasState(gomock.Any(), "NJ").Return(true)
+		boundaryRepo.EXPECT().PrimaryState(gomock.Any()).Return("NY")
+		boundaryRepo.EXPECT().ValidateFeatures(gomock.Any(), "NJ", gomock.Len(1)).
+			Return(entity.BoundaryValidationReport{Valid: true})
+		boundaryRepo.EXPECT().DiffFeatures(gomock.Any(), "NJ", gomock.Len(1)).
+			Return(entity.BoundaryDiff{})
+		boundarySetRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(8, nil)
+
+		set, err := uc.Upload(context.Background(), " nj ", strings.NewReader(testGeoJSON))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if set.State != "NJ" {
+			t.Errorf("expected state NJ, got %q", set.State)
+		}
+	})
+
+	t.Run("primary state by code", func(t *testing.T) {
+		uc, boundaryRepo, boundarySetRepo := newTestUseCase(t)
+
+		boundaryRepo.EXPECT().HasState(gomock.Any(), "NY").Return(true)
+		boundaryRepo.EXPECT().PrimaryState(gomock.Any()).Return("NY")
+		boundaryRepo.EXPECT().ValidateFeatures(gomock.Any(), "", gomock.Any()).
+			Return(entity.BoundaryValidationReport{Valid: true})
+		boundaryRepo.EXPECT().DiffFeatures(gomock.Any(), "", gomock.Any()).
+			Return(entity.BoundaryDiff{})
+		boundarySetRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(9, nil)
+
+		set, err := uc.Upload(context.Background(), "NY", strings.NewReader(testGeoJSON))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if set.State != "" {
+			t.Errorf("expected the primary state to be stored empty, got %q", set.State)
+		}
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		uc, boundaryRepo, _ := newTestUseCase(t)
+
+		boundaryRepo.EXPECT().HasState(gomock.Any(), "TX").Return(false)
+
+		_, err := uc.Upload(context.Background(), "TX", strings.NewReader(testGeoJSON))
+		if !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("malformed geojson", func(t *testing.T) {
+		uc, boundaryRepo, _ := newTestUseCase(t)
+
+		boundaryRepo.EXPECT().HasState(gomock.Any(), "").Return(true)
+		boundaryRepo.EXPECT().PrimaryState(gomock.Any()).Return("NY")
+
+		_, err := uc.Upload(context.Background(), "", strings.NewReader("{not json"))
+		if !errors.Is(err, entity.ErrInvalidFileFormat) {
+			t.Fatalf("expected ErrInvalidFileFormat, got %v", err)
+		}
+	})
+}
+
+func TestActivate(t *testing.T) {
+	set := entity.BoundarySet{
+		Id:      3,
+		Status:  entity.BoundarySetStatusStaged,
+		State:   "NJ",
+		GeoJSON: &entity.GeoJSON{Features: []*geojson.Feature{{}}},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		uc, boundaryRepo, boundarySetRepo := newTestUseCase(t)
+
+		gomock.InOrder(
+			boundarySetRepo.EXPECT().GetById(gomock.Any(), 3).Return(set, nil),
+			boundaryRepo.EXPECT().ValidateFeatures(gomock.Any(), "NJ", set.GeoJSON.Features).
+				Return(entity.BoundaryValidationReport{Valid: true}),
+			boundarySetRepo.EXPECT().Activate(gomock.Any(), 3).Return(nil),
+			boundaryRepo.EXPECT().ReplaceFeatures(gomock.Any(), "NJ", set.GeoJSON.Features),
+		)
+
+		out, err := uc.Activate(context.Background(), 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Status != entity.BoundarySetStatusActive {
+			t.Errorf("expected active status, got %s", out.Status)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		uc, boundaryRepo, boundarySetRepo := newTestUseCase(t)
+
+		boundarySetRepo.EXPECT().GetById(gomock.Any(), 3).Return(set, nil)
+		boundaryRepo.EXPECT().ValidateFeatures(gomock.Any(), "NJ", gomock.Any()).
+			Return(entity.BoundaryValidationReport{Valid: false})
+
+		_, err := uc.Activate(context.Background(), 3)
+		if !errors.Is(err, entity.ErrInvalidBoundarySet) {
+			t.Fatalf("expected ErrInvalidBoundarySet, got %v", err)
+		}
+	})
+}
+
+func TestLoadActive(t *testing.T) {
+	uc, boundaryRepo, boundarySetRepo := newTestUseCase(t)
+
+	primary := entity.BoundarySet{Id: 1, Status: entity.BoundarySetStatusActive, GeoJSON: &entity.GeoJSON{Features: []*geojson.Feature{{}}}}
+	nj := entity.BoundarySet{Id: 2, Status: entity.BoundarySetStatusActive, State: "NJ", GeoJSON: &entity.GeoJSON{Features: []*geojson.Feature{{}, {}}}}
+	removed := entity.BoundarySet{Id: 3, Status: entity.BoundarySetStatusActive, State: "CT", GeoJSON: &entity.GeoJSON{}}
+
+	boundarySetRepo.EXPECT().GetActive(gomock.Any()).Return([]entity.BoundarySet{primary, nj, removed}, nil)
+	boundaryRepo.EXPECT().HasState(gomock.Any(), "").Return(true)
+	boundaryRepo.EXPECT().HasState(gomock.Any(), "NJ").Return(true)
+	boundaryRepo.EXPECT().HasState(gomock.Any(), "CT").Return(false)
+	boundaryRepo.EXPECT().ReplaceFeatures(gomock.Any(), "", primary.GeoJSON.Features)
+	boundaryRepo.EXPECT().ReplaceFeatures(gomock.Any(), "NJ", nj.GeoJSON.Features)
+
+	if err := uc.LoadActive(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -13,9 +13,53 @@ import (
 type (
 	OrderService interface {
 		Create(ctx context.Context, order dto.Order) (entity.Order, error)
-		AsyncBatchCreate(reader *csv.Reader, closer io.Closer)
+		AsyncBatchCreate(reader *csv.Reader, closer io.Closer, options dto.OrderImport)
 		GetById(ctx context.Context, id int) (entity.Order, error)
 		GetAll(ctx context.Context, filter dto.OrderFilters) (entity.OrderList, error)
-		DeleteAll(ctx context.Context) error
+		Delete(ctx context.Context, filter dto.OrderFilters) (entity.OrderDeletion, error)
+		Restore(ctx context.Context, filter dto.OrderFilters) (entity.OrderRestoration, error)
+		RequestWipe(ctx context.Context) (entity.WipeConfirmation, error)
+		DeleteAll(ctx context.Context, token string) error
+		Update(ctx context.Context, id, version int, update dto.OrderUpdate) (entity.Order, error)
+		Void(ctx context.Context, id, version int) (entity.Order, error)
+		Refund(ctx context.Context, id int, refund dto.OrderRefund) (entity.OrderRefund, error)
+		History(ctx context.Context, id int) ([]entity.OrderEvent, error)
+	}
+	TaxService interface {
+		Explain(ctx context.Context, order dto.Order) (entity.TaxExplanation, error)
+	}
+	TaxRecalculationService interface {
+		PreviewRecalculation(ctx context.Context, req dto.TaxRecalculation) (entity.TaxRecalculation, error)
+		GetRecalculation(ctx context.Context, id int) (entity.TaxRecalculation, error)
+		StartRecalculation(ctx context.Context, id int) (entity.TaxRecalculation, error)
+		AsyncApplyRecalculation(rec entity.TaxRecalculation, actor entity.Actor)
+		ResolveOutOfScope(ctx context.Context, filter dto.OrderFilters) (entity.OutOfScopeResolution, error)
+	}
+	ExemptionService interface {
+		Create(ctx context.Context, cert dto.ExemptionCertificate) (entity.ExemptionCertificate, error)
+		GetByCustomerRef(ctx context.Context, customerRef string) ([]entity.ExemptionCertificate, error)
+	}
+	CustomerService interface {
+		Create(ctx context.Context, customer dto.Customer) (entity.Customer, error)
+		GetById(ctx context.Context, id int) (entity.Customer, error)
+		GetAll(ctx context.Context, limit, offset int) (entity.CustomerList, error)
+		Update(ctx context.Context, id int, customer dto.Customer) (entity.Customer, error)
+		Delete(ctx context.Context, id int) error
+		GetOrders(ctx context.Context, id int, filter dto.OrderFilters) (entity.OrderList, error)
+		GetTotals(ctx context.Context, id int) (entity.CustomerTotals, error)
+	}
+	TaxOverrideService interface {
+		Create(ctx context.Context, override dto.TaxOverride) (entity.TaxOverride, error)
+		GetAll(ctx context.Context) []entity.TaxOverride
+		Delete(ctx context.Context, id int) error
+	}
+	ExchangeRateService interface {
+		Create(ctx context.Context, rates dto.ExchangeRates) ([]entity.ExchangeRate, error)
+		GetAll(ctx context.Context) []entity.ExchangeRate
+	}
+	BoundaryService interface {
+		Upload(ctx context.Context, state string, r io.Reader) (entity.BoundarySet, error)
+		GetById(ctx context.Context, id int) (entity.BoundarySet, error)
+		Activate(ctx context.Context, id int) (entity.BoundarySet, error)
 	}
 )
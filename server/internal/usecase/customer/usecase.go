@@ -0,0 +1,105 @@
+package customer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+
+	"github.com/rs/zerolog"
+)
+
+// UseCase implements business logic for customers
+// and the orders they placed.
+type UseCase struct {
+	customerRepo repo.CustomerRepo
+	orderRepo    repo.OrderRepo
+	logger       zerolog.Logger
+}
+
+func New(customerRepo repo.CustomerRepo, orderRepo repo.OrderRepo, logger zerolog.Logger) *UseCase {
+	l := logger.With().Str("usecase", "customer").Logger()
+	return &UseCase{
+		customerRepo: customerRepo,
+		orderRepo:    orderRepo,
+		logger:       l,
+	}
+}
+
+// Create registers a customer.
+func (uc *UseCase) Create(ctx context.Context, customerDto dto.Customer) (entity.Customer, error) {
+	created, err := uc.customerRepo.Create(ctx, newCustomer(customerDto))
+	if err != nil {
+		return entity.Customer{}, fmt.Errorf("failed to create customer: %w", err)
+	}
+	return created, nil
+}
+
+// GetById returns the customer.
+func (uc *UseCase) GetById(ctx context.Context, id int) (entity.Customer, error) {
+	return uc.customerRepo.GetById(ctx, id)
+}
+
+// GetAll returns a page of customers.
+func (uc *UseCase) GetAll(ctx context.Context, limit, offset int) (entity.CustomerList, error) {
+	return uc.customerRepo.GetAll(ctx, limit, offset)
+}
+
+// Update replaces the name and default location of the customer.
+// Orders already placed keep the location they were taxed at.
+// The external reference links orders to the exemption certificates of the
+// customer, so changing it returns ErrCustomerRefImmutable.
+func (uc *UseCase) Update(ctx context.Context, id int, customerDto dto.Customer) (entity.Customer, error) {
+	stored, err := uc.customerRepo.GetById(ctx, id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	if customerDto.ExternalRef != stored.ExternalRef {
+		return entity.Customer{}, entity.ErrCustomerRefImmutable
+	}
+
+	customer := newCustomer(customerDto)
+	customer.Id = id
+	if err := uc.customerRepo.Update(ctx, customer); err != nil {
+		return entity.Customer{}, fmt.Errorf("failed to update customer: %w", err)
+	}
+	return uc.customerRepo.GetById(ctx, id)
+}
+
+// Delete removes the customer and its exemption certificates.
+// Its orders are kept, unlinked from it.
+func (uc *UseCase) Delete(ctx context.Context, id int) error {
+	return uc.customerRepo.Delete(ctx, id)
+}
+
+// GetOrders returns the orders placed by the customer,
+// narrowed down by the other filters.
+func (uc *UseCase) GetOrders(ctx context.Context, id int, filter dto.OrderFilters) (entity.OrderList, error) {
+	if _, err := uc.customerRepo.GetById(ctx, id); err != nil {
+		return entity.OrderList{}, err
+	}
+
+	filter.CustomerId = &id
+	return uc.orderRepo.GetAll(ctx, filter)
+}
+
+// GetTotals sums up the orders placed by the customer.
+func (uc *UseCase) GetTotals(ctx context.Context, id int) (entity.CustomerTotals, error) {
+	if _, err := uc.customerRepo.GetById(ctx, id); err != nil {
+		return entity.CustomerTotals{}, err
+	}
+	return uc.customerRepo.GetTotals(ctx, id)
+}
+
+func newCustomer(customerDto dto.Customer) entity.Customer {
+	return entity.Customer{
+		ExternalRef: customerDto.ExternalRef,
+		Name:        customerDto.Name,
+		Address:     customerDto.Address,
+		Zip:         customerDto.Zip,
+		Latitude:    customerDto.Latitude,
+		Longitude:   customerDto.Longitude,
+	}
+}
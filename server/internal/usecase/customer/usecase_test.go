@@ -0,0 +1,83 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	repomocks "github.com/ryl1k/INT20H-test-task-server/internal/repo/mocks"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestUseCase(t *testing.T) (*UseCase, *repomocks.MockCustomerRepo, *repomocks.MockOrderRepo) {
+	ctrl := gomock.NewController(t)
+	customerRepo := repomocks.NewMockCustomerRepo(ctrl)
+	orderRepo := repomocks.NewMockOrderRepo(ctrl)
+	return New(customerRepo, orderRepo, zerolog.Nop()), customerRepo, orderRepo
+}
+
+func TestUpdate(t *testing.T) {
+	uc, customerRepo, _ := newTestUseCase(t)
+	lat, lon := 40.7, -74.0
+
+	t.Run("location", func(t *testing.T) {
+		gomock.InOrder(
+			customerRepo.EXPECT().GetById(gomock.Any(), 3).Return(entity.Customer{Id: 3, ExternalRef: "acme"}, nil),
+			customerRepo.EXPECT().Update(gomock.Any(), entity.Customer{Id: 3, ExternalRef: "acme", Latitude: &lat, Longitude: &lon}).Return(nil),
+			customerRepo.EXPECT().GetById(gomock.Any(), 3).Return(entity.Customer{Id: 3, ExternalRef: "acme"}, nil),
+		)
+
+		out, err := uc.Update(context.Background(), 3, dto.Customer{ExternalRef: "acme", Latitude: &lat, Longitude: &lon})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Id != 3 {
+			t.Errorf("unexpected customer %+v", out)
+		}
+	})
+
+	t.Run("changed reference", func(t *testing.T) {
+		customerRepo.EXPECT().GetById(gomock.Any(), 3).Return(entity.Customer{Id: 3, ExternalRef: "acme"}, nil)
+
+		if _, err := uc.Update(context.Background(), 3, dto.Customer{ExternalRef: "acme-2"}); !errors.Is(err, entity.ErrCustomerRefImmutable) {
+			t.Fatalf("expected ErrCustomerRefImmutable, got %v", err)
+		}
+	})
+}
+
+func TestGetOrders(t *testing.T) {
+	t.Run("filtered by customer", func(t *testing.T) {
+		uc, customerRepo, orderRepo := newTestUseCase(t)
+
+		customerRepo.EXPECT().GetById(gomock.Any(), 3).Return(entity.Customer{Id: 3}, nil)
+		orderRepo.EXPECT().GetAll(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f dto.OrderFilters) (entity.OrderList, error) {
+			if f.CustomerId == nil || *f.CustomerId != 3 || f.Limit != 10 {
+				t.Errorf("unexpected filter %+v", f)
+			}
+			return entity.OrderList{Total: 2}, nil
+		})
+
+		list, err := uc.GetOrders(context.Background(), 3, dto.OrderFilters{Limit: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if list.Total != 2 {
+			t.Errorf("unexpected list %+v", list)
+		}
+	})
+
+	t.Run("unknown customer", func(t *testing.T) {
+		uc, customerRepo, _ := newTestUseCase(t)
+
+		customerRepo.EXPECT().GetById(gomock.Any(), 4).Return(entity.Customer{}, entity.ErrCustomerNotFound)
+
+		if _, err := uc.GetOrders(context.Background(), 4, dto.OrderFilters{}); !errors.Is(err, entity.ErrCustomerNotFound) {
+			t.Fatalf("expected ErrCustomerNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+
+	"github.com/rs/zerolog"
+)
+
+// UseCase implements business logic for exchange rates.
+// Rates come from a local file and from the admin API; both are merged
+// into the table orders are converted with, with API rates taking
+// precedence over file ones of the same currency and date.
+type UseCase struct {
+	activeRepo repo.ActiveExchangeRateRepo
+	rateRepo   repo.ExchangeRateRepo
+	fileRates  []entity.ExchangeRate
+	logger     zerolog.Logger
+}
+
+func New(
+	activeRepo repo.ActiveExchangeRateRepo,
+	rateRepo repo.ExchangeRateRepo,
+	fileRates []entity.ExchangeRate,
+	logger zerolog.Logger,
+) *UseCase {
+	l := logger.With().Str("usecase", "exchange").Logger()
+	return &UseCase{
+		activeRepo: activeRepo,
+		rateRepo:   rateRepo,
+		fileRates:  fileRates,
+		logger:     l,
+	}
+}
+
+// Load reads the stored rates and makes them, together with
+// the file rates, the ones orders are converted with.
+func (uc *UseCase) Load(ctx context.Context) error {
+	stored, err := uc.rateRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+
+	// later rates of the same currency and date win
+	rates := make([]entity.ExchangeRate, 0, len(uc.fileRates)+len(stored))
+	rates = append(rates, uc.fileRates...)
+	rates = append(rates, stored...)
+	uc.activeRepo.ReplaceRates(ctx, rates)
+
+	uc.logger.Info().Int("stored", len(stored)).Int("file", len(uc.fileRates)).Msg("loaded exchange rates")
+	return nil
+}
+
+// Create stores the rates and reloads the active ones.
+// Rates of the reporting currency cannot be set.
+func (uc *UseCase) Create(ctx context.Context, ratesDto dto.ExchangeRates) ([]entity.ExchangeRate, error) {
+	rates := make([]entity.ExchangeRate, 0, len(ratesDto.Rates))
+	for _, r := range ratesDto.Rates {
+		currency, ok := entity.NormalizeCurrency(r.Currency)
+		if !ok || currency == entity.ReportingCurrency {
+			return nil, entity.ErrBadRequest
+		}
+
+		y, m, d := r.Date.UTC().Date()
+		rates = append(rates, entity.ExchangeRate{
+			Currency: currency,
+			Date:     time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+			Rate:     r.Rate,
+			Source:   entity.ExchangeRateSourceApi,
+		})
+	}
+
+	if err := uc.rateRepo.Upsert(ctx, rates); err != nil {
+		return nil, fmt.Errorf("failed to store exchange rates: %w", err)
+	}
+
+	if err := uc.Load(ctx); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// GetAll returns the rates orders are converted with,
+// sorted by currency and date.
+func (uc *UseCase) GetAll(ctx context.Context) []entity.ExchangeRate {
+	return uc.activeRepo.GetRates(ctx)
+}
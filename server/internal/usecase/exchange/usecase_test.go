@@ -0,0 +1,82 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	repomocks "github.com/ryl1k/INT20H-test-task-server/internal/repo/mocks"
+
+	"github.com/rs/zerolog"
+)
+
+var fileRate = entity.ExchangeRate{
+	Currency: "EUR",
+	Date:     time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+	Rate:     1.1,
+	Source:   entity.ExchangeRateSourceFile,
+}
+
+func newTestUseCase(t *testing.T) (*UseCase, *repomocks.MockActiveExchangeRateRepo, *repomocks.MockExchangeRateRepo) {
+	ctrl := gomock.NewController(t)
+	activeRepo := repomocks.NewMockActiveExchangeRateRepo(ctrl)
+	rateRepo := repomocks.NewMockExchangeRateRepo(ctrl)
+	return New(activeRepo, rateRepo, []entity.ExchangeRate{fileRate}, zerolog.Nop()), activeRepo, rateRepo
+}
+
+func TestLoad(t *testing.T) {
+	uc, activeRepo, rateRepo := newTestUseCase(t)
+
+	stored := fileRate
+	stored.Rate, stored.Source = 1.2, entity.ExchangeRateSourceApi
+	rateRepo.EXPECT().GetAll(gomock.Any()).Return([]entity.ExchangeRate{stored}, nil)
+	activeRepo.EXPECT().ReplaceRates(gomock.Any(), []entity.ExchangeRate{fileRate, stored})
+
+	if err := uc.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		uc, activeRepo, rateRepo := newTestUseCase(t)
+
+		want := entity.ExchangeRate{
+			Currency: "GBP",
+			Date:     time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+			Rate:     1.3,
+			Source:   entity.ExchangeRateSourceApi,
+		}
+		gomock.InOrder(
+			rateRepo.EXPECT().Upsert(gomock.Any(), []entity.ExchangeRate{want}).Return(nil),
+			rateRepo.EXPECT().GetAll(gomock.Any()).Return([]entity.ExchangeRate{want}, nil),
+			activeRepo.EXPECT().ReplaceRates(gomock.Any(), gomock.Len(2)),
+		)
+
+		rates, err := uc.Create(context.Background(), dto.ExchangeRates{Rates: []dto.ExchangeRate{
+			{Currency: "gbp", Date: time.Date(2025, 8, 2, 15, 30, 0, 0, time.UTC), Rate: 1.3},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rates) != 1 || rates[0] != want {
+			t.Errorf("unexpected rates %+v", rates)
+		}
+	})
+
+	t.Run("reporting currency", func(t *testing.T) {
+		uc, _, _ := newTestUseCase(t)
+
+		_, err := uc.Create(context.Background(), dto.ExchangeRates{Rates: []dto.ExchangeRate{
+			{Currency: "USD", Date: time.Now(), Rate: 1},
+		}})
+		if !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+}
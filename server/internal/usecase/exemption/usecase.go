@@ -0,0 +1,71 @@
+package exemption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+
+	"github.com/rs/zerolog"
+)
+
+// UseCase implements business logic for the exemption certificate registry.
+type UseCase struct {
+	taxRepo       repo.TaxRepo
+	exemptionRepo repo.ExemptionRepo
+	logger        zerolog.Logger
+}
+
+func New(taxRepo repo.TaxRepo, exemptionRepo repo.ExemptionRepo, logger zerolog.Logger) *UseCase {
+	l := logger.With().Str("usecase", "exemption").Logger()
+	return &UseCase{
+		taxRepo:       taxRepo,
+		exemptionRepo: exemptionRepo,
+		logger:        l,
+	}
+}
+
+// Create registers an exemption certificate for a customer.
+// The validity period must not end before it starts, and a certificate
+// of a state that is not configured is a bad request.
+func (uc *UseCase) Create(ctx context.Context, certDto dto.ExemptionCertificate) (entity.ExemptionCertificate, error) {
+	if certDto.ValidTo != nil && certDto.ValidTo.Before(certDto.ValidFrom) {
+		return entity.ExemptionCertificate{}, entity.ErrBadRequest
+	}
+
+	state := strings.ToUpper(strings.TrimSpace(certDto.State))
+	if !uc.taxRepo.HasState(ctx, state) {
+		return entity.ExemptionCertificate{}, entity.ErrBadRequest
+	}
+
+	customer := entity.Customer{
+		ExternalRef: certDto.CustomerRef,
+		Name:        certDto.CustomerName,
+	}
+	cert := entity.ExemptionCertificate{
+		Number:        certDto.Number,
+		Type:          certDto.Type,
+		State:         state,
+		Jurisdictions: certDto.Jurisdictions,
+		Components:    certDto.Components,
+		ValidFrom:     certDto.ValidFrom,
+		ValidTo:       certDto.ValidTo,
+	}
+	if cert.Components == nil {
+		cert.Components = []entity.TaxLayerLevel{}
+	}
+
+	created, err := uc.exemptionRepo.Create(ctx, customer, cert)
+	if err != nil {
+		return entity.ExemptionCertificate{}, fmt.Errorf("failed to create exemption certificate: %w", err)
+	}
+	return created, nil
+}
+
+// GetByCustomerRef returns all certificates registered for a customer.
+func (uc *UseCase) GetByCustomerRef(ctx context.Context, customerRef string) ([]entity.ExemptionCertificate, error) {
+	return uc.exemptionRepo.GetByCustomerRef(ctx, customerRef)
+}
@@ -0,0 +1,107 @@
+package exemption
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	repomocks "github.com/ryl1k/INT20H-test-task-server/internal/repo/mocks"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestUseCase(t *testing.T) (*UseCase, *repomocks.MockTaxRepo, *repomocks.MockExemptionRepo) {
+	ctrl := gomock.NewController(t)
+	taxRepo := repomocks.NewMockTaxRepo(ctrl)
+	exemptionRepo := repomocks.NewMockExemptionRepo(ctrl)
+	return New(taxRepo, exemptionRepo, zerolog.Nop()), taxRepo, exemptionRepo
+}
+
+func TestCreate(t *testing.T) {
+	validFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("created", func(t *testing.T) {
+		uc, taxRepo, exemptionRepo := newTestUseCase(t)
+
+		taxRepo.EXPECT().HasState(gomock.Any(), "NJ").Return(true)
+		exemptionRepo.EXPECT().Create(gomock.Any(), entity.Customer{ExternalRef: "acme", Name: "Acme"}, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, customer entity.Customer, cert entity.ExemptionCertificate) (entity.ExemptionCertificate, error) {
+				if cert.Components == nil {
+					t.Error("expected empty components to be stored as an empty list")
+				}
+				if cert.State != "NJ" {
+					t.Errorf("expected normalized state, got %q", cert.State)
+				}
+				cert.Id = 1
+				cert.CustomerRef = customer.ExternalRef
+				return cert, nil
+			})
+
+		cert, err := uc.Create(context.Background(), dto.ExemptionCertificate{
+			CustomerRef:   "acme",
+			CustomerName:  "Acme",
+			Number:        "R-1",
+			Type:          entity.ExemptionTypeResale,
+			State:         " nj ",
+			Jurisdictions: []string{entity.AllJurisdictions},
+			ValidFrom:     validFrom,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cert.Id != 1 || cert.CustomerRef != "acme" {
+			t.Errorf("unexpected certificate %+v", cert)
+		}
+	})
+
+	t.Run("invalid validity period", func(t *testing.T) {
+		uc, _, _ := newTestUseCase(t)
+
+		validTo := validFrom.AddDate(0, 0, -1)
+		_, err := uc.Create(context.Background(), dto.ExemptionCertificate{
+			CustomerRef:   "acme",
+			Number:        "R-1",
+			Jurisdictions: []string{"0001"},
+			ValidFrom:     validFrom,
+			ValidTo:       &validTo,
+		})
+		if !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		uc, taxRepo, _ := newTestUseCase(t)
+
+		taxRepo.EXPECT().HasState(gomock.Any(), "ZZ").Return(false)
+
+		_, err := uc.Create(context.Background(), dto.ExemptionCertificate{
+			CustomerRef:   "acme",
+			Number:        "R-1",
+			State:         "ZZ",
+			Jurisdictions: []string{"0001"},
+			ValidFrom:     validFrom,
+		})
+		if !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		uc, taxRepo, exemptionRepo := newTestUseCase(t)
+
+		taxRepo.EXPECT().HasState(gomock.Any(), "").Return(true)
+		exemptionRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(entity.ExemptionCertificate{}, entity.ErrExemptionCertificateAlreadyExists)
+
+		_, err := uc.Create(context.Background(), dto.ExemptionCertificate{CustomerRef: "acme", Number: "R-1", ValidFrom: validFrom})
+		if !errors.Is(err, entity.ErrExemptionCertificateAlreadyExists) {
+			t.Fatalf("expected ErrExemptionCertificateAlreadyExists, got %v", err)
+		}
+	})
+}
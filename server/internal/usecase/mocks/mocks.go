@@ -45,15 +45,15 @@ func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
 }
 
 // AsyncBatchCreate mocks base method.
-func (m *MockOrderService) AsyncBatchCreate(reader *csv.Reader, closer io.Closer) {
+func (m *MockOrderService) AsyncBatchCreate(reader *csv.Reader, closer io.Closer, options dto.OrderImport) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "AsyncBatchCreate", reader, closer)
+	m.ctrl.Call(m, "AsyncBatchCreate", reader, closer, options)
 }
 
 // AsyncBatchCreate indicates an expected call of AsyncBatchCreate.
-func (mr *MockOrderServiceMockRecorder) AsyncBatchCreate(reader, closer any) *gomock.Call {
+func (mr *MockOrderServiceMockRecorder) AsyncBatchCreate(reader, closer, options any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncBatchCreate", reflect.TypeOf((*MockOrderService)(nil).AsyncBatchCreate), reader, closer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncBatchCreate", reflect.TypeOf((*MockOrderService)(nil).AsyncBatchCreate), reader, closer, options)
 }
 
 // Create mocks base method.
@@ -71,18 +71,33 @@ func (mr *MockOrderServiceMockRecorder) Create(ctx, order any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderService)(nil).Create), ctx, order)
 }
 
+// Delete mocks base method.
+func (m *MockOrderService) Delete(ctx context.Context, filter dto.OrderFilters) (entity.OrderDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, filter)
+	ret0, _ := ret[0].(entity.OrderDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOrderServiceMockRecorder) Delete(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOrderService)(nil).Delete), ctx, filter)
+}
+
 // DeleteAll mocks base method.
-func (m *MockOrderService) DeleteAll(ctx context.Context) error {
+func (m *MockOrderService) DeleteAll(ctx context.Context, token string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAll", ctx)
+	ret := m.ctrl.Call(m, "DeleteAll", ctx, token)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAll indicates an expected call of DeleteAll.
-func (mr *MockOrderServiceMockRecorder) DeleteAll(ctx any) *gomock.Call {
+func (mr *MockOrderServiceMockRecorder) DeleteAll(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockOrderService)(nil).DeleteAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockOrderService)(nil).DeleteAll), ctx, token)
 }
 
 // GetAll mocks base method.
@@ -114,3 +129,599 @@ func (mr *MockOrderServiceMockRecorder) GetById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockOrderService)(nil).GetById), ctx, id)
 }
+
+// History mocks base method.
+func (m *MockOrderService) History(ctx context.Context, id int) ([]entity.OrderEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id)
+	ret0, _ := ret[0].([]entity.OrderEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockOrderServiceMockRecorder) History(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockOrderService)(nil).History), ctx, id)
+}
+
+// Refund mocks base method.
+func (m *MockOrderService) Refund(ctx context.Context, id int, refund dto.OrderRefund) (entity.OrderRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, id, refund)
+	ret0, _ := ret[0].(entity.OrderRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockOrderServiceMockRecorder) Refund(ctx, id, refund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockOrderService)(nil).Refund), ctx, id, refund)
+}
+
+// RequestWipe mocks base method.
+func (m *MockOrderService) RequestWipe(ctx context.Context) (entity.WipeConfirmation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestWipe", ctx)
+	ret0, _ := ret[0].(entity.WipeConfirmation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestWipe indicates an expected call of RequestWipe.
+func (mr *MockOrderServiceMockRecorder) RequestWipe(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestWipe", reflect.TypeOf((*MockOrderService)(nil).RequestWipe), ctx)
+}
+
+// Restore mocks base method.
+func (m *MockOrderService) Restore(ctx context.Context, filter dto.OrderFilters) (entity.OrderRestoration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, filter)
+	ret0, _ := ret[0].(entity.OrderRestoration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockOrderServiceMockRecorder) Restore(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockOrderService)(nil).Restore), ctx, filter)
+}
+
+// Update mocks base method.
+func (m *MockOrderService) Update(ctx context.Context, id, version int, update dto.OrderUpdate) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, update)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOrderServiceMockRecorder) Update(ctx, id, version, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOrderService)(nil).Update), ctx, id, version, update)
+}
+
+// Void mocks base method.
+func (m *MockOrderService) Void(ctx context.Context, id, version int) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, id, version)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockOrderServiceMockRecorder) Void(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockOrderService)(nil).Void), ctx, id, version)
+}
+
+// MockTaxService is a mock of TaxService interface.
+type MockTaxService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxServiceMockRecorder is the mock recorder for MockTaxService.
+type MockTaxServiceMockRecorder struct {
+	mock *MockTaxService
+}
+
+// NewMockTaxService creates a new mock instance.
+func NewMockTaxService(ctrl *gomock.Controller) *MockTaxService {
+	mock := &MockTaxService{ctrl: ctrl}
+	mock.recorder = &MockTaxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxService) EXPECT() *MockTaxServiceMockRecorder {
+	return m.recorder
+}
+
+// Explain mocks base method.
+func (m *MockTaxService) Explain(ctx context.Context, order dto.Order) (entity.TaxExplanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", ctx, order)
+	ret0, _ := ret[0].(entity.TaxExplanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockTaxServiceMockRecorder) Explain(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockTaxService)(nil).Explain), ctx, order)
+}
+
+// MockTaxRecalculationService is a mock of TaxRecalculationService interface.
+type MockTaxRecalculationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxRecalculationServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxRecalculationServiceMockRecorder is the mock recorder for MockTaxRecalculationService.
+type MockTaxRecalculationServiceMockRecorder struct {
+	mock *MockTaxRecalculationService
+}
+
+// NewMockTaxRecalculationService creates a new mock instance.
+func NewMockTaxRecalculationService(ctrl *gomock.Controller) *MockTaxRecalculationService {
+	mock := &MockTaxRecalculationService{ctrl: ctrl}
+	mock.recorder = &MockTaxRecalculationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxRecalculationService) EXPECT() *MockTaxRecalculationServiceMockRecorder {
+	return m.recorder
+}
+
+// AsyncApplyRecalculation mocks base method.
+func (m *MockTaxRecalculationService) AsyncApplyRecalculation(rec entity.TaxRecalculation, actor entity.Actor) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AsyncApplyRecalculation", rec, actor)
+}
+
+// AsyncApplyRecalculation indicates an expected call of AsyncApplyRecalculation.
+func (mr *MockTaxRecalculationServiceMockRecorder) AsyncApplyRecalculation(rec, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncApplyRecalculation", reflect.TypeOf((*MockTaxRecalculationService)(nil).AsyncApplyRecalculation), rec, actor)
+}
+
+// GetRecalculation mocks base method.
+func (m *MockTaxRecalculationService) GetRecalculation(ctx context.Context, id int) (entity.TaxRecalculation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecalculation", ctx, id)
+	ret0, _ := ret[0].(entity.TaxRecalculation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecalculation indicates an expected call of GetRecalculation.
+func (mr *MockTaxRecalculationServiceMockRecorder) GetRecalculation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecalculation", reflect.TypeOf((*MockTaxRecalculationService)(nil).GetRecalculation), ctx, id)
+}
+
+// PreviewRecalculation mocks base method.
+func (m *MockTaxRecalculationService) PreviewRecalculation(ctx context.Context, req dto.TaxRecalculation) (entity.TaxRecalculation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewRecalculation", ctx, req)
+	ret0, _ := ret[0].(entity.TaxRecalculation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewRecalculation indicates an expected call of PreviewRecalculation.
+func (mr *MockTaxRecalculationServiceMockRecorder) PreviewRecalculation(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewRecalculation", reflect.TypeOf((*MockTaxRecalculationService)(nil).PreviewRecalculation), ctx, req)
+}
+
+// ResolveOutOfScope mocks base method.
+func (m *MockTaxRecalculationService) ResolveOutOfScope(ctx context.Context, filter dto.OrderFilters) (entity.OutOfScopeResolution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveOutOfScope", ctx, filter)
+	ret0, _ := ret[0].(entity.OutOfScopeResolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveOutOfScope indicates an expected call of ResolveOutOfScope.
+func (mr *MockTaxRecalculationServiceMockRecorder) ResolveOutOfScope(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveOutOfScope", reflect.TypeOf((*MockTaxRecalculationService)(nil).ResolveOutOfScope), ctx, filter)
+}
+
+// StartRecalculation mocks base method.
+func (m *MockTaxRecalculationService) StartRecalculation(ctx context.Context, id int) (entity.TaxRecalculation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartRecalculation", ctx, id)
+	ret0, _ := ret[0].(entity.TaxRecalculation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartRecalculation indicates an expected call of StartRecalculation.
+func (mr *MockTaxRecalculationServiceMockRecorder) StartRecalculation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRecalculation", reflect.TypeOf((*MockTaxRecalculationService)(nil).StartRecalculation), ctx, id)
+}
+
+// MockExemptionService is a mock of ExemptionService interface.
+type MockExemptionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExemptionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockExemptionServiceMockRecorder is the mock recorder for MockExemptionService.
+type MockExemptionServiceMockRecorder struct {
+	mock *MockExemptionService
+}
+
+// NewMockExemptionService creates a new mock instance.
+func NewMockExemptionService(ctrl *gomock.Controller) *MockExemptionService {
+	mock := &MockExemptionService{ctrl: ctrl}
+	mock.recorder = &MockExemptionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExemptionService) EXPECT() *MockExemptionServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockExemptionService) Create(ctx context.Context, cert dto.ExemptionCertificate) (entity.ExemptionCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, cert)
+	ret0, _ := ret[0].(entity.ExemptionCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockExemptionServiceMockRecorder) Create(ctx, cert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExemptionService)(nil).Create), ctx, cert)
+}
+
+// GetByCustomerRef mocks base method.
+func (m *MockExemptionService) GetByCustomerRef(ctx context.Context, customerRef string) ([]entity.ExemptionCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCustomerRef", ctx, customerRef)
+	ret0, _ := ret[0].([]entity.ExemptionCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCustomerRef indicates an expected call of GetByCustomerRef.
+func (mr *MockExemptionServiceMockRecorder) GetByCustomerRef(ctx, customerRef any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCustomerRef", reflect.TypeOf((*MockExemptionService)(nil).GetByCustomerRef), ctx, customerRef)
+}
+
+// MockCustomerService is a mock of CustomerService interface.
+type MockCustomerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomerServiceMockRecorder is the mock recorder for MockCustomerService.
+type MockCustomerServiceMockRecorder struct {
+	mock *MockCustomerService
+}
+
+// NewMockCustomerService creates a new mock instance.
+func NewMockCustomerService(ctrl *gomock.Controller) *MockCustomerService {
+	mock := &MockCustomerService{ctrl: ctrl}
+	mock.recorder = &MockCustomerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerService) EXPECT() *MockCustomerServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCustomerService) Create(ctx context.Context, customer dto.Customer) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, customer)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCustomerServiceMockRecorder) Create(ctx, customer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerService)(nil).Create), ctx, customer)
+}
+
+// Delete mocks base method.
+func (m *MockCustomerService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCustomerServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerService)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockCustomerService) GetAll(ctx context.Context, limit, offset int) (entity.CustomerList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, limit, offset)
+	ret0, _ := ret[0].(entity.CustomerList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCustomerServiceMockRecorder) GetAll(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCustomerService)(nil).GetAll), ctx, limit, offset)
+}
+
+// GetById mocks base method.
+func (m *MockCustomerService) GetById(ctx context.Context, id int) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockCustomerServiceMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCustomerService)(nil).GetById), ctx, id)
+}
+
+// GetOrders mocks base method.
+func (m *MockCustomerService) GetOrders(ctx context.Context, id int, filter dto.OrderFilters) (entity.OrderList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, id, filter)
+	ret0, _ := ret[0].(entity.OrderList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockCustomerServiceMockRecorder) GetOrders(ctx, id, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockCustomerService)(nil).GetOrders), ctx, id, filter)
+}
+
+// GetTotals mocks base method.
+func (m *MockCustomerService) GetTotals(ctx context.Context, id int) (entity.CustomerTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotals", ctx, id)
+	ret0, _ := ret[0].(entity.CustomerTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotals indicates an expected call of GetTotals.
+func (mr *MockCustomerServiceMockRecorder) GetTotals(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotals", reflect.TypeOf((*MockCustomerService)(nil).GetTotals), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockCustomerService) Update(ctx context.Context, id int, customer dto.Customer) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, customer)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCustomerServiceMockRecorder) Update(ctx, id, customer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerService)(nil).Update), ctx, id, customer)
+}
+
+// MockTaxOverrideService is a mock of TaxOverrideService interface.
+type MockTaxOverrideService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxOverrideServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxOverrideServiceMockRecorder is the mock recorder for MockTaxOverrideService.
+type MockTaxOverrideServiceMockRecorder struct {
+	mock *MockTaxOverrideService
+}
+
+// NewMockTaxOverrideService creates a new mock instance.
+func NewMockTaxOverrideService(ctrl *gomock.Controller) *MockTaxOverrideService {
+	mock := &MockTaxOverrideService{ctrl: ctrl}
+	mock.recorder = &MockTaxOverrideServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxOverrideService) EXPECT() *MockTaxOverrideServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTaxOverrideService) Create(ctx context.Context, override dto.TaxOverride) (entity.TaxOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, override)
+	ret0, _ := ret[0].(entity.TaxOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTaxOverrideServiceMockRecorder) Create(ctx, override any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaxOverrideService)(nil).Create), ctx, override)
+}
+
+// Delete mocks base method.
+func (m *MockTaxOverrideService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTaxOverrideServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaxOverrideService)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockTaxOverrideService) GetAll(ctx context.Context) []entity.TaxOverride {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entity.TaxOverride)
+	return ret0
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTaxOverrideServiceMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTaxOverrideService)(nil).GetAll), ctx)
+}
+
+// MockExchangeRateService is a mock of ExchangeRateService interface.
+type MockExchangeRateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockExchangeRateServiceMockRecorder is the mock recorder for MockExchangeRateService.
+type MockExchangeRateServiceMockRecorder struct {
+	mock *MockExchangeRateService
+}
+
+// NewMockExchangeRateService creates a new mock instance.
+func NewMockExchangeRateService(ctrl *gomock.Controller) *MockExchangeRateService {
+	mock := &MockExchangeRateService{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateService) EXPECT() *MockExchangeRateServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockExchangeRateService) Create(ctx context.Context, rates dto.ExchangeRates) ([]entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rates)
+	ret0, _ := ret[0].([]entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockExchangeRateServiceMockRecorder) Create(ctx, rates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExchangeRateService)(nil).Create), ctx, rates)
+}
+
+// GetAll mocks base method.
+func (m *MockExchangeRateService) GetAll(ctx context.Context) []entity.ExchangeRate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entity.ExchangeRate)
+	return ret0
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockExchangeRateServiceMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockExchangeRateService)(nil).GetAll), ctx)
+}
+
+// MockBoundaryService is a mock of BoundaryService interface.
+type MockBoundaryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBoundaryServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBoundaryServiceMockRecorder is the mock recorder for MockBoundaryService.
+type MockBoundaryServiceMockRecorder struct {
+	mock *MockBoundaryService
+}
+
+// NewMockBoundaryService creates a new mock instance.
+func NewMockBoundaryService(ctrl *gomock.Controller) *MockBoundaryService {
+	mock := &MockBoundaryService{ctrl: ctrl}
+	mock.recorder = &MockBoundaryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBoundaryService) EXPECT() *MockBoundaryServiceMockRecorder {
+	return m.recorder
+}
+
+// Activate mocks base method.
+func (m *MockBoundaryService) Activate(ctx context.Context, id int) (entity.BoundarySet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Activate", ctx, id)
+	ret0, _ := ret[0].(entity.BoundarySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Activate indicates an expected call of Activate.
+func (mr *MockBoundaryServiceMockRecorder) Activate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Activate", reflect.TypeOf((*MockBoundaryService)(nil).Activate), ctx, id)
+}
+
+// GetById mocks base method.
+func (m *MockBoundaryService) GetById(ctx context.Context, id int) (entity.BoundarySet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(entity.BoundarySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockBoundaryServiceMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockBoundaryService)(nil).GetById), ctx, id)
+}
+
+// Upload mocks base method.
+func (m *MockBoundaryService) Upload(ctx context.Context, state string, r io.Reader) (entity.BoundarySet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, state, r)
+	ret0, _ := ret[0].(entity.BoundarySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockBoundaryServiceMockRecorder) Upload(ctx, state, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockBoundaryService)(nil).Upload), ctx, state, r)
+}
@@ -0,0 +1,351 @@
+package order
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+)
+
+// recalculationPageSize is how many orders are selected at a time
+// while previewing a recalculation.
+const recalculationPageSize = 500
+
+// PreviewRecalculation resolves the tax of the orders matching the request
+// again with the loaded rates and stores the orders whose tax would change,
+// without changing any of them. Orders are loaded a page at a time, with
+// the items of the whole page in one query. Orders that can no longer be
+// changed, such as voided or refunded ones, are skipped.
+func (uc *UseCase) PreviewRecalculation(ctx context.Context, req dto.TaxRecalculation) (entity.TaxRecalculation, error) {
+	// only the current rates are loaded, so a preview for another version
+	// of them cannot be computed
+	ratesVersion := uc.currentRatesVersion(ctx)
+	if req.ExpectedRatesVersion != "" && req.ExpectedRatesVersion != ratesVersion {
+		return entity.TaxRecalculation{}, entity.ErrRatesVersionMismatch
+	}
+	if req.FromDate != nil && req.ToDate != nil && req.FromDate.After(*req.ToDate) {
+		return entity.TaxRecalculation{}, entity.ErrBadRequest
+	}
+
+	rec := entity.TaxRecalculation{
+		Status:       entity.TaxRecalculationStatusPreviewed,
+		RatesVersion: ratesVersion,
+		Filter: entity.TaxRecalculationFilter{
+			FromDate:      req.FromDate,
+			ToDate:        req.ToDate,
+			ReportingCode: req.ReportingCode,
+			Status:        req.Status,
+		},
+		Changes:   []entity.OrderTaxChange{},
+		CreatedAt: time.Now(),
+	}
+
+	filter := dto.OrderFilters{
+		Limit:         recalculationPageSize,
+		FromDate:      req.FromDate,
+		ToDate:        req.ToDate,
+		ReportingCode: req.ReportingCode,
+		SortBy:        "id",
+		SortOrder:     "asc",
+	}
+	if req.Status != "" {
+		filter.Statuses = []string{string(req.Status)}
+	}
+	certificates := make(map[string][]entity.ExemptionCertificate)
+
+	for {
+		list, err := uc.orderRepo.GetAll(ctx, filter)
+		if err != nil {
+			return entity.TaxRecalculation{}, fmt.Errorf("failed to get orders: %w", err)
+		}
+
+		// the list does not carry the items of the orders
+		ids := make([]int, 0, len(list.Orders))
+		for _, o := range list.Orders {
+			if o.Editable() {
+				ids = append(ids, o.Id)
+			}
+		}
+		items, err := uc.orderRepo.GetItems(ctx, ids)
+		if err != nil {
+			return entity.TaxRecalculation{}, fmt.Errorf("failed to get order items: %w", err)
+		}
+
+		for _, order := range list.Orders {
+			if !order.Editable() {
+				rec.Skipped++
+				continue
+			}
+			order.Items = items[order.Id]
+
+			recalculated, err := uc.recalculate(ctx, order, certificates)
+			if err != nil {
+				return entity.TaxRecalculation{}, err
+			}
+
+			before, after := entity.NewOrderTaxFigures(order), entity.NewOrderTaxFigures(recalculated)
+			if before.Equal(after) {
+				rec.Unchanged++
+				continue
+			}
+			rec.Changes = append(rec.Changes, entity.OrderTaxChange{
+				OrderId: order.Id,
+				Version: order.Version,
+				Before:  before,
+				After:   after,
+			})
+		}
+
+		filter.Offset += len(list.Orders)
+		if len(list.Orders) < filter.Limit || filter.Offset >= list.Total {
+			break
+		}
+	}
+
+	id, err := uc.recalculationRepo.Create(ctx, rec)
+	if err != nil {
+		return entity.TaxRecalculation{}, fmt.Errorf("failed to create tax recalculation: %w", err)
+	}
+	rec.Id = id
+
+	return rec, nil
+}
+
+// currentRatesVersion fingerprints the loaded tax data together with the
+// boundaries and overrides active now, which change at runtime.
+func (uc *UseCase) currentRatesVersion(ctx context.Context) string {
+	sum := sha256.Sum256([]byte(uc.ratesVersion + uc.taxRepo.DataVersion(ctx)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// GetRecalculation returns a recalculation with its changes and progress.
+func (uc *UseCase) GetRecalculation(ctx context.Context, id int) (entity.TaxRecalculation, error) {
+	return uc.recalculationRepo.GetById(ctx, id)
+}
+
+// StartRecalculation marks a previewed recalculation as being applied.
+// It can only be applied once and with the rates it was previewed with.
+func (uc *UseCase) StartRecalculation(ctx context.Context, id int) (entity.TaxRecalculation, error) {
+	rec, err := uc.recalculationRepo.GetById(ctx, id)
+	if err != nil {
+		return entity.TaxRecalculation{}, err
+	}
+	if rec.Status != entity.TaxRecalculationStatusPreviewed {
+		return entity.TaxRecalculation{}, entity.ErrTaxRecalculationNotPending
+	}
+	if rec.RatesVersion != uc.currentRatesVersion(ctx) {
+		return entity.TaxRecalculation{}, entity.ErrRatesVersionMismatch
+	}
+
+	if err := uc.recalculationRepo.StartApply(ctx, id); err != nil {
+		return entity.TaxRecalculation{}, err
+	}
+	rec.Status = entity.TaxRecalculationStatusApplying
+
+	return rec, nil
+}
+
+// AsyncApplyRecalculation applies the changes of a recalculation started
+// by StartRecalculation in batches of ordersBatchSize and stores the progress
+// after every batch. Orders changed or deleted since the preview are left
+// out as conflicts. Every applied change is recorded in the order history
+// as made by the actor. Processing stops when the timeout is reached,
+// leaving the recalculation failed with the changes applied so far.
+func (uc *UseCase) AsyncApplyRecalculation(rec entity.TaxRecalculation, actor entity.Actor) {
+	l := uc.logger.With().Str("method", "async_apply_recalculation").Int("id", rec.Id).Logger()
+
+	baseCtx := entity.ContextWithActor(uc.outerCtx, actor)
+	ctx, cancel := context.WithTimeout(baseCtx, uc.processingTimeout)
+	defer cancel()
+
+	certificates := make(map[string][]entity.ExemptionCertificate)
+	rec.Status = entity.TaxRecalculationStatusApplied
+
+	for start := 0; start < len(rec.Changes); start += uc.ordersBatchSize {
+		end := min(start+uc.ordersBatchSize, len(rec.Changes))
+
+		for _, change := range rec.Changes[start:end] {
+			err := uc.applyChange(ctx, rec.Id, change, certificates)
+			if errors.Is(err, entity.ErrOrderVersionConflict) || errors.Is(err, entity.ErrOrderNotFound) ||
+				errors.Is(err, entity.ErrOrderNotEditable) {
+				rec.Conflicts++
+				continue
+			}
+			if err != nil {
+				l.Error().Err(err).Int("order_id", change.OrderId).Msg("failed to apply change")
+				rec.Status = entity.TaxRecalculationStatusFailed
+				break
+			}
+
+			rec.Applied++
+		}
+
+		if rec.Status == entity.TaxRecalculationStatusFailed {
+			break
+		}
+		if err := uc.recalculationRepo.UpdateProgress(baseCtx, rec); err != nil {
+			l.Error().Err(err).Msg("failed to store progress")
+		}
+	}
+
+	now := time.Now()
+	rec.AppliedAt = &now
+	if err := uc.recalculationRepo.UpdateProgress(baseCtx, rec); err != nil {
+		l.Error().Err(err).Msg("failed to store result")
+	}
+
+	l.Info().
+		Str("status", string(rec.Status)).
+		Int("applied", rec.Applied).
+		Int("conflicts", rec.Conflicts).
+		Dur("duration", time.Since(rec.CreatedAt)).
+		Msg("finished applying tax recalculation")
+}
+
+// applyChange recalculates the order of the change, unless it was changed
+// since the preview, and stores it together with the event recording it.
+// An order whose tax no longer comes out as previewed, e.g. because the
+// boundaries or overrides changed meanwhile, is a conflict as well.
+func (uc *UseCase) applyChange(ctx context.Context, recalculationId int, change entity.OrderTaxChange, certificates map[string][]entity.ExemptionCertificate) error {
+	order, err := uc.getEditable(ctx, change.OrderId, change.Version)
+	if err != nil {
+		return err
+	}
+
+	recalculated, err := uc.recalculate(ctx, order, certificates)
+	if err != nil {
+		return err
+	}
+	if !entity.NewOrderTaxFigures(recalculated).Equal(change.After) {
+		return entity.ErrOrderVersionConflict
+	}
+
+	stored := recalculated
+	stored.Version++
+	event := newEvent(ctx, entity.OrderEventRecalculated, &order, &stored)
+	event.Source = entity.OrderEventSourceRecalculation
+	event.SourceId = strconv.Itoa(recalculationId)
+
+	if err := uc.orderRepo.Update(ctx, recalculated, event); err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	return nil
+}
+
+// recalculate resolves the tax of a stored order again, looking up the
+// exemption certificates of every customer only once.
+func (uc *UseCase) recalculate(ctx context.Context, order entity.Order, certificates map[string][]entity.ExemptionCertificate) (entity.Order, error) {
+	certs, ok := certificates[order.CustomerRef]
+	if !ok {
+		var err error
+		certs, err = uc.getCertificates(ctx, order.CustomerRef)
+		if err != nil {
+			return entity.Order{}, fmt.Errorf("failed to get exemption certificates: %w", err)
+		}
+		certificates[order.CustomerRef] = certs
+	}
+
+	recalculated, err := uc.calculateConverted(ctx, newOrderRequest(order), certs)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	recalculated.Id, recalculated.Version = order.Id, order.Version
+	recalculated.ImportId = order.ImportId
+	recalculated.UpdatedAt = time.Now()
+	if err := carryStatus(order, &recalculated, recalculated.UpdatedAt); err != nil {
+		return entity.Order{}, err
+	}
+	return recalculated, nil
+}
+
+// ResolveOutOfScope resolves the tax of the out-of-scope orders, of the
+// orders whose tax failed to resolve and of the pending orders left by
+// imports matching the filter again and stores the ones that now match
+// a jurisdiction as completed, recording the change in their history.
+// Pending orders are stored with whatever status their tax resolves to,
+// but only the completed ones count as resolved.
+func (uc *UseCase) ResolveOutOfScope(ctx context.Context, filter dto.OrderFilters) (entity.OutOfScopeResolution, error) {
+	filter.Statuses = []string{
+		string(entity.OrderStatusOutOfScope),
+		string(entity.OrderStatusFailedTaxResolution),
+		string(entity.OrderStatusPending),
+	}
+	filter.SortBy, filter.SortOrder = "id", "asc"
+	filter.Limit, filter.Offset = recalculationPageSize, 0
+
+	// resolved orders leave the filter, so the ids are collected first
+	var ids []int
+	for {
+		list, err := uc.orderRepo.GetAll(ctx, filter)
+		if err != nil {
+			return entity.OutOfScopeResolution{}, fmt.Errorf("failed to get orders: %w", err)
+		}
+		for _, o := range list.Orders {
+			ids = append(ids, o.Id)
+		}
+
+		filter.Offset += len(list.Orders)
+		if len(list.Orders) < filter.Limit || filter.Offset >= list.Total {
+			break
+		}
+	}
+
+	var resolution entity.OutOfScopeResolution
+	certificates := make(map[string][]entity.ExemptionCertificate)
+
+	for _, id := range ids {
+		resolution.Checked++
+
+		ok, err := uc.resolveOutOfScope(ctx, id, certificates)
+		if errors.Is(err, entity.ErrOrderVersionConflict) || errors.Is(err, entity.ErrOrderNotFound) ||
+			errors.Is(err, entity.ErrOrderNotEditable) {
+			resolution.Conflicts++
+			continue
+		}
+		if err != nil {
+			return resolution, err
+		}
+		if ok {
+			resolution.Resolved++
+		}
+	}
+
+	return resolution, nil
+}
+
+// resolveOutOfScope resolves the tax of an out-of-scope order, of one
+// whose tax failed to resolve or of a pending one again and stores it when
+// it now matches a jurisdiction, or when it was pending, together with the
+// event recording it, reporting whether it now matches a jurisdiction.
+func (uc *UseCase) resolveOutOfScope(ctx context.Context, id int, certificates map[string][]entity.ExemptionCertificate) (bool, error) {
+	order, err := uc.getEditable(ctx, id, 0)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != entity.OrderStatusOutOfScope && order.Status != entity.OrderStatusFailedTaxResolution &&
+		order.Status != entity.OrderStatusPending {
+		return false, entity.ErrOrderVersionConflict
+	}
+
+	resolved, err := uc.recalculate(ctx, order, certificates)
+	if err != nil {
+		return false, err
+	}
+	completed := resolved.Status == entity.OrderStatusCompleted
+	if !completed && order.Status != entity.OrderStatusPending {
+		return false, nil
+	}
+
+	stored := resolved
+	stored.Version++
+	if err := uc.orderRepo.Update(ctx, resolved, newEvent(ctx, entity.OrderEventRecalculated, &order, &stored)); err != nil {
+		return false, fmt.Errorf("failed to update order: %w", err)
+	}
+	return completed, nil
+}
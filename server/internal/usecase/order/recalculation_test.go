@@ -0,0 +1,280 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	repomocks "github.com/ryl1k/INT20H-test-task-server/internal/repo/mocks"
+)
+
+func newTestRecalculationUseCase(t *testing.T) (*UseCase, *repomocks.MockTaxRepo, *repomocks.MockOrderRepo, *repomocks.MockTaxRecalculationRepo) {
+	uc, taxRepo, orderRepo, _, _ := newTestUseCase(t)
+	recalculationRepo := repomocks.NewMockTaxRecalculationRepo(gomock.NewController(t))
+	uc.recalculationRepo = recalculationRepo
+	taxRepo.EXPECT().DataVersion(gomock.Any()).Return(testDataVersion).AnyTimes()
+	return uc, taxRepo, orderRepo, recalculationRepo
+}
+
+// testDataVersion is the version of the boundaries and overrides
+// active in the tax repo of recalculation tests.
+const testDataVersion = "boundaries"
+
+func newRecalculationTestOrder(id int, lon float64) entity.Order {
+	return entity.Order{
+		Id:               id,
+		Latitude:         40.7,
+		Longitude:        lon,
+		Subtotal:         100,
+		TotalAmount:      108,
+		TaxAmount:        8,
+		CompositeTaxRate: 0.08,
+		Components:       []entity.OrderComponentTax{{Component: entity.OrderComponentSubtotal, Amount: 100, TaxableAmount: 100, TaxRate: 0.08, TaxAmount: 8}},
+		GeocodingMethod:  entity.GeocodingMethodCoordinates,
+		Status:           entity.OrderStatusCompleted,
+		Version:          2,
+		CreatedAt:        time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func newRecalculationTestTax(rate float64) *entity.LocationTax {
+	return &entity.LocationTax{JurisdictionTax: entity.JurisdictionTax{
+		CompositeRate: rate,
+		Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: rate - 0.04},
+	}}
+}
+
+func TestPreviewRecalculation(t *testing.T) {
+	uc, taxRepo, orderRepo, recalculationRepo := newTestRecalculationUseCase(t)
+	ctx := context.Background()
+
+	t.Run("other rates version", func(t *testing.T) {
+		_, err := uc.PreviewRecalculation(ctx, dto.TaxRecalculation{ExpectedRatesVersion: "other"})
+		if !errors.Is(err, entity.ErrRatesVersionMismatch) {
+			t.Fatalf("expected ErrRatesVersionMismatch, got %v", err)
+		}
+	})
+
+	t.Run("preview", func(t *testing.T) {
+		changed, unchanged := newRecalculationTestOrder(5, -74), newRecalculationTestOrder(7, -73)
+		// stored with floating point noise, the same tax in cents
+		unchanged.TaxAmount, unchanged.CompositeTaxRate = 8.0000000001, 0.08+1e-12
+		voided := newRecalculationTestOrder(6, -74)
+		voided.Status = entity.OrderStatusVoided
+
+		from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+		orderRepo.EXPECT().GetAll(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f dto.OrderFilters) (entity.OrderList, error) {
+			if f.FromDate != &from || f.ReportingCode != "NY-1" || f.SortBy != "id" || f.Offset != 0 {
+				t.Errorf("unexpected filter %+v", f)
+			}
+			return entity.OrderList{Orders: []entity.Order{changed, voided, unchanged}, Total: 3}, nil
+		})
+		orderRepo.EXPECT().GetItems(gomock.Any(), []int{5, 7}).Return(map[int][]entity.OrderItem{}, nil)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(newRecalculationTestTax(0.09), true)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -73.0).Return(newRecalculationTestTax(0.08), true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false).Times(2)
+		recalculationRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(11, nil)
+
+		rec, err := uc.PreviewRecalculation(ctx, dto.TaxRecalculation{FromDate: &from, ReportingCode: "NY-1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Id != 11 || rec.Status != entity.TaxRecalculationStatusPreviewed || rec.RatesVersion != uc.currentRatesVersion(ctx) {
+			t.Errorf("unexpected recalculation %+v", rec)
+		}
+		if rec.Unchanged != 1 || rec.Skipped != 1 || len(rec.Changes) != 1 {
+			t.Fatalf("unexpected counts %+v", rec)
+		}
+		change := rec.Changes[0]
+		if change.OrderId != 5 || change.Version != 2 || change.Before.TaxAmount != 8 ||
+			change.After.TaxAmount != 9 || change.After.TotalAmount != 109 || change.After.CompositeTaxRate != 0.09 {
+			t.Errorf("unexpected change %+v", change)
+		}
+	})
+}
+
+func TestApplyRecalculation(t *testing.T) {
+	uc, taxRepo, orderRepo, recalculationRepo := newTestRecalculationUseCase(t)
+	ctx := context.Background()
+
+	t.Run("already applied", func(t *testing.T) {
+		recalculationRepo.EXPECT().GetById(gomock.Any(), 11).
+			Return(entity.TaxRecalculation{Id: 11, Status: entity.TaxRecalculationStatusApplied, RatesVersion: uc.currentRatesVersion(ctx)}, nil)
+
+		if _, err := uc.StartRecalculation(ctx, 11); !errors.Is(err, entity.ErrTaxRecalculationNotPending) {
+			t.Fatalf("expected ErrTaxRecalculationNotPending, got %v", err)
+		}
+	})
+
+	t.Run("rates changed since preview", func(t *testing.T) {
+		recalculationRepo.EXPECT().GetById(gomock.Any(), 11).
+			Return(entity.TaxRecalculation{Id: 11, Status: entity.TaxRecalculationStatusPreviewed, RatesVersion: "old"}, nil)
+
+		if _, err := uc.StartRecalculation(ctx, 11); !errors.Is(err, entity.ErrRatesVersionMismatch) {
+			t.Fatalf("expected ErrRatesVersionMismatch, got %v", err)
+		}
+	})
+
+	t.Run("started", func(t *testing.T) {
+		recalculationRepo.EXPECT().GetById(gomock.Any(), 11).
+			Return(entity.TaxRecalculation{Id: 11, Status: entity.TaxRecalculationStatusPreviewed, RatesVersion: uc.currentRatesVersion(ctx)}, nil)
+		recalculationRepo.EXPECT().StartApply(gomock.Any(), 11).Return(nil)
+
+		rec, err := uc.StartRecalculation(ctx, 11)
+		if err != nil || rec.Status != entity.TaxRecalculationStatusApplying {
+			t.Fatalf("unexpected recalculation %+v, %v", rec, err)
+		}
+	})
+
+	t.Run("applied in batches", func(t *testing.T) {
+		actor := entity.Actor{Key: "0a1b2c3d4e5f", User: "alice"}
+		rec := entity.TaxRecalculation{
+			Id:     11,
+			Status: entity.TaxRecalculationStatusApplying,
+			Changes: []entity.OrderTaxChange{
+				{OrderId: 5, Version: 2, After: entity.OrderTaxFigures{Status: entity.OrderStatusCompleted, CompositeTaxRate: 0.09, TaxAmount: 9, TotalAmount: 109}},
+				{OrderId: 8, Version: 1},
+			},
+		}
+
+		orderRepo.EXPECT().GetById(gomock.Any(), 5).Return(newRecalculationTestOrder(5, -74), nil)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(newRecalculationTestTax(0.09), true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+		orderRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o entity.Order, e entity.OrderEvent) error {
+			if o.Id != 5 || o.Version != 2 || o.TaxAmount != 9 {
+				t.Errorf("unexpected updated order %+v", o)
+			}
+			if e.Type != entity.OrderEventRecalculated || e.Source != entity.OrderEventSourceRecalculation ||
+				e.SourceId != "11" || e.Actor != actor || e.Before.TaxAmount != 8 || e.After.Version != 3 {
+				t.Errorf("unexpected event %+v", e)
+			}
+			return nil
+		})
+
+		// changed since the preview
+		changed := newRecalculationTestOrder(8, -74)
+		changed.Version = 2
+		orderRepo.EXPECT().GetById(gomock.Any(), 8).Return(changed, nil)
+
+		var progress []entity.TaxRecalculation
+		recalculationRepo.EXPECT().UpdateProgress(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r entity.TaxRecalculation) error {
+			progress = append(progress, r)
+			return nil
+		}).Times(3)
+
+		uc.AsyncApplyRecalculation(rec, actor)
+
+		if progress[0].Applied != 1 || progress[0].Conflicts != 0 {
+			t.Errorf("unexpected progress after first batch %+v", progress[0])
+		}
+		last := progress[2]
+		if last.Status != entity.TaxRecalculationStatusApplied || last.Applied != 1 || last.Conflicts != 1 || last.AppliedAt == nil {
+			t.Errorf("unexpected result %+v", last)
+		}
+	})
+
+	t.Run("tax changed since preview", func(t *testing.T) {
+		rec := entity.TaxRecalculation{
+			Id:     12,
+			Status: entity.TaxRecalculationStatusApplying,
+			Changes: []entity.OrderTaxChange{
+				{OrderId: 5, Version: 2, After: entity.OrderTaxFigures{Status: entity.OrderStatusCompleted, CompositeTaxRate: 0.09, TaxAmount: 9, TotalAmount: 109}},
+			},
+		}
+
+		// e.g. another boundary set was activated meanwhile
+		orderRepo.EXPECT().GetById(gomock.Any(), 5).Return(newRecalculationTestOrder(5, -74), nil)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(newRecalculationTestTax(0.1), true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+
+		var last entity.TaxRecalculation
+		recalculationRepo.EXPECT().UpdateProgress(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r entity.TaxRecalculation) error {
+			last = r
+			return nil
+		}).Times(2)
+
+		uc.AsyncApplyRecalculation(rec, entity.Actor{})
+
+		if last.Status != entity.TaxRecalculationStatusApplied || last.Applied != 0 || last.Conflicts != 1 {
+			t.Errorf("unexpected result %+v", last)
+		}
+	})
+}
+
+func TestCurrentRatesVersion(t *testing.T) {
+	uc, taxRepo, _, _, _, _ := newTestUseCaseWithEvents(t)
+	ctx := context.Background()
+
+	taxRepo.EXPECT().DataVersion(gomock.Any()).Return("boundaries").Times(2)
+	taxRepo.EXPECT().DataVersion(gomock.Any()).Return("activated")
+
+	version := uc.currentRatesVersion(ctx)
+	if version == "" || uc.currentRatesVersion(ctx) != version {
+		t.Fatalf("expected a stable version, got %q", version)
+	}
+	if uc.currentRatesVersion(ctx) == version {
+		t.Error("expected the version to change with the active boundaries")
+	}
+}
+
+func TestResolveOutOfScope(t *testing.T) {
+	uc, taxRepo, orderRepo, _ := newTestRecalculationUseCase(t)
+	ctx := context.Background()
+
+	matching, outside, pending := newRecalculationTestOrder(20, -74), newRecalculationTestOrder(21, -10), newRecalculationTestOrder(22, -12)
+	for _, o := range []*entity.Order{&matching, &outside, &pending} {
+		o.Status = entity.OrderStatusOutOfScope
+		o.TotalAmount, o.TaxAmount, o.CompositeTaxRate = 100, 0, 0
+		o.Components = []entity.OrderComponentTax{{Component: entity.OrderComponentSubtotal, Amount: 100, TaxableAmount: 100}}
+	}
+	outside.Status = entity.OrderStatusFailedTaxResolution
+	pending.Status = entity.OrderStatusPending
+
+	orderRepo.EXPECT().GetAll(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f dto.OrderFilters) (entity.OrderList, error) {
+		want := []string{string(entity.OrderStatusOutOfScope), string(entity.OrderStatusFailedTaxResolution), string(entity.OrderStatusPending)}
+		if !slices.Equal(f.Statuses, want) || f.ImportId != "abc" {
+			t.Errorf("unexpected filter %+v", f)
+		}
+		return entity.OrderList{Orders: []entity.Order{matching, outside, pending}, Total: 3}, nil
+	})
+	orderRepo.EXPECT().GetById(gomock.Any(), 20).Return(matching, nil)
+	orderRepo.EXPECT().GetById(gomock.Any(), 21).Return(outside, nil)
+	orderRepo.EXPECT().GetById(gomock.Any(), 22).Return(pending, nil)
+	taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(newRecalculationTestTax(0.08), true)
+	taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -10.0).Return(nil, false)
+	taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -12.0).Return(nil, false)
+	taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+	orderRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(_ context.Context, o entity.Order, e entity.OrderEvent) error {
+		if o.Id == 22 {
+			// pending orders are stored with whatever their tax resolved to
+			if o.Status != entity.OrderStatusOutOfScope || e.Before.Status != entity.OrderStatusPending {
+				t.Errorf("unexpected stored pending order %+v", o)
+			}
+			return nil
+		}
+		if e.OrderId != 20 || e.Type != entity.OrderEventRecalculated {
+			t.Errorf("unexpected event %+v", e)
+		}
+		if o.Id != 20 || o.Status != entity.OrderStatusCompleted || o.TaxAmount != 8 || o.Breakdown.StateRate != 0.04 {
+			t.Errorf("unexpected resolved order %+v", o)
+		}
+		if len(o.StatusTransitions) != 1 || o.StatusTransitions[0].From != entity.OrderStatusOutOfScope || o.StatusTransitions[0].To != entity.OrderStatusCompleted {
+			t.Errorf("unexpected status transitions %+v", o.StatusTransitions)
+		}
+		return nil
+	})
+
+	resolution, err := uc.ResolveOutOfScope(ctx, dto.OrderFilters{ImportId: "abc", Statuses: []string{string(entity.OrderStatusCompleted)}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolution != (entity.OutOfScopeResolution{Checked: 3, Resolved: 1}) {
+		t.Errorf("unexpected resolution %+v", resolution)
+	}
+}
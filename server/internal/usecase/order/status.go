@@ -0,0 +1,85 @@
+package order
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+// statusTransitions lists the statuses an order can move to from each status.
+// Resolving the tax again may move an order between the statuses of a resolved
+// tax until it is voided or refunded. Only completed orders, which carry a
+// tax, can be refunded, refunds only move on to a full refund, and voided
+// and refunded orders are final.
+var statusTransitions = map[entity.OrderStatus][]entity.OrderStatus{
+	entity.OrderStatusPending: {
+		entity.OrderStatusCompleted,
+		entity.OrderStatusOutOfScope,
+		entity.OrderStatusFailedTaxResolution,
+		entity.OrderStatusVoided,
+	},
+	entity.OrderStatusCompleted: {
+		entity.OrderStatusOutOfScope,
+		entity.OrderStatusFailedTaxResolution,
+		entity.OrderStatusVoided,
+		entity.OrderStatusPartiallyRefunded,
+		entity.OrderStatusRefunded,
+	},
+	entity.OrderStatusOutOfScope: {
+		entity.OrderStatusCompleted,
+		entity.OrderStatusFailedTaxResolution,
+		entity.OrderStatusVoided,
+	},
+	entity.OrderStatusFailedTaxResolution: {
+		entity.OrderStatusCompleted,
+		entity.OrderStatusOutOfScope,
+		entity.OrderStatusVoided,
+	},
+	entity.OrderStatusPartiallyRefunded: {
+		entity.OrderStatusRefunded,
+	},
+}
+
+// canTransition reports whether an order can move between the statuses.
+// Staying at the same status is not a transition and is always allowed.
+func canTransition(from, to entity.OrderStatus) bool {
+	return from == to || slices.Contains(statusTransitions[from], to)
+}
+
+// transition moves the order to the status at the given time and records
+// the change in its transitions. Transitions not allowed from the status
+// of the order return ErrInvalidStatusTransition.
+func transition(order *entity.Order, to entity.OrderStatus, at time.Time) error {
+	if !canTransition(order.Status, to) {
+		return entity.ErrInvalidStatusTransition
+	}
+	if order.Status == to {
+		return nil
+	}
+
+	transitions := order.StatusTransitions
+	order.StatusTransitions = append(transitions[:len(transitions):len(transitions)], entity.StatusTransition{
+		From: order.Status,
+		To:   to,
+		At:   at,
+	})
+	order.Status = to
+	return nil
+}
+
+// carryStatus moves the calculated order on from the status of the order
+// it was calculated from, keeping the transitions of that order.
+func carryStatus(from entity.Order, calculated *entity.Order, at time.Time) error {
+	to := calculated.Status
+	calculated.Status, calculated.StatusTransitions = from.Status, from.StatusTransitions
+	return transition(calculated, to, at)
+}
+
+// startStatus moves a newly calculated order from pending
+// to the status its tax resolved to, which pending can always move to.
+func startStatus(order *entity.Order, at time.Time) {
+	resolved := order.Status
+	order.Status, order.StatusTransitions = entity.OrderStatusPending, nil
+	_ = transition(order, resolved, at)
+}
@@ -0,0 +1,96 @@
+package order
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+)
+
+func TestTransition(t *testing.T) {
+	at := time.Date(2026, 5, 7, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		from    entity.OrderStatus
+		to      entity.OrderStatus
+		wantErr error
+	}{
+		{name: "pending to completed", from: entity.OrderStatusPending, to: entity.OrderStatusCompleted},
+		{name: "pending to failed", from: entity.OrderStatusPending, to: entity.OrderStatusFailedTaxResolution},
+		{name: "failed to completed", from: entity.OrderStatusFailedTaxResolution, to: entity.OrderStatusCompleted},
+		{name: "completed to out of scope", from: entity.OrderStatusCompleted, to: entity.OrderStatusOutOfScope},
+		{name: "completed to refunded", from: entity.OrderStatusCompleted, to: entity.OrderStatusRefunded},
+		{name: "partially refunded to refunded", from: entity.OrderStatusPartiallyRefunded, to: entity.OrderStatusRefunded},
+		{name: "same status", from: entity.OrderStatusPartiallyRefunded, to: entity.OrderStatusPartiallyRefunded},
+		{name: "pending to refunded", from: entity.OrderStatusPending, to: entity.OrderStatusRefunded, wantErr: entity.ErrInvalidStatusTransition},
+		{name: "out of scope to refunded", from: entity.OrderStatusOutOfScope, to: entity.OrderStatusRefunded, wantErr: entity.ErrInvalidStatusTransition},
+		{name: "failed to refunded", from: entity.OrderStatusFailedTaxResolution, to: entity.OrderStatusPartiallyRefunded, wantErr: entity.ErrInvalidStatusTransition},
+		{name: "partially refunded to voided", from: entity.OrderStatusPartiallyRefunded, to: entity.OrderStatusVoided, wantErr: entity.ErrInvalidStatusTransition},
+		{name: "voided to completed", from: entity.OrderStatusVoided, to: entity.OrderStatusCompleted, wantErr: entity.ErrInvalidStatusTransition},
+		{name: "refunded to partially refunded", from: entity.OrderStatusRefunded, to: entity.OrderStatusPartiallyRefunded, wantErr: entity.ErrInvalidStatusTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			earlier := []entity.StatusTransition{{From: entity.OrderStatusPending, To: tt.from, At: at.Add(-time.Hour)}}
+			order := entity.Order{Status: tt.from, StatusTransitions: earlier}
+
+			err := transition(&order, tt.to, at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("transition() error = %v, want %v", err, tt.wantErr)
+			}
+
+			want, wantTransitions := tt.to, 2
+			if tt.wantErr != nil {
+				want = tt.from
+			}
+			if tt.wantErr != nil || tt.from == tt.to {
+				wantTransitions = 1
+			}
+			if order.Status != want || len(order.StatusTransitions) != wantTransitions {
+				t.Fatalf("got status %s with transitions %+v", order.Status, order.StatusTransitions)
+			}
+			if wantTransitions == 2 && order.StatusTransitions[1] != (entity.StatusTransition{From: tt.from, To: tt.to, At: at}) {
+				t.Errorf("unexpected transition %+v", order.StatusTransitions[1])
+			}
+		})
+	}
+}
+
+func TestStartStatus(t *testing.T) {
+	at := time.Date(2026, 5, 7, 9, 0, 0, 0, time.UTC)
+	order := entity.Order{Status: entity.OrderStatusOutOfScope}
+
+	startStatus(&order, at)
+	want := entity.StatusTransition{From: entity.OrderStatusPending, To: entity.OrderStatusOutOfScope, At: at}
+	if order.Status != entity.OrderStatusOutOfScope || len(order.StatusTransitions) != 1 || order.StatusTransitions[0] != want {
+		t.Errorf("unexpected order status %s with transitions %+v", order.Status, order.StatusTransitions)
+	}
+}
+
+func TestCarryStatus(t *testing.T) {
+	at := time.Date(2026, 5, 7, 9, 0, 0, 0, time.UTC)
+	stored := entity.Order{
+		Status:            entity.OrderStatusFailedTaxResolution,
+		StatusTransitions: []entity.StatusTransition{{From: entity.OrderStatusPending, To: entity.OrderStatusFailedTaxResolution}},
+	}
+
+	calculated := entity.Order{Status: entity.OrderStatusCompleted}
+	if err := carryStatus(stored, &calculated, at); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calculated.StatusTransitions) != 2 || calculated.StatusTransitions[1].From != entity.OrderStatusFailedTaxResolution {
+		t.Errorf("unexpected transitions %+v", calculated.StatusTransitions)
+	}
+	if len(stored.StatusTransitions) != 1 {
+		t.Errorf("expected the stored transitions to be kept, got %+v", stored.StatusTransitions)
+	}
+
+	stored.Status = entity.OrderStatusVoided
+	calculated = entity.Order{Status: entity.OrderStatusCompleted}
+	if err := carryStatus(stored, &calculated, at); !errors.Is(err, entity.ErrInvalidStatusTransition) {
+		t.Fatalf("expected ErrInvalidStatusTransition, got %v", err)
+	}
+}
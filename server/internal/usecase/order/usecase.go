@@ -2,16 +2,24 @@ package order
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
 	"github.com/ryl1k/INT20H-test-task-server/internal/repo"
 	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
 
+	"github.com/goccy/go-json"
 	"github.com/rs/zerolog"
 )
 
@@ -23,8 +31,15 @@ type UseCase struct {
 	// such as asynchronous batch processing.
 	outerCtx context.Context
 
-	taxRepo   repo.TaxRepo
-	orderRepo repo.OrderRepo
+	taxRepo       repo.TaxRepo
+	orderRepo     repo.OrderRepo
+	exemptionRepo repo.ExemptionRepo
+	customerRepo  repo.CustomerRepo
+	geocodeRepo   repo.GeocodeRepo
+	eventRepo     repo.OrderEventRepo
+
+	recalculationRepo repo.TaxRecalculationRepo
+	exchangeRepo      repo.ActiveExchangeRateRepo
 
 	// processingTimeout defines the maximum duration allowed
 	// for asynchronous batch processing.
@@ -33,15 +48,36 @@ type UseCase struct {
 	// ordersBatchSize defines how many orders are accumulated
 	// before performing a batch insert into storage.
 	ordersBatchSize int
-	logger          zerolog.Logger
+
+	// deletedRetention is how long deleted orders can be restored
+	// before they are purged.
+	deletedRetention time.Duration
+
+	// ratesVersion identifies the loaded tax data tax recalculations are
+	// computed with, apart from the boundaries and overrides changing
+	// at runtime.
+	ratesVersion string
+
+	logger zerolog.Logger
 }
 
+// wipeConfirmationTTL is how long a wipe confirmation token stays valid.
+const wipeConfirmationTTL = 5 * time.Minute
+
 func New(
 	outerCtx context.Context,
 	taxRepo repo.TaxRepo,
 	orderRepo repo.OrderRepo,
+	exemptionRepo repo.ExemptionRepo,
+	customerRepo repo.CustomerRepo,
+	geocodeRepo repo.GeocodeRepo,
+	eventRepo repo.OrderEventRepo,
+	recalculationRepo repo.TaxRecalculationRepo,
+	exchangeRepo repo.ActiveExchangeRateRepo,
 	processingTimeout time.Duration,
 	ordersBatchSize int,
+	deletedRetention time.Duration,
+	ratesVersion string,
 	logger zerolog.Logger,
 ) *UseCase {
 	l := logger.With().Str("usecase", "order").Logger()
@@ -49,19 +85,33 @@ func New(
 		logger:            l,
 		outerCtx:          outerCtx,
 		orderRepo:         orderRepo,
+		exemptionRepo:     exemptionRepo,
+		customerRepo:      customerRepo,
+		geocodeRepo:       geocodeRepo,
+		eventRepo:         eventRepo,
+		recalculationRepo: recalculationRepo,
+		exchangeRepo:      exchangeRepo,
 		taxRepo:           taxRepo,
 		ordersBatchSize:   ordersBatchSize,
 		processingTimeout: processingTimeout,
+		deletedRetention:  deletedRetention,
+		ratesVersion:      ratesVersion,
 	}
 }
 
 // AsyncBatchCreate processes orders from a CSV reader asynchronously.
-// It reads records one by one, maps them to domain entities,
-// calculates taxes based on coordinates, and inserts them in batches.
+// It reads records one by one, maps them to domain entities and queues
+// them in batches. Every batch is inserted as pending first, then the taxes
+// of its orders are calculated based on coordinates and customer exemption
+// certificates and the orders are stored with their resolved status.
 // Processing stops when the timeout is reached or EOF occurs.
 // Invalid rows are skipped and logged.
+// The import options apply to every row, and every stored order
+// is recorded in its history as imported and then resolved by the import job.
+// Orders whose tax could not be resolved stay pending until they are
+// resolved again through ResolveOutOfScope.
 // Remaining buffered orders are flushed before completion.
-func (uc *UseCase) AsyncBatchCreate(reader *csv.Reader, closer io.Closer) {
+func (uc *UseCase) AsyncBatchCreate(reader *csv.Reader, closer io.Closer, options dto.OrderImport) {
 	defer closer.Close()
 
 	now := time.Now()
@@ -76,6 +126,12 @@ func (uc *UseCase) AsyncBatchCreate(reader *csv.Reader, closer io.Closer) {
 	failedCount := 0
 	timedOut := false
 
+	// customers and certificates cache registered customers and exemption
+	// certificates per customer reference so that every customer
+	// is looked up only once per import.
+	customers := make(map[string]*entity.Customer)
+	certificates := make(map[string][]entity.ExemptionCertificate)
+
 loop:
 	for {
 		select {
@@ -99,22 +155,34 @@ loop:
 				failedCount++
 				continue
 			}
+			parsedOrder.TaxInclusive = options.TaxInclusive
 
-			tax, ok := uc.taxRepo.GetTaxByLocation(ctx, parsedOrder.Latitude, parsedOrder.Longitude)
-
-			var order entity.Order
+			customer, ok := customers[parsedOrder.CustomerRef]
 			if !ok {
-				order = uc.buildOutOfScopeOrder(parsedOrder)
-			} else {
-				order = uc.buildCompletedOrder(parsedOrder, *tax)
+				customer, err = uc.getCustomer(ctx, parsedOrder)
+				if err != nil {
+					l.Error().Err(err).Str("customer_ref", parsedOrder.CustomerRef).Msg("skipping row, failed to get customer")
+					failedCount++
+					continue
+				}
+				customers[parsedOrder.CustomerRef] = customer
+			}
+			parsedOrder = linkCustomer(parsedOrder, customer)
+
+			order, err := uc.pendingOrder(ctx, parsedOrder)
+			if err != nil {
+				l.Warn().Err(err).Interface("record", rec).Msg("skipping row, failed to build order")
+				failedCount++
+				continue
 			}
+			order.ImportId = options.Id
 
 			orders = append(orders, order)
 			processedCount++
 
 			if len(orders) >= uc.ordersBatchSize {
-				if err := uc.orderRepo.BatchCreate(ctx, orders); err != nil {
-					l.Error().Err(err).Int("batch_size", len(orders)).Msg("failed to create batch order")
+				if err := uc.importBatch(ctx, orders, options, certificates); err != nil {
+					l.Error().Err(err).Int("batch_size", len(orders)).Msg("failed to import batch order")
 				}
 
 				orders = orders[:0]
@@ -130,8 +198,8 @@ loop:
 	}
 
 	if len(orders) > 0 {
-		if err := uc.orderRepo.BatchCreate(flushCtx, orders); err != nil {
-			l.Error().Err(err).Int("remaining_size", len(orders)).Msg("failed to create remaining batch order")
+		if err := uc.importBatch(flushCtx, orders, options, certificates); err != nil {
+			l.Error().Err(err).Int("remaining_size", len(orders)).Msg("failed to import remaining batch order")
 		}
 	}
 
@@ -145,31 +213,177 @@ loop:
 	}
 
 	l.Info().
+		Str("import_id", options.Id).
 		Int("total_processed", processedCount).
 		Int("total_failed", failedCount).
 		Dur("duration", time.Since(now)).
 		Msg("async batch processing finished")
 }
 
+// importBatch inserts the orders of an import as pending in one transaction,
+// then resolves the tax of every order, looking up the exemption certificates
+// of every customer only once per import, and stores them with their status
+// in a second one. Orders whose tax fails to resolve are stored as
+// failed_tax_resolution. If storing them fails, they stay pending until
+// the out-of-scope orders are resolved again.
+func (uc *UseCase) importBatch(ctx context.Context, orders []entity.Order, options dto.OrderImport, certificates map[string][]entity.ExemptionCertificate) error {
+	l := uc.logger.With().Str("method", "import_batch").Str("import_id", options.Id).Logger()
+
+	if err := uc.orderRepo.BatchCreate(ctx, orders, importedEvent(options)); err != nil {
+		return fmt.Errorf("failed to create batch order: %w", err)
+	}
+
+	resolved := make([]entity.Order, len(orders))
+	events := make([]entity.OrderEvent, len(orders))
+	for i := range orders {
+		order, err := uc.recalculate(ctx, orders[i], certificates)
+		if err != nil {
+			l.Warn().Err(err).Int("order_id", orders[i].Id).Msg("failed to resolve imported order")
+
+			order = orders[i]
+			order.UpdatedAt = time.Now()
+			if err := transition(&order, entity.OrderStatusFailedTaxResolution, order.UpdatedAt); err != nil {
+				return fmt.Errorf("failed to mark order %d: %w", orders[i].Id, err)
+			}
+		}
+
+		stored := order
+		stored.Version++
+		resolved[i], events[i] = order, resolvedEvent(options, &orders[i], &stored)
+	}
+
+	if err := uc.orderRepo.BatchUpdate(ctx, resolved, events); err != nil {
+		return fmt.Errorf("failed to update batch order: %w", err)
+	}
+	return nil
+}
+
 // Create handles single order creation.
-// It retrieves tax information by coordinates,
+// It links the order to the registered customer, if any, whose default
+// location is used for orders without one, retrieves tax information
+// by coordinates and exemption certificates of the referenced customer,
 // builds either a completed or out-of-scope order,
-// persists it, and returns the resulting entity.
+// persists it, records it in the order history and returns the resulting entity.
 func (uc *UseCase) Create(ctx context.Context, orderDto dto.Order) (entity.Order, error) {
+	customer, err := uc.getCustomer(ctx, orderDto)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("failed to get customer: %w", err)
+	}
+	orderDto = linkCustomer(orderDto, customer)
 
-	tax, ok := uc.taxRepo.GetTaxByLocation(ctx, orderDto.Latitude, orderDto.Longitude)
-	var order entity.Order
-	if !ok {
-		order = uc.buildOutOfScopeOrder(orderDto)
-	} else {
-		order = uc.buildCompletedOrder(orderDto, *tax)
+	certs, err := uc.getCertificates(ctx, orderDto.CustomerRef)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("failed to get exemption certificates: %w", err)
 	}
 
-	id, err := uc.orderRepo.Create(ctx, order)
+	order, err := uc.calculateConverted(ctx, orderDto, certs)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	startStatus(&order, time.Now())
+	order.Version = 1
+
+	id, err := uc.orderRepo.Create(ctx, order, newEvent(ctx, entity.OrderEventCreated, nil, &order))
 	if err != nil {
 		return entity.Order{}, fmt.Errorf("failed to create order: %w", err)
 	}
 	order.Id = id
+
+	return order, nil
+}
+
+// Update corrects the coordinates, subtotal or timestamp of the order
+// and resolves its tax again from the corrected order, which keeps
+// its other fields, items and explanation request.
+// A version other than zero must match the version of the order.
+// Orders with items take their subtotal from the items and
+// cannot be given one, and final orders cannot be updated.
+func (uc *UseCase) Update(ctx context.Context, id, version int, update dto.OrderUpdate) (entity.Order, error) {
+	order, err := uc.getEditable(ctx, id, version)
+	if err != nil {
+		return entity.Order{}, err
+	}
+
+	p := newOrderRequest(order)
+	if update.Latitude != nil || update.Longitude != nil {
+		p.Latitude, p.Longitude = order.Latitude, order.Longitude
+		if update.Latitude != nil {
+			p.Latitude = *update.Latitude
+		}
+		if update.Longitude != nil {
+			p.Longitude = *update.Longitude
+		}
+	}
+	if update.Subtotal != nil {
+		if len(p.Items) > 0 {
+			return entity.Order{}, entity.ErrBadRequest
+		}
+		p.Subtotal = *update.Subtotal
+	}
+	if update.Timestamp != nil {
+		p.Timestamp = *update.Timestamp
+	}
+
+	certs, err := uc.getCertificates(ctx, p.CustomerRef)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("failed to get exemption certificates: %w", err)
+	}
+
+	updated, err := uc.calculateConverted(ctx, p, certs)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	updated.Id, updated.Version = order.Id, order.Version
+	updated.UpdatedAt = time.Now()
+	if err := carryStatus(order, &updated, updated.UpdatedAt); err != nil {
+		return entity.Order{}, err
+	}
+
+	stored := updated
+	stored.Version++
+	if err := uc.orderRepo.Update(ctx, updated, newEvent(ctx, entity.OrderEventUpdated, &order, &stored)); err != nil {
+		return entity.Order{}, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	return stored, nil
+}
+
+// Void voids the order, which then no longer counts as a sale.
+// A version other than zero must match the version of the order,
+// and final orders cannot be voided.
+func (uc *UseCase) Void(ctx context.Context, id, version int) (entity.Order, error) {
+	order, err := uc.getEditable(ctx, id, version)
+	if err != nil {
+		return entity.Order{}, err
+	}
+
+	voided := order
+	voided.UpdatedAt = time.Now()
+	if err := transition(&voided, entity.OrderStatusVoided, voided.UpdatedAt); err != nil {
+		return entity.Order{}, err
+	}
+	stored := voided
+	stored.Version++
+	if err := uc.orderRepo.UpdateStatus(ctx, voided, newEvent(ctx, entity.OrderEventVoided, &order, &stored)); err != nil {
+		return entity.Order{}, fmt.Errorf("failed to void order: %w", err)
+	}
+
+	return stored, nil
+}
+
+// getEditable returns the order when it can still be changed
+// and, unless the version is zero, is at the given version.
+func (uc *UseCase) getEditable(ctx context.Context, id, version int) (entity.Order, error) {
+	order, err := uc.orderRepo.GetById(ctx, id)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	if version != 0 && order.Version != version {
+		return entity.Order{}, entity.ErrOrderVersionConflict
+	}
+	if !order.Editable() {
+		return entity.Order{}, entity.ErrOrderNotEditable
+	}
 	return order, nil
 }
 
@@ -185,20 +399,513 @@ func (uc *UseCase) GetAll(ctx context.Context, filter dto.OrderFilters) (entity.
 	return uc.orderRepo.GetAll(ctx, filter)
 }
 
-// DeleteAll removes all orders from storage.
-// Intended primarily for administrative or testing purposes.
-func (uc *UseCase) DeleteAll(ctx context.Context) error {
-	return uc.orderRepo.DeleteAll(ctx)
+// Delete soft-deletes the orders matching the filter, which must target
+// given orders, an import job or a customer, and records the deletion in
+// their history. Deleting all orders is left to the admin wipe.
+// Deleted orders can be restored until the retention period ends.
+func (uc *UseCase) Delete(ctx context.Context, filter dto.OrderFilters) (entity.OrderDeletion, error) {
+	if !filter.Targeted() {
+		return entity.OrderDeletion{}, entity.ErrDeletionScopeRequired
+	}
+
+	now := time.Now()
+	ids, err := uc.orderRepo.SoftDelete(ctx, filter, now, newBulkEvent(ctx, entity.OrderEventDeleted))
+	if err != nil {
+		return entity.OrderDeletion{}, fmt.Errorf("failed to delete orders: %w", err)
+	}
+
+	return entity.OrderDeletion{Deleted: len(ids), PurgeAt: now.Add(uc.deletedRetention)}, nil
+}
+
+// Restore brings back the deleted orders matching the filter, which must
+// target orders like for deletion, that were not purged yet and records
+// it in their history.
+func (uc *UseCase) Restore(ctx context.Context, filter dto.OrderFilters) (entity.OrderRestoration, error) {
+	if !filter.Targeted() {
+		return entity.OrderRestoration{}, entity.ErrDeletionScopeRequired
+	}
+
+	ids, err := uc.orderRepo.Restore(ctx, filter, newBulkEvent(ctx, entity.OrderEventRestored))
+	if err != nil {
+		return entity.OrderRestoration{}, fmt.Errorf("failed to restore orders: %w", err)
+	}
+
+	return entity.OrderRestoration{Restored: len(ids)}, nil
+}
+
+// PurgeDeleted removes for good the orders deleted
+// longer than the retention period ago.
+func (uc *UseCase) PurgeDeleted(ctx context.Context) (int, error) {
+	return uc.orderRepo.PurgeDeleted(ctx, time.Now().Add(-uc.deletedRetention))
+}
+
+// RunPurge purges deleted orders right away and then
+// at every interval until the context is done.
+func (uc *UseCase) RunPurge(ctx context.Context, interval time.Duration) {
+	l := uc.logger.With().Str("method", "run_purge").Logger()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := uc.PurgeDeleted(ctx)
+		if err != nil {
+			l.Error().Err(err).Msg("failed to purge deleted orders")
+		} else if purged > 0 {
+			l.Info().Int("purged", purged).Msg("purged deleted orders")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RequestWipe issues the token that confirms a wipe of all orders.
+// Only the latest token is valid, once and for a short time. The token is
+// stored hashed, so that it confirms the wipe on any instance.
+func (uc *UseCase) RequestWipe(ctx context.Context) (entity.WipeConfirmation, error) {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	confirmation := entity.WipeConfirmation{
+		Token:     hex.EncodeToString(b),
+		ExpiresAt: time.Now().Add(wipeConfirmationTTL),
+	}
+	if err := uc.orderRepo.CreateWipeConfirmation(ctx, wipeTokenHash(confirmation.Token), confirmation.ExpiresAt); err != nil {
+		return entity.WipeConfirmation{}, fmt.Errorf("failed to create wipe confirmation: %w", err)
+	}
+	return confirmation, nil
+}
+
+// DeleteAll removes all orders from storage for good, including deleted
+// ones, once confirmed by the token issued by RequestWipe.
+// The history of the orders is kept, and new orders never reuse their ids.
+func (uc *UseCase) DeleteAll(ctx context.Context, token string) error {
+	if token == "" {
+		return entity.ErrInvalidConfirmationToken
+	}
+
+	if err := uc.orderRepo.DeleteAll(ctx, wipeTokenHash(token), time.Now()); err != nil {
+		return err
+	}
+
+	uc.logger.Warn().
+		Str("actor_key", entity.ActorFromContext(ctx).Key).
+		Str("actor_user", entity.ActorFromContext(ctx).User).
+		Msg("wiped all orders")
+	return nil
+}
+
+// wipeTokenHash returns the hex-encoded sha256 of a wipe confirmation token.
+func wipeTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Refund refunds the given lines and the pre-tax amount, or everything left
+// when neither is given, from the order and reverses the tax of every
+// component and item line it is allocated to at the rate of the line.
+// The order becomes partially refunded, or refunded once nothing is left.
+func (uc *UseCase) Refund(ctx context.Context, id int, refundDto dto.OrderRefund) (entity.OrderRefund, error) {
+	order, err := uc.orderRepo.GetById(ctx, id)
+	if err != nil {
+		return entity.OrderRefund{}, err
+	}
+
+	lines := make([]entity.OrderRefundLine, len(refundDto.Lines))
+	for i, line := range refundDto.Lines {
+		lines[i] = entity.OrderRefundLine{Component: entity.OrderComponent(line.Component), Line: line.Line, Amount: line.Amount}
+	}
+
+	refund, status, err := order.Refund(refundDto.Amount, lines)
+	if err != nil {
+		return entity.OrderRefund{}, err
+	}
+	refund.Reason = refundDto.Reason
+	refund.CreatedAt = time.Now()
+
+	refunded := order
+	if err := transition(&refunded, status, refund.CreatedAt); err != nil {
+		return entity.OrderRefund{}, err
+	}
+
+	refunded = refunded.WithRefund(refund, status)
+	refund.Id, err = uc.orderRepo.CreateRefund(ctx, refund, order.Version, status, newEvent(ctx, entity.OrderEventRefunded, &order, &refunded))
+	if err != nil {
+		return entity.OrderRefund{}, fmt.Errorf("failed to create refund: %w", err)
+	}
+
+	return refund, nil
+}
+
+// History returns the changes of the order from the oldest.
+// The history outlives the order, and only an order
+// without any history is reported as not found.
+func (uc *UseCase) History(ctx context.Context, id int) ([]entity.OrderEvent, error) {
+	events, err := uc.eventRepo.GetByOrderId(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order events: %w", err)
+	}
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	if _, err := uc.orderRepo.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// newBulkEvent returns the event of a change of every order matching
+// a filter made through the API by the actor of the request.
+// The order repository records it for every changed order.
+// Such changes do not change the orders and keep no snapshots.
+func newBulkEvent(ctx context.Context, eventType entity.OrderEventType) entity.OrderEvent {
+	return entity.OrderEvent{
+		Type:      eventType,
+		Actor:     entity.ActorFromContext(ctx),
+		Source:    entity.OrderEventSourceApi,
+		CreatedAt: time.Now(),
+	}
+}
+
+// importedEvent returns the event of the orders stored by an import job.
+// The order repository records it for every stored order.
+func importedEvent(options dto.OrderImport) entity.OrderEvent {
+	return entity.OrderEvent{
+		Type:      entity.OrderEventImported,
+		Actor:     options.Actor,
+		Source:    entity.OrderEventSourceImport,
+		SourceId:  options.Id,
+		CreatedAt: time.Now(),
+	}
+}
+
+// resolvedEvent returns the event of a pending order of an import job
+// whose tax was resolved.
+func resolvedEvent(options dto.OrderImport, before, after *entity.Order) entity.OrderEvent {
+	return entity.OrderEvent{
+		OrderId:   after.Id,
+		Type:      entity.OrderEventResolved,
+		Actor:     options.Actor,
+		Source:    entity.OrderEventSourceImport,
+		SourceId:  options.Id,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now(),
+	}
+}
+
+// newEvent returns the event of a change of an order
+// made through the API by the actor of the request.
+func newEvent(ctx context.Context, eventType entity.OrderEventType, before, after *entity.Order) entity.OrderEvent {
+	return entity.OrderEvent{
+		OrderId:   after.Id,
+		Type:      eventType,
+		Actor:     entity.ActorFromContext(ctx),
+		Source:    entity.OrderEventSourceApi,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Explain calculates the tax of the order without storing it
+// and returns how the result was derived.
+func (uc *UseCase) Explain(ctx context.Context, orderDto dto.Order) (entity.TaxExplanation, error) {
+	customer, err := uc.getCustomer(ctx, orderDto)
+	if err != nil {
+		return entity.TaxExplanation{}, fmt.Errorf("failed to get customer: %w", err)
+	}
+	orderDto = linkCustomer(orderDto, customer)
+
+	certs, err := uc.getCertificates(ctx, orderDto.CustomerRef)
+	if err != nil {
+		return entity.TaxExplanation{}, fmt.Errorf("failed to get exemption certificates: %w", err)
+	}
+
+	orderDto.Explain = true
+	order, err := uc.calculateConverted(ctx, orderDto, certs)
+	if err != nil {
+		return entity.TaxExplanation{}, err
+	}
+
+	return *order.Explain, nil
+}
+
+// getCustomer returns the registered customer the order is placed by,
+// given by id or by reference. Orders without a customer, or referencing
+// one that is not registered, return nil. A customer id that does not
+// match the reference given along with it is rejected.
+func (uc *UseCase) getCustomer(ctx context.Context, p dto.Order) (*entity.Customer, error) {
+	var (
+		customer entity.Customer
+		err      error
+	)
+	switch {
+	case p.CustomerId != nil:
+		customer, err = uc.customerRepo.GetById(ctx, *p.CustomerId)
+		if err != nil {
+			return nil, err
+		}
+		if p.CustomerRef != "" && p.CustomerRef != customer.ExternalRef {
+			return nil, entity.ErrBadRequest
+		}
+	case p.CustomerRef != "":
+		customer, err = uc.customerRepo.GetByExternalRef(ctx, p.CustomerRef)
+		if errors.Is(err, entity.ErrCustomerNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+	return &customer, nil
+}
+
+// linkCustomer links the order to the customer and places it
+// at the default location of the customer when it has no location
+// of its own. A nil customer leaves the order unchanged.
+func linkCustomer(p dto.Order, customer *entity.Customer) dto.Order {
+	if customer == nil {
+		return p
+	}
+
+	p.CustomerId, p.CustomerRef = &customer.Id, customer.ExternalRef
+	if p.Latitude != 0 || p.Longitude != 0 || p.Address != "" || p.Zip != "" {
+		return p
+	}
+
+	if customer.Latitude != nil && customer.Longitude != nil {
+		p.Latitude, p.Longitude = *customer.Latitude, *customer.Longitude
+	}
+	p.Address, p.Zip = customer.Address, customer.Zip
+	return p
+}
+
+// getCertificates returns exemption certificates of the customer.
+// Orders without a customer reference have no certificates.
+func (uc *UseCase) getCertificates(ctx context.Context, customerRef string) ([]entity.ExemptionCertificate, error) {
+	if customerRef == "" {
+		return nil, nil
+	}
+	return uc.exemptionRepo.GetByCustomerRef(ctx, customerRef)
+}
+
+// calculate geocodes the order when it has no coordinates, resolves tax
+// information in the configured states by coordinates
+// or by the jurisdiction the ZIP code maps to
+// and the tax override active for the jurisdiction, category and order time,
+// and builds either a completed or out-of-scope order.
+// Orders whose location matched several jurisdictions carry a warning,
+// including those left out of scope by the ambiguity policy.
+// Orders with items get their subtotal from the items, each of which
+// is taxed by the override and taxability rule of its own category.
+// When the order requests an explanation, it is attached to the order.
+// Metadata and tags are carried over as given.
+func (uc *UseCase) calculate(ctx context.Context, p dto.Order, certs []entity.ExemptionCertificate) entity.Order {
+	items, subtotal := newOrderItems(p.Items)
+	if items != nil {
+		p.Subtotal = subtotal
+	}
+
+	geocode := uc.geocode(ctx, p)
+	p.Latitude, p.Longitude = geocode.Latitude, geocode.Longitude
+
+	var (
+		tax         *entity.LocationTax
+		ok          bool
+		explanation *entity.TaxExplanation
+	)
+	switch {
+	case geocode.Method == entity.GeocodingMethodUnresolved:
+	case geocode.Method == entity.GeocodingMethodZipJurisdiction:
+		tax, ok = uc.taxRepo.GetTaxByJurisdiction(ctx, geocode.State, geocode.Jurisdiction, geocode.Latitude, geocode.Longitude)
+	case p.Explain:
+		var e entity.TaxExplanation
+		tax, e, ok = uc.taxRepo.ExplainTaxByLocation(ctx, p.Latitude, p.Longitude)
+		explanation = &e
+	default:
+		tax, ok = uc.taxRepo.GetTaxByLocation(ctx, p.Latitude, p.Longitude)
+	}
+
+	if p.Explain {
+		if explanation == nil {
+			explanation = newJurisdictionExplanation(tax, ok)
+		}
+		explanation.Latitude, explanation.Longitude = p.Latitude, p.Longitude
+		explanation.Subtotal = p.Subtotal
+		explanation.Geocode = &geocode
+	}
+
+	var order entity.Order
+	if ok {
+		overrides := uc.getOverrides(ctx, tax.State, tax.Code, p)
+		certificate := uc.getCertificate(ctx, certs, tax.State, tax.Code, p.Timestamp)
+		order = uc.buildCompletedOrder(p, items, *tax, overrides, certificate, explanation)
+	} else {
+		order = uc.buildOutOfScopeOrder(p, items)
+		order.Explain = explanation
+		// a location matching no jurisdiction, or an ambiguous one under the
+		// fail policy, is out of scope, while an unresolved zip or a matched
+		// jurisdiction without a tax is a failed lookup
+		if geocode.Method == entity.GeocodingMethodUnresolved ||
+			geocode.Method == entity.GeocodingMethodZipJurisdiction || tax != nil && !tax.Ambiguous {
+			order.Status = entity.OrderStatusFailedTaxResolution
+		}
+		if tax != nil && tax.Ambiguous {
+			order.Warning = entity.OrderWarningAmbiguousJurisdiction
+			order.AmbiguousJurisdictions = tax.Matches
+		}
+	}
+
+	order.NetTotalAmount, order.NetTaxAmount = order.TotalAmount, order.TaxAmount
+	order.Zip = geocode.Zip
+	order.GeocodingMethod = geocode.Method
+	order.GeocodingPrecision = geocode.Precision
+	order.Metadata, order.Tags = newOrderLabels(p.Metadata, p.Tags)
+	order.CustomerId = p.CustomerId
+	return order
+}
+
+// calculateConverted calculates the order in USD. The amounts of orders
+// placed in another currency, including item prices and discounts, are
+// converted at the rate of the order date and rounded to cents first and
+// kept as given on the order, together with the total and tax converted
+// back at the same rate.
+func (uc *UseCase) calculateConverted(ctx context.Context, p dto.Order, certs []entity.ExemptionCertificate) (entity.Order, error) {
+	return uc.convert(ctx, p, func(p dto.Order) entity.Order {
+		return uc.calculate(ctx, p, certs)
+	})
+}
+
+// pendingOrder builds the order in USD like calculateConverted,
+// without resolving its tax.
+func (uc *UseCase) pendingOrder(ctx context.Context, p dto.Order) (entity.Order, error) {
+	return uc.convert(ctx, p, uc.buildPendingOrder)
+}
+
+// convert converts the amounts of the order to USD as described in
+// calculateConverted and builds the order from them.
+func (uc *UseCase) convert(ctx context.Context, p dto.Order, build func(dto.Order) entity.Order) (entity.Order, error) {
+	currency, ok := entity.NormalizeCurrency(p.Currency)
+	if !ok {
+		return entity.Order{}, entity.ErrBadRequest
+	}
+
+	if currency == entity.ReportingCurrency {
+		order := build(p)
+		order.Currency, order.ExchangeRate = currency, 1
+		return order, nil
+	}
+
+	rate, ok := uc.exchangeRepo.GetRate(ctx, currency, p.Timestamp)
+	if !ok {
+		return entity.Order{}, entity.ErrExchangeRateNotFound
+	}
+
+	original := entity.OrderAmounts{
+		Subtotal: p.Subtotal,
+		Shipping: p.Shipping,
+		Handling: p.Handling,
+		Discount: p.Discount,
+	}
+	if _, subtotal := newOrderItems(p.Items); len(p.Items) > 0 {
+		original.Subtotal = subtotal
+	}
+
+	p.Subtotal = rate.ToReporting(p.Subtotal)
+	p.Shipping = rate.ToReporting(p.Shipping)
+	p.Handling = rate.ToReporting(p.Handling)
+	p.Discount = rate.ToReporting(p.Discount)
+
+	items := make([]dto.OrderItem, len(p.Items))
+	for i, item := range p.Items {
+		item.UnitPrice = rate.ToReporting(item.UnitPrice)
+		item.Discount = rate.ToReporting(item.Discount)
+		items[i] = item
+	}
+	p.Items = items
+
+	order := build(p)
+	original.TotalAmount = rate.FromReporting(order.TotalAmount)
+	original.TaxAmount = rate.FromReporting(order.TaxAmount)
+
+	order.Currency, order.ExchangeRate = currency, rate.Rate
+	order.Original = &original
+	return order, nil
+}
+
+// geocode resolves the location of the order. Orders with coordinates,
+// or without any address, are located by their coordinates. Others are
+// geocoded by the ZIP code given directly or found in the address line.
+func (uc *UseCase) geocode(ctx context.Context, p dto.Order) entity.Geocode {
+	zip := p.Zip
+	if zip == "" {
+		zip, _ = entity.ExtractZip(p.Address)
+	}
+
+	if p.Latitude != 0 || p.Longitude != 0 || zip == "" {
+		return entity.Geocode{
+			Method:    entity.GeocodingMethodCoordinates,
+			Precision: entity.GeocodingPrecisionExact,
+			Zip:       zip,
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+		}
+	}
+
+	geocode, ok := uc.geocodeRepo.GeocodeZip(ctx, zip)
+	if !ok {
+		return entity.Geocode{
+			Method:    entity.GeocodingMethodUnresolved,
+			Precision: entity.GeocodingPrecisionNone,
+			Zip:       zip,
+		}
+	}
+	return geocode
+}
+
+// newJurisdictionExplanation explains a tax resolved without a location
+// lookup, i.e. directly from the jurisdiction or not at all.
+func newJurisdictionExplanation(tax *entity.LocationTax, ok bool) *entity.TaxExplanation {
+	explanation := &entity.TaxExplanation{
+		Candidates: []entity.FeatureMatch{},
+		Layers:     []entity.LayerMatch{},
+		Steps:      []entity.ComputationStep{},
+	}
+	if ok {
+		jurisdiction := tax.JurisdictionTax
+		explanation.Jurisdiction = &jurisdiction
+		explanation.AddStep(entity.ComputationStepJurisdiction, tax.Code, tax.Breakdown, tax.CompositeRate)
+	}
+	return explanation
 }
 
 // buildOutOfScopeOrder constructs an order entity
-// when no tax jurisdiction matches the provided coordinates.
+// when no jurisdiction of any configured state matches the provided coordinates.
 // Such orders are marked as OutOfScope and contain no tax data.
-func (uc *UseCase) buildOutOfScopeOrder(p dto.Order) entity.Order {
+func (uc *UseCase) buildOutOfScopeOrder(p dto.Order, items []entity.OrderItem) entity.Order {
+	discount := min(p.Discount, p.Subtotal)
+
 	return entity.Order{
 		Latitude:      p.Latitude,
 		Longitude:     p.Longitude,
-		TotalAmount:   p.Subtotal,
+		Subtotal:      p.Subtotal,
+		Shipping:      p.Shipping,
+		Handling:      p.Handling,
+		Discount:      discount,
+		TotalAmount:   p.Subtotal + p.Shipping + p.Handling - discount,
+		Components:    entity.OrderComponents(p.Subtotal, p.Shipping, p.Handling, discount),
+		Items:         items,
+		TaxInclusive:  p.TaxInclusive,
+		Category:      p.Category,
+		CustomerRef:   p.CustomerRef,
 		Status:        entity.OrderStatusOutOfScope,
 		Jurisdictions: []string{},
 		CreatedAt:     p.Timestamp,
@@ -206,47 +913,383 @@ func (uc *UseCase) buildOutOfScopeOrder(p dto.Order) entity.Order {
 	}
 }
 
+// buildPendingOrder constructs an order entity whose tax is not resolved
+// yet, e.g. one queued by an import. Its location is not geocoded yet either,
+// so that resolving its tax later geocodes it like a new order.
+func (uc *UseCase) buildPendingOrder(p dto.Order) entity.Order {
+	items, subtotal := newOrderItems(p.Items)
+	if items != nil {
+		p.Subtotal = subtotal
+	}
+
+	order := uc.buildOutOfScopeOrder(p, items)
+	order.Status = entity.OrderStatusPending
+	order.Version = 1
+	order.NetTotalAmount = order.TotalAmount
+	order.Zip = p.Zip
+	if order.Zip == "" {
+		order.Zip, _ = entity.ExtractZip(p.Address)
+	}
+	order.Metadata, order.Tags = newOrderLabels(p.Metadata, p.Tags)
+	order.CustomerId = p.CustomerId
+	return order
+}
+
 // buildCompletedOrder constructs a fully calculated order entity
-// when tax information is available.
-// It computes tax amount using the composite tax rate
-// and fills detailed tax breakdown and reporting metadata.
-func (uc *UseCase) buildCompletedOrder(p dto.Order, tax entity.JurisdictionTax) entity.Order {
+// when tax information is available, taxing every component of the order
+// and recording every step that changes the rates in the explanation, if any.
+func (uc *UseCase) buildCompletedOrder(
+	p dto.Order,
+	items []entity.OrderItem,
+	tax entity.LocationTax,
+	overrides map[string]*entity.TaxOverride,
+	certificate *entity.ExemptionCertificate,
+	explanation *entity.TaxExplanation,
+) entity.Order {
+	rates := tax.Breakdown
+	compositeRate := tax.CompositeRate
+
+	var (
+		override     *entity.TaxOverride
+		overrideName string
+	)
+	// the merchandise of an itemized order is taxed at the rates of its items
+	// weighted by their amounts, otherwise at the rates of the order category
+	if items != nil {
+		rates = taxItems(items, tax, overrides, certificate, p.TaxInclusive)
+		compositeRate = rates.Total()
+		explanation.AddStep(entity.ComputationStepItems, fmt.Sprintf("%d items", len(items)), rates, compositeRate)
+	} else {
+		if override = overrides[p.Category]; override != nil {
+			rates = override.Apply(rates)
+			compositeRate = rates.Total()
+			overrideName = override.Name
+			explanation.AddStep(entity.ComputationStepOverride, override.Name, rates, compositeRate)
+		}
+
+		if rule, ok := tax.Taxability[p.Category]; ok {
+			var net float64
+			rates, net = rule.ApplyCharged(rates, p.Subtotal, p.TaxInclusive)
+			compositeRate = rates.Total()
+			explanation.AddStep(entity.ComputationStepTaxability, fmt.Sprintf("%s: %s", p.Category, rule.TreatmentFor(net)), rates, compositeRate)
+		}
+	}
+
+	discount := min(p.Discount, p.Subtotal)
+	components := entity.OrderComponents(p.Subtotal, p.Shipping, p.Handling, discount)
+
+	var taxAmount float64
+	for i := range components {
+		c := &components[i]
+
+		if c.Component == entity.OrderComponentSubtotal && items != nil {
+			c.ApplyItems(items)
+			taxAmount += c.TaxAmount
+			continue
+		}
+
+		// other components are taxed at the rates of the merchandise narrowed
+		// by their own rule, and tax-inclusive ones get their tax backed out
+		componentRates := rates
+		if rule, ok := tax.ComponentTaxability[c.Component]; ok {
+			componentRates, _ = rule.ApplyCharged(componentRates, math.Abs(c.Amount), p.TaxInclusive)
+		}
+		if certificate != nil {
+			componentRates = certificate.Apply(componentRates)
+		}
+
+		c.Apply(componentRates, p.TaxInclusive)
+		taxAmount += c.TaxAmount
+	}
+
+	var subtotal, shipping, handling float64
+	for _, c := range components {
+		switch c.Component {
+		case entity.OrderComponentSubtotal:
+			subtotal = c.Amount
+		case entity.OrderComponentShipping:
+			shipping = c.Amount
+		case entity.OrderComponentHandling:
+			handling = c.Amount
+		case entity.OrderComponentDiscount:
+			discount = -c.Amount
+		}
+	}
+
+	var certificateNumber string
+	if certificate != nil {
+		rates = certificate.Apply(rates)
+		compositeRate = rates.Total()
+		certificateNumber = certificate.Number
+		explanation.AddStep(entity.ComputationStepExemption, certificate.Number, rates, compositeRate)
+	}
+
+	var warning entity.OrderWarning
+	if tax.Ambiguous {
+		warning = entity.OrderWarningAmbiguousJurisdiction
+	}
+
+	if explanation != nil {
+		explanation.Subtotal = subtotal
+		explanation.Override = override
+		explanation.CompositeRate = compositeRate
+		explanation.TaxAmount = taxAmount
+		explanation.Components = components
+	}
+
 	return entity.Order{
-		Latitude:         p.Latitude,
-		Longitude:        p.Longitude,
-		TotalAmount:      p.Subtotal,
-		TaxAmount:        p.Subtotal * tax.CompositeRate,
-		CompositeTaxRate: tax.CompositeRate,
+		Latitude:     p.Latitude,
+		Longitude:    p.Longitude,
+		Subtotal:     subtotal,
+		Shipping:     shipping,
+		Handling:     handling,
+		Discount:     discount,
+		TotalAmount:  subtotal + shipping + handling - discount + taxAmount,
+		TaxAmount:    taxAmount,
+		TaxInclusive: p.TaxInclusive,
+		Components:   components,
+		Items:        items,
+		Category:     p.Category,
+		CustomerRef:  p.CustomerRef,
+
+		ExemptionCertificate: certificateNumber,
+		TaxOverride:          overrideName,
+		CompositeTaxRate:     compositeRate,
 		Breakdown: entity.TaxRateBreakdown{
-			StateRate:   tax.Breakdown.State,
-			CountyRate:  tax.Breakdown.County,
-			CityRate:    tax.Breakdown.City,
-			SpecialRate: tax.Breakdown.Special,
+			StateRate:   rates.State,
+			CountyRate:  rates.County,
+			CityRate:    rates.City,
+			SpecialRate: rates.Special,
 		},
 		Jurisdictions: tax.Names,
 		ReportingCode: tax.Code,
+		State:         tax.State,
 		Status:        entity.OrderStatusCompleted,
-		CreatedAt:     p.Timestamp,
-		UpdatedAt:     p.Timestamp,
+		Explain:       explanation,
+
+		BoundaryResolved: tax.BoundaryResolved,
+		BoundaryDistance: tax.BoundaryDistance,
+
+		Warning:                warning,
+		AmbiguousJurisdictions: tax.Matches,
+		CreatedAt:              p.Timestamp,
+		UpdatedAt:              p.Timestamp,
+	}
+}
+
+// getOverrides returns the active override of the jurisdiction of the state
+// for the order category and for the category of every item, keyed by category.
+// Categories without an active override map to nil.
+func (uc *UseCase) getOverrides(ctx context.Context, state, reportingCode string, p dto.Order) map[string]*entity.TaxOverride {
+	overrides := make(map[string]*entity.TaxOverride, len(p.Items)+1)
+	overrides[p.Category], _ = uc.taxRepo.GetOverride(ctx, state, reportingCode, p.Category, p.Timestamp)
+
+	for _, item := range p.Items {
+		if _, ok := overrides[item.Category]; !ok {
+			overrides[item.Category], _ = uc.taxRepo.GetOverride(ctx, state, reportingCode, item.Category, p.Timestamp)
+		}
+	}
+	return overrides
+}
+
+// getCertificate returns the first of the certificates covering an order
+// taxed in the jurisdiction of the state at the given time, or nil.
+func (uc *UseCase) getCertificate(ctx context.Context, certs []entity.ExemptionCertificate, state, reportingCode string, at time.Time) *entity.ExemptionCertificate {
+	if len(certs) == 0 {
+		return nil
+	}
+
+	primary := uc.taxRepo.PrimaryState(ctx)
+	for i := range certs {
+		if certs[i].Covers(state, primary, reportingCode, at) {
+			return &certs[i]
+		}
+	}
+	return nil
+}
+
+// newOrderRequest rebuilds the request an order was created from.
+// Tax-inclusive amounts are made gross again from the components, and
+// orders geocoded by ZIP code are geocoded again instead of keeping
+// the coordinates they were given. Orders placed in another currency
+// get their amounts as given back, so that they are converted again.
+func newOrderRequest(o entity.Order) dto.Order {
+	p := dto.Order{
+		Currency:     o.Currency,
+		Timestamp:    o.CreatedAt,
+		Category:     o.Category,
+		CustomerRef:  o.CustomerRef,
+		CustomerId:   o.CustomerId,
+		Zip:          o.Zip,
+		TaxInclusive: o.TaxInclusive,
+		Explain:      o.Explain != nil,
+		Metadata:     o.Metadata,
+		Tags:         o.Tags,
+	}
+	if o.GeocodingMethod == entity.GeocodingMethodCoordinates || o.GeocodingMethod == "" {
+		p.Latitude, p.Longitude = o.Latitude, o.Longitude
+	}
+
+	for _, c := range o.Components {
+		amount := c.Amount
+		if o.TaxInclusive {
+			amount += c.TaxAmount
+		}
+
+		switch c.Component {
+		case entity.OrderComponentSubtotal:
+			p.Subtotal = amount
+		case entity.OrderComponentShipping:
+			p.Shipping = amount
+		case entity.OrderComponentHandling:
+			p.Handling = amount
+		case entity.OrderComponentDiscount:
+			p.Discount = -amount
+		}
+	}
+
+	rate := 1.0
+	if o.Original != nil && o.ExchangeRate > 0 {
+		rate = o.ExchangeRate
+		p.Subtotal = o.Original.Subtotal
+		p.Shipping = o.Original.Shipping
+		p.Handling = o.Original.Handling
+		p.Discount = o.Original.Discount
+	}
+
+	for _, item := range o.Items {
+		p.Items = append(p.Items, dto.OrderItem{
+			SKU:         item.SKU,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice / rate,
+			Category:    item.Category,
+			Discount:    item.Discount / rate,
+		})
+	}
+	return p
+}
+
+// newOrderLabels returns the metadata and tags of an order, empty
+// rather than nil. Tags are trimmed, and blank or repeated ones dropped.
+func newOrderLabels(metadata map[string]string, tags []string) (map[string]string, []string) {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	labels := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(labels, tag) {
+			labels = append(labels, tag)
+		}
+	}
+	return metadata, labels
+}
+
+// newOrderItems builds the untaxed order items and returns them
+// together with their total amount. An order without items returns nil.
+// The discount of an item is capped at its gross amount.
+func newOrderItems(lines []dto.OrderItem) ([]entity.OrderItem, float64) {
+	if len(lines) == 0 {
+		return nil, 0
+	}
+
+	items := make([]entity.OrderItem, len(lines))
+	var subtotal float64
+	for i, line := range lines {
+		gross := line.Quantity * line.UnitPrice
+		discount := min(line.Discount, gross)
+
+		items[i] = entity.OrderItem{
+			Line:        i + 1,
+			SKU:         line.SKU,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			Category:    line.Category,
+			Discount:    discount,
+			Amount:      gross - discount,
+		}
+		subtotal += items[i].Amount
+	}
+	return items, subtotal
+}
+
+// taxItems taxes every item at the jurisdiction rates changed by the override
+// and the taxability rule of its category and by the exemption certificate.
+// Taxability thresholds apply to the unit price of the item.
+// The tax of tax-inclusive items is backed out of their amount.
+// It returns the rates of the items weighted by their net amounts,
+// which are zero when the items add up to nothing.
+func taxItems(items []entity.OrderItem, tax entity.LocationTax, overrides map[string]*entity.TaxOverride, certificate *entity.ExemptionCertificate, inclusive bool) entity.JurisdictionTaxBreakdown {
+	var weighted entity.JurisdictionTaxBreakdown
+	var total float64
+	for i := range items {
+		item := &items[i]
+
+		rates := tax.Breakdown
+		if override := overrides[item.Category]; override != nil {
+			rates = override.Apply(rates)
+			item.TaxOverride = override.Name
+		}
+		if rule, ok := tax.Taxability[item.Category]; ok {
+			rates, _ = rule.ApplyCharged(rates, item.UnitPrice, inclusive)
+		}
+		if certificate != nil {
+			rates = certificate.Apply(rates)
+		}
+
+		item.TaxRate = rates.Total()
+		item.Amount, item.TaxAmount = entity.SplitTax(item.Amount, item.TaxRate, inclusive)
+
+		weighted.State += rates.State * item.Amount
+		weighted.County += rates.County * item.Amount
+		weighted.City += rates.City * item.Amount
+		weighted.Special += rates.Special * item.Amount
+		total += item.Amount
+	}
+
+	if total == 0 {
+		return entity.JurisdictionTaxBreakdown{}
+	}
+	return entity.JurisdictionTaxBreakdown{
+		State:   weighted.State / total,
+		County:  weighted.County / total,
+		City:    weighted.City / total,
+		Special: weighted.Special / total,
 	}
 }
 
 // mapCSVToEntity converts a CSV record into a DTO order.
 // It validates column count, parses coordinates, timestamp,
-// and subtotal amount. Invalid records return an error.
+// subtotal amount, the optional product category, customer reference,
+// address, shipping, handling, discount, items, currency, metadata and tags. Coordinates may be left
+// empty when the address is given, the subtotal when items are given;
+// empty amounts are zero.
+// Invalid records return an error.
 func (uc *UseCase) mapCSVToEntity(rec []string) (dto.Order, error) {
 	if len(rec) < 5 {
 		return dto.Order{}, fmt.Errorf("invalid column count")
 	}
 
-	lon, err := strconv.ParseFloat(rec[1], 64)
-	if err != nil {
-		return dto.Order{}, err
+	var address string
+	if len(rec) > 7 {
+		address = strings.TrimSpace(rec[7])
 	}
 
-	lat, err := strconv.ParseFloat(rec[2], 64)
-	if err != nil {
-		return dto.Order{}, err
+	var lon, lat float64
+	if address == "" || rec[1] != "" || rec[2] != "" {
+		var err error
+		lon, err = strconv.ParseFloat(rec[1], 64)
+		if err != nil {
+			return dto.Order{}, err
+		}
+
+		lat, err = strconv.ParseFloat(rec[2], 64)
+		if err != nil {
+			return dto.Order{}, err
+		}
 	}
 
 	const layout = "2006-01-02 15:04:05.999999999"
@@ -255,15 +1298,84 @@ func (uc *UseCase) mapCSVToEntity(rec []string) (dto.Order, error) {
 		return dto.Order{}, err
 	}
 
-	sub, err := strconv.ParseFloat(rec[4], 64)
-	if err != nil {
-		return dto.Order{}, err
+	// items follow in column 11 as a JSON array and replace the subtotal
+	var items []dto.OrderItem
+	if len(rec) > 11 && strings.TrimSpace(rec[11]) != "" {
+		if err := json.Unmarshal([]byte(rec[11]), &items); err != nil {
+			return dto.Order{}, fmt.Errorf("invalid items: %w", err)
+		}
+		for _, item := range items {
+			if item.Quantity <= 0 || item.UnitPrice < 0 || item.Discount < 0 {
+				return dto.Order{}, fmt.Errorf("invalid item %q", item.SKU)
+			}
+		}
+	}
+
+	var sub float64
+	if len(items) == 0 || strings.TrimSpace(rec[4]) != "" {
+		sub, err = strconv.ParseFloat(rec[4], 64)
+		if err != nil {
+			return dto.Order{}, err
+		}
+	}
+
+	var category, customerRef string
+	if len(rec) > 5 {
+		category = strings.TrimSpace(rec[5])
+	}
+	if len(rec) > 6 {
+		customerRef = strings.TrimSpace(rec[6])
+	}
+
+	// shipping, handling and discount follow in columns 8 to 10
+	var amounts [3]float64
+	for i := range amounts {
+		if len(rec) <= 8+i || strings.TrimSpace(rec[8+i]) == "" {
+			continue
+		}
+		amounts[i], err = strconv.ParseFloat(strings.TrimSpace(rec[8+i]), 64)
+		if err != nil {
+			return dto.Order{}, err
+		}
+		if amounts[i] < 0 {
+			return dto.Order{}, fmt.Errorf("negative amount in column %d", 8+i)
+		}
+	}
+
+	var currency string
+	if len(rec) > 12 {
+		currency = strings.TrimSpace(rec[12])
+	}
+
+	// metadata and tags follow in columns 13 and 14 as a JSON object and array
+	var metadata map[string]string
+	if len(rec) > 13 && strings.TrimSpace(rec[13]) != "" {
+		if err := json.Unmarshal([]byte(rec[13]), &metadata); err != nil {
+			return dto.Order{}, fmt.Errorf("invalid metadata: %w", err)
+		}
+	}
+
+	var tags []string
+	if len(rec) > 14 && strings.TrimSpace(rec[14]) != "" {
+		if err := json.Unmarshal([]byte(rec[14]), &tags); err != nil {
+			return dto.Order{}, fmt.Errorf("invalid tags: %w", err)
+		}
 	}
 
 	return dto.Order{
-		Longitude: lon,
-		Latitude:  lat,
-		Subtotal:  sub,
-		Timestamp: ts,
+		Longitude:   lon,
+		Latitude:    lat,
+		Subtotal:    sub,
+		Shipping:    amounts[0],
+		Handling:    amounts[1],
+		Discount:    amounts[2],
+		Items:       items,
+		Timestamp:   ts,
+		Category:    category,
+		CustomerRef: customerRef,
+		Address:     address,
+		Currency:    currency,
+		Metadata:    metadata,
+		Tags:        tags,
 	}, nil
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/csv"
 	"errors"
 	"io"
+	"math"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -18,16 +20,30 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func newTestUseCase(t *testing.T) (*UseCase, *repomocks.MockTaxRepo, *repomocks.MockOrderRepo) {
+// testRatesVersion is the version of the tax data loaded by test use cases.
+const testRatesVersion = "test-rates"
+
+// newTestUseCase returns a use case whose order history is not read.
+func newTestUseCase(t *testing.T) (*UseCase, *repomocks.MockTaxRepo, *repomocks.MockOrderRepo, *repomocks.MockExemptionRepo, *repomocks.MockGeocodeRepo) {
+	uc, taxRepo, orderRepo, exemptionRepo, geocodeRepo, _ := newTestUseCaseWithEvents(t)
+	return uc, taxRepo, orderRepo, exemptionRepo, geocodeRepo
+}
+
+func newTestUseCaseWithEvents(t *testing.T) (*UseCase, *repomocks.MockTaxRepo, *repomocks.MockOrderRepo, *repomocks.MockExemptionRepo, *repomocks.MockGeocodeRepo, *repomocks.MockOrderEventRepo) {
 	ctrl := gomock.NewController(t)
 	taxRepo := repomocks.NewMockTaxRepo(ctrl)
 	orderRepo := repomocks.NewMockOrderRepo(ctrl)
-	uc := New(context.Background(), taxRepo, orderRepo, time.Second*5, 1, zerolog.Nop())
-	return uc, taxRepo, orderRepo
+	exemptionRepo := repomocks.NewMockExemptionRepo(ctrl)
+	geocodeRepo := repomocks.NewMockGeocodeRepo(ctrl)
+	eventRepo := repomocks.NewMockOrderEventRepo(ctrl)
+	uc := New(context.Background(), taxRepo, orderRepo, exemptionRepo, repomocks.NewMockCustomerRepo(ctrl), geocodeRepo, eventRepo, repomocks.NewMockTaxRecalculationRepo(ctrl), repomocks.NewMockActiveExchangeRateRepo(ctrl), time.Second*5, 1, time.Hour, testRatesVersion, zerolog.Nop())
+	return uc, taxRepo, orderRepo, exemptionRepo, geocodeRepo, eventRepo
 }
 
 func TestCreate(t *testing.T) {
-	uc, taxRepo, orderRepo := newTestUseCase(t)
+	uc, taxRepo, orderRepo, exemptionRepo, geocodeRepo := newTestUseCase(t)
+	customerRepo := repomocks.NewMockCustomerRepo(gomock.NewController(t))
+	uc.customerRepo = customerRepo
 
 	t.Run("completed", func(t *testing.T) {
 		input := dto.Order{
@@ -51,13 +67,19 @@ func TestCreate(t *testing.T) {
 		gomock.InOrder(
 			taxRepo.EXPECT().
 				GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).
-				Return(&expectedTax, true),
+				Return(&entity.LocationTax{JurisdictionTax: expectedTax}, true),
+			taxRepo.EXPECT().
+				GetOverride(gomock.Any(), gomock.Any(), expectedTax.Code, input.Category, input.Timestamp).
+				Return(nil, false),
 			orderRepo.EXPECT().
-				Create(gomock.Any(), gomock.Any()).
-				DoAndReturn(func(ctx context.Context, o entity.Order) (int, error) {
+				Create(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, o entity.Order, e entity.OrderEvent) (int, error) {
 					if o.CompositeTaxRate != expectedTax.CompositeRate {
 						t.Errorf("wrong composite rate %v", o.CompositeTaxRate)
 					}
+					if e.Type != entity.OrderEventCreated || e.Before != nil || e.After == nil || e.After.Version != 1 {
+						t.Errorf("unexpected event %+v", e)
+					}
 					return 123, nil
 				}),
 		)
@@ -86,7 +108,7 @@ func TestCreate(t *testing.T) {
 			GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).
 			Return(nil, false)
 		orderRepo.EXPECT().
-			Create(gomock.Any(), gomock.Any()).
+			Create(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(5, nil)
 
 		out, err := uc.Create(context.Background(), input)
@@ -101,112 +123,1451 @@ func TestCreate(t *testing.T) {
 		}
 	})
 
-	t.Run("repo error", func(t *testing.T) {
+	t.Run("ambiguous jurisdiction", func(t *testing.T) {
 		input := dto.Order{
-			Latitude:  1,
-			Longitude: 2,
-			Subtotal:  1,
+			Latitude:  1.5,
+			Longitude: 1.5,
+			Subtotal:  100,
 			Timestamp: time.Now(),
 		}
-		expectedTax := entity.JurisdictionTax{CompositeRate: 0}
+		tax := entity.LocationTax{
+			JurisdictionTax: entity.JurisdictionTax{CompositeRate: 0.04, Names: []string{"Low"}, Code: "0001"},
+			Ambiguous:       true,
+			Matches:         []string{"Low", "High"},
+		}
+
+		taxRepo.EXPECT().
+			GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).
+			Return(&tax, true)
+		taxRepo.EXPECT().
+			GetOverride(gomock.Any(), gomock.Any(), "0001", input.Category, input.Timestamp).
+			Return(nil, false)
+		orderRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(7, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Status != entity.OrderStatusCompleted || out.Warning != entity.OrderWarningAmbiguousJurisdiction {
+			t.Errorf("got status %s warning %q, want completed with ambiguity warning", out.Status, out.Warning)
+		}
+		if len(out.AmbiguousJurisdictions) != 2 {
+			t.Errorf("ambiguous jurisdictions %v, want [Low High]", out.AmbiguousJurisdictions)
+		}
+	})
+
+	t.Run("ambiguous jurisdiction with fail policy", func(t *testing.T) {
+		input := dto.Order{
+			Latitude:  1.5,
+			Longitude: 1.5,
+			Subtotal:  100,
+			Timestamp: time.Now(),
+		}
+
+		taxRepo.EXPECT().
+			GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).
+			Return(&entity.LocationTax{Ambiguous: true, Matches: []string{"Low", "High"}}, false)
+		orderRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(8, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Status != entity.OrderStatusOutOfScope || out.Warning != entity.OrderWarningAmbiguousJurisdiction {
+			t.Errorf("got status %s warning %q, want out_of_scope with ambiguity warning", out.Status, out.Warning)
+		}
+		if out.TaxAmount != 0 {
+			t.Errorf("expected no tax, got %v", out.TaxAmount)
+		}
+	})
+
+	t.Run("geocoded by zip jurisdiction", func(t *testing.T) {
+		input := dto.Order{
+			Address:   "350 5th Ave, New York, NY 10118-0110",
+			Subtotal:  100,
+			Timestamp: time.Now(),
+		}
+		tax := entity.JurisdictionTax{CompositeRate: 0.08875, Names: []string{"New York"}, Code: "8081"}
 
 		gomock.InOrder(
-			taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), gomock.Any(), gomock.Any()).Return(&expectedTax, true),
-			orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(0, errors.New("boom")),
+			geocodeRepo.EXPECT().
+				GeocodeZip(gomock.Any(), "10118-0110").
+				Return(entity.Geocode{
+					Method:       entity.GeocodingMethodZipJurisdiction,
+					Precision:    entity.GeocodingPrecisionZip5,
+					Zip:          "10118",
+					Latitude:     40.7484,
+					Longitude:    -73.9967,
+					Jurisdiction: "New York",
+				}, true),
+			taxRepo.EXPECT().
+				GetTaxByJurisdiction(gomock.Any(), "", "New York", 40.7484, -73.9967).
+				Return(&entity.LocationTax{JurisdictionTax: tax}, true),
+			taxRepo.EXPECT().
+				GetOverride(gomock.Any(), gomock.Any(), "8081", input.Category, input.Timestamp).
+				Return(nil, false),
+			orderRepo.EXPECT().
+				Create(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(9, nil),
 		)
 
-		_, err := uc.Create(context.Background(), input)
-		if err == nil {
-			t.Fatal("expected error")
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Status != entity.OrderStatusCompleted || out.ReportingCode != "8081" {
+			t.Errorf("got status %s code %s, want completed 8081", out.Status, out.ReportingCode)
+		}
+		if out.GeocodingMethod != entity.GeocodingMethodZipJurisdiction || out.GeocodingPrecision != entity.GeocodingPrecisionZip5 || out.Zip != "10118" {
+			t.Errorf("got geocoding %s/%s/%s", out.GeocodingMethod, out.GeocodingPrecision, out.Zip)
 		}
 	})
-}
 
-func TestPassthroughMethods(t *testing.T) {
-	uc, _, orderRepo := newTestUseCase(t)
+	t.Run("geocoded by zip centroid", func(t *testing.T) {
+		input := dto.Order{Zip: "10001", Subtotal: 100, Timestamp: time.Now()}
 
-	orderRepo.EXPECT().GetById(gomock.Any(), 42).Return(entity.Order{Id: 42}, nil)
-	if _, err := uc.GetById(context.Background(), 42); err != nil {
-		t.Fatal(err)
-	}
+		gomock.InOrder(
+			geocodeRepo.EXPECT().
+				GeocodeZip(gomock.Any(), "10001").
+				Return(entity.Geocode{
+					Method:    entity.GeocodingMethodZipCentroid,
+					Precision: entity.GeocodingPrecisionZip5,
+					Zip:       "10001",
+					Latitude:  40.75,
+					Longitude: -73.99,
+				}, true),
+			taxRepo.EXPECT().
+				GetTaxByLocation(gomock.Any(), 40.75, -73.99).
+				Return(nil, false),
+			orderRepo.EXPECT().
+				Create(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(10, nil),
+		)
 
-	orderRepo.EXPECT().GetAll(gomock.Any(), dto.OrderFilters{Limit: 1, Offset: 0}).Return(entity.OrderList{}, nil)
-	if _, err := uc.GetAll(context.Background(), dto.OrderFilters{Limit: 1, Offset: 0}); err != nil {
-		t.Fatal(err)
-	}
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Latitude != 40.75 || out.Longitude != -73.99 || out.GeocodingMethod != entity.GeocodingMethodZipCentroid {
+			t.Errorf("got %v,%v by %s, want the zip centroid", out.Latitude, out.Longitude, out.GeocodingMethod)
+		}
+	})
 
-	orderRepo.EXPECT().DeleteAll(gomock.Any()).Return(nil)
-	if err := uc.DeleteAll(context.Background()); err != nil {
-		t.Fatal(err)
-	}
-}
+	t.Run("unresolved zip", func(t *testing.T) {
+		input := dto.Order{Zip: "99999", Subtotal: 100, Timestamp: time.Now()}
 
-func Test_mapCSVToEntity(t *testing.T) {
-	uc, _, _ := newTestUseCase(t)
+		geocodeRepo.EXPECT().GeocodeZip(gomock.Any(), "99999").Return(entity.Geocode{}, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(11, nil)
 
-	t.Run("invalid columns", func(t *testing.T) {
-		_, err := uc.mapCSVToEntity([]string{"a", "b", "c"})
-		if err == nil {
-			t.Fatal("expected error")
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Status != entity.OrderStatusFailedTaxResolution || out.GeocodingMethod != entity.GeocodingMethodUnresolved {
+			t.Errorf("got status %s method %s, want unresolved failed_tax_resolution", out.Status, out.GeocodingMethod)
 		}
 	})
 
-	t.Run("valid row", func(t *testing.T) {
-		row := []string{"1", "10.1", "20.2", "2023-01-01 00:00:00.000000000", "15.5"}
-		d, err := uc.mapCSVToEntity(row)
+	t.Run("jurisdiction without tax", func(t *testing.T) {
+		input := dto.Order{Latitude: 11, Longitude: 21, Subtotal: 100, Timestamp: time.Now()}
+
+		taxRepo.EXPECT().
+			GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).
+			Return(&entity.LocationTax{State: "NY"}, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(12, nil)
+
+		out, err := uc.Create(context.Background(), input)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if d.Latitude != 20.2 || d.Longitude != 10.1 || d.Subtotal != 15.5 {
-			t.Error("parsed values mismatch")
+		if out.Status != entity.OrderStatusFailedTaxResolution {
+			t.Errorf("got status %s, want failed_tax_resolution", out.Status)
 		}
 	})
 
-	t.Run("invalid longitude", func(t *testing.T) {
-		row := []string{"1", "bad", "20.2", "2023-01-01 00:00:00.000000000", "15.5"}
-		if _, err := uc.mapCSVToEntity(row); err == nil {
-			t.Fatal("expected parse error for longitude")
+	t.Run("category taxability", func(t *testing.T) {
+		input := dto.Order{
+			Latitude:  40.7,
+			Longitude: -74,
+			Subtotal:  50,
+			Category:  "clothing",
+			Timestamp: time.Now(),
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08875,
+			Breakdown: entity.JurisdictionTaxBreakdown{
+				State:   0.04,
+				City:    0.045,
+				Special: 0.00375,
+			},
+			Taxability: map[string]entity.TaxabilityRule{
+				"clothing": {Treatment: entity.TaxTreatmentTaxable, Threshold: 110, BelowThreshold: entity.TaxTreatmentLocalOnly},
+			},
+		}
+
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(9, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Breakdown.StateRate != 0 {
+			t.Errorf("expected state rate to be exempt, got %v", out.Breakdown.StateRate)
+		}
+		if math.Abs(out.CompositeTaxRate-0.04875) > 1e-9 {
+			t.Errorf("wrong composite rate %v", out.CompositeTaxRate)
+		}
+		if out.Category != "clothing" {
+			t.Errorf("wrong category %q", out.Category)
 		}
 	})
 
-	t.Run("invalid timestamp", func(t *testing.T) {
-		row := []string{"1", "10.1", "20.2", "bad-time", "15.5"}
-		if _, err := uc.mapCSVToEntity(row); err == nil {
-			t.Fatal("expected parse error for timestamp")
+	t.Run("tax holiday", func(t *testing.T) {
+		ts := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+		input := dto.Order{
+			Latitude:  40.7,
+			Longitude: -74,
+			Subtotal:  200,
+			Category:  "clothing",
+			Timestamp: ts,
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+			Code:          "0001",
+		}
+		holiday := entity.TaxOverride{
+			Name:          "back-to-school",
+			Jurisdictions: []string{entity.AllJurisdictions},
+			Category:      "clothing",
+			Components:    []entity.TaxLayerLevel{entity.TaxLayerLevelState},
+			StartsAt:      ts.AddDate(0, 0, -3),
+			EndsAt:        ts.AddDate(0, 0, 3),
+		}
+
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax, State: "NY"}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), "NY", "0001", "clothing", ts).Return(&holiday, true)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(11, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.TaxOverride != "back-to-school" {
+			t.Errorf("wrong tax override %q", out.TaxOverride)
+		}
+		if out.Breakdown.StateRate != 0 || math.Abs(out.TaxAmount-8) > 1e-9 {
+			t.Errorf("unexpected breakdown %+v and tax amount %v", out.Breakdown, out.TaxAmount)
 		}
 	})
-}
 
-func TestAsyncBatchCreate(t *testing.T) {
-	uc, taxRepo, orderRepo := newTestUseCase(t)
+	t.Run("exempt customer", func(t *testing.T) {
+		ts := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		input := dto.Order{
+			Latitude:    40.7,
+			Longitude:   -74,
+			Subtotal:    100,
+			CustomerRef: "acme",
+			Timestamp:   ts,
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+			Code:          "0001",
+		}
+		certs := []entity.ExemptionCertificate{
+			{Number: "EXPIRED", Jurisdictions: []string{entity.AllJurisdictions}, ValidFrom: ts.AddDate(-2, 0, 0), ValidTo: ptr(ts.AddDate(-1, 0, 0))},
+			{Number: "ENDS-NOW", Jurisdictions: []string{entity.AllJurisdictions}, ValidFrom: ts.AddDate(-1, 0, 0), ValidTo: ptr(ts)},
+			{Number: "OTHER", Jurisdictions: []string{"0002"}, ValidFrom: ts.AddDate(-1, 0, 0)},
+			{Number: "NJ-1", State: "NJ", Jurisdictions: []string{"0001"}, ValidFrom: ts.AddDate(-1, 0, 0)},
+			{Number: "RESALE-1", Jurisdictions: []string{"0001"}, Components: []entity.TaxLayerLevel{entity.TaxLayerLevelState}, ValidFrom: ts.AddDate(-1, 0, 0)},
+		}
 
-	// create CSV with two records; first returns tax, second missing
-	csvData := strings.Join([]string{
-		"1,30.0,50.0,2023-01-01 00:00:00.000000000,10.0",
-		"2,40.0,60.0,2023-01-02 00:00:00.000000000,20.0",
-	}, "\n")
-	src := io.NopCloser(strings.NewReader(csvData))
-	reader := csv.NewReader(src)
+		customerRepo.EXPECT().GetByExternalRef(gomock.Any(), "acme").Return(entity.Customer{}, entity.ErrCustomerNotFound)
+		exemptionRepo.EXPECT().GetByCustomerRef(gomock.Any(), "acme").Return(certs, nil)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax, State: "NY"}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+		taxRepo.EXPECT().PrimaryState(gomock.Any()).Return("NY")
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(10, nil)
 
-	// expectations: two tax lookups and two batch writes (batch size == 1)
-	gomock.InOrder(
-		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 50.0, 30.0).
-			Return(&entity.JurisdictionTax{CompositeRate: 0.1, Names: []string{"A"}, Code: "A"}, true),
-		orderRepo.EXPECT().BatchCreate(gomock.Any(), gomock.Any()).Do(func(ctx interface{}, orders interface{}) {
-			o := orders.([]entity.Order)
-			if len(o) != 1 {
-				t.Errorf("expected batch size 1, got %d", len(o))
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.ExemptionCertificate != "RESALE-1" {
+			t.Errorf("wrong certificate %q", out.ExemptionCertificate)
+		}
+		if out.Breakdown.StateRate != 0 || out.Breakdown.CountyRate != 0.04 {
+			t.Errorf("unexpected breakdown %+v", out.Breakdown)
+		}
+		if math.Abs(out.TaxAmount-4) > 1e-9 {
+			t.Errorf("wrong tax amount %v", out.TaxAmount)
+		}
+	})
+
+	t.Run("shipping handling and discount", func(t *testing.T) {
+		input := dto.Order{
+			Latitude:  40.7,
+			Longitude: -74,
+			Subtotal:  100,
+			Shipping:  10,
+			Handling:  5,
+			Discount:  20,
+			Timestamp: time.Now(),
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+			ComponentTaxability: map[entity.OrderComponent]entity.TaxabilityRule{
+				entity.OrderComponentShipping: {Treatment: entity.TaxTreatmentExempt},
+				entity.OrderComponentHandling: {Treatment: entity.TaxTreatmentStateOnly},
+			},
+		}
+
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(11, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []entity.OrderComponentTax{
+			{Component: entity.OrderComponentSubtotal, Amount: 100, TaxableAmount: 100, TaxRate: 0.08, TaxAmount: 8},
+			{Component: entity.OrderComponentShipping, Amount: 10},
+			{Component: entity.OrderComponentHandling, Amount: 5, TaxableAmount: 5, TaxRate: 0.04, TaxAmount: 0.2},
+			{Component: entity.OrderComponentDiscount, Amount: -20, TaxableAmount: -20, TaxRate: 0.08, TaxAmount: -1.6},
+		}
+		if len(out.Components) != len(want) {
+			t.Fatalf("got components %+v", out.Components)
+		}
+		for i, c := range out.Components {
+			if c.Component != want[i].Component || c.Amount != want[i].Amount || c.TaxableAmount != want[i].TaxableAmount ||
+				math.Abs(c.TaxRate-want[i].TaxRate) > 1e-9 || math.Abs(c.TaxAmount-want[i].TaxAmount) > 1e-9 {
+				t.Errorf("component %d: got %+v, want %+v", i, c, want[i])
 			}
-		}),
-		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 60.0, 40.0).
-			Return(nil, false),
-		orderRepo.EXPECT().BatchCreate(gomock.Any(), gomock.Any()).Do(func(ctx interface{}, orders interface{}) {
-			o := orders.([]entity.Order)
-			if o[0].Status != entity.OrderStatusOutOfScope {
-				t.Errorf("expected out_of_scope status")
+		}
+		if math.Abs(out.TaxAmount-6.6) > 1e-9 {
+			t.Errorf("wrong tax amount %v", out.TaxAmount)
+		}
+		if math.Abs(out.TotalAmount-101.6) > 1e-9 {
+			t.Errorf("wrong grand total %v", out.TotalAmount)
+		}
+	})
+
+	t.Run("tax inclusive", func(t *testing.T) {
+		input := dto.Order{
+			Latitude:     40.7,
+			Longitude:    -74,
+			Subtotal:     108,
+			Shipping:     10,
+			TaxInclusive: true,
+			Timestamp:    time.Now(),
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+		}
+
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(12, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !out.TaxInclusive {
+			t.Error("expected tax inclusive order")
+		}
+		// 10 shipping backs out 0.7407... of tax, rounded to 0.74
+		if out.Subtotal != 100 || out.Shipping != 9.26 {
+			t.Errorf("wrong net amounts: subtotal %v, shipping %v", out.Subtotal, out.Shipping)
+		}
+		if math.Abs(out.TaxAmount-8.74) > 1e-9 {
+			t.Errorf("wrong tax amount %v", out.TaxAmount)
+		}
+		if math.Abs(out.TotalAmount-118) > 1e-9 {
+			t.Errorf("wrong grand total %v", out.TotalAmount)
+		}
+	})
+
+	t.Run("tax inclusive threshold", func(t *testing.T) {
+		input := dto.Order{
+			Latitude:     40.7,
+			Longitude:    -74,
+			Subtotal:     115,
+			Category:     "clothing",
+			TaxInclusive: true,
+			Explain:      true,
+			Timestamp:    time.Now(),
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08875,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, City: 0.045, Special: 0.00375},
+			Taxability: map[string]entity.TaxabilityRule{
+				"clothing": {Treatment: entity.TaxTreatmentTaxable, Threshold: 110, BelowThreshold: entity.TaxTreatmentLocalOnly},
+			},
+		}
+
+		taxRepo.EXPECT().ExplainTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax}, entity.TaxExplanation{}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(13, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// 115 including tax is about 105.63 net at the full rate,
+		// which is under the threshold, so only local rates apply
+		if math.Abs(out.CompositeTaxRate-0.04875) > 1e-9 {
+			t.Errorf("wrong composite rate %v", out.CompositeTaxRate)
+		}
+		if out.TaxAmount != 5.35 || out.Subtotal != 109.65 {
+			t.Errorf("wrong tax %v and net subtotal %v", out.TaxAmount, out.Subtotal)
+		}
+		if out.Explain == nil || out.Explain.Subtotal != 109.65 {
+			t.Errorf("expected the net subtotal in the explanation, got %+v", out.Explain)
+		}
+	})
+
+	t.Run("items", func(t *testing.T) {
+		ts := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+		input := dto.Order{
+			Latitude:  40.7,
+			Longitude: -74,
+			Subtotal:  1,
+			Items: []dto.OrderItem{
+				{SKU: "SHIRT", Quantity: 2, UnitPrice: 50, Category: "clothing", Discount: 10},
+				{SKU: "LAPTOP", Quantity: 1, UnitPrice: 1000, Category: "electronics"},
+				{SKU: "BOOK", Quantity: 3, UnitPrice: 20},
+			},
+			Timestamp: ts,
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+			Code:          "0001",
+			Taxability: map[string]entity.TaxabilityRule{
+				"clothing": {Treatment: entity.TaxTreatmentTaxable, Threshold: 110, BelowThreshold: entity.TaxTreatmentLocalOnly},
+			},
+		}
+		holiday := &entity.TaxOverride{Name: "Electronics holiday", Category: "electronics"}
+
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "", ts).Return(nil, false)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "clothing", ts).Return(nil, false)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "electronics", ts).Return(holiday, true)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(12, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []entity.OrderItem{
+			{Line: 1, SKU: "SHIRT", Discount: 10, Amount: 90, TaxRate: 0.04, TaxAmount: 3.6},
+			{Line: 2, SKU: "LAPTOP", Amount: 1000, TaxOverride: "Electronics holiday"},
+			{Line: 3, SKU: "BOOK", Amount: 60, TaxRate: 0.08, TaxAmount: 4.8},
+		}
+		if len(out.Items) != len(want) {
+			t.Fatalf("got items %+v", out.Items)
+		}
+		for i, item := range out.Items {
+			if item.Line != want[i].Line || item.SKU != want[i].SKU || item.Discount != want[i].Discount || item.Amount != want[i].Amount ||
+				item.TaxOverride != want[i].TaxOverride || math.Abs(item.TaxRate-want[i].TaxRate) > 1e-9 || math.Abs(item.TaxAmount-want[i].TaxAmount) > 1e-9 {
+				t.Errorf("item %d: got %+v, want %+v", i, item, want[i])
 			}
-		}),
-	)
+		}
+
+		if out.Subtotal != 1150 {
+			t.Errorf("expected subtotal derived from items, got %v", out.Subtotal)
+		}
+		if math.Abs(out.TaxAmount-8.4) > 1e-9 || math.Abs(out.TotalAmount-1158.4) > 1e-9 {
+			t.Errorf("wrong totals: tax %v total %v", out.TaxAmount, out.TotalAmount)
+		}
+		if c := out.Components[0]; c.TaxableAmount != 150 || math.Abs(c.TaxAmount-8.4) > 1e-9 {
+			t.Errorf("wrong subtotal component %+v", c)
+		}
+		if math.Abs(out.CompositeTaxRate-8.4/1150) > 1e-9 {
+			t.Errorf("expected the item-weighted composite rate, got %v", out.CompositeTaxRate)
+		}
+	})
+
+	t.Run("items with shipping", func(t *testing.T) {
+		ts := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+		input := dto.Order{
+			Latitude:  40.7,
+			Longitude: -74,
+			Subtotal:  1,
+			Shipping:  10,
+			Category:  "food",
+			Items: []dto.OrderItem{
+				{SKU: "BOOK", Quantity: 1, UnitPrice: 100},
+				{SKU: "BREAD", Quantity: 1, UnitPrice: 100, Category: "food"},
+			},
+			Explain:   true,
+			Timestamp: ts,
+		}
+		tax := entity.JurisdictionTax{
+			CompositeRate: 0.08,
+			Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+			Code:          "0001",
+			Taxability: map[string]entity.TaxabilityRule{
+				"food": {Treatment: entity.TaxTreatmentExempt},
+			},
+		}
+
+		taxRepo.EXPECT().ExplainTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax}, entity.TaxExplanation{}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "", ts).Return(nil, false)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "food", ts).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(14, nil)
+
+		out, err := uc.Create(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// half of the merchandise is exempt, so shipping is taxed at half the rate
+		if c := out.Components[1]; c.Component != entity.OrderComponentShipping || math.Abs(c.TaxRate-0.04) > 1e-9 || math.Abs(c.TaxAmount-0.4) > 1e-9 {
+			t.Errorf("wrong shipping component %+v", c)
+		}
+		if math.Abs(out.CompositeTaxRate-0.04) > 1e-9 || math.Abs(out.Breakdown.StateRate-0.02) > 1e-9 || math.Abs(out.Breakdown.CountyRate-0.02) > 1e-9 {
+			t.Errorf("expected the item-weighted rates, got %v %+v", out.CompositeTaxRate, out.Breakdown)
+		}
+		if math.Abs(out.TaxAmount-8.4) > 1e-9 {
+			t.Errorf("wrong tax amount %v", out.TaxAmount)
+		}
+		if out.Explain == nil || len(out.Explain.Steps) == 0 || out.Explain.Steps[len(out.Explain.Steps)-1].Kind != entity.ComputationStepItems {
+			t.Errorf("expected an items step, got %+v", out.Explain)
+		}
+	})
+
+	t.Run("exemption lookup error", func(t *testing.T) {
+		input := dto.Order{Latitude: 1, Longitude: 2, Subtotal: 1, CustomerRef: "acme", Timestamp: time.Now()}
+
+		customerRepo.EXPECT().GetByExternalRef(gomock.Any(), "acme").Return(entity.Customer{}, entity.ErrCustomerNotFound)
+		exemptionRepo.EXPECT().GetByCustomerRef(gomock.Any(), "acme").Return(nil, errors.New("boom"))
+
+		if _, err := uc.Create(context.Background(), input); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		input := dto.Order{
+			Latitude:  1,
+			Longitude: 2,
+			Subtotal:  1,
+			Timestamp: time.Now(),
+		}
+		expectedTax := entity.JurisdictionTax{CompositeRate: 0}
+
+		gomock.InOrder(
+			taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), gomock.Any(), gomock.Any()).Return(&entity.LocationTax{JurisdictionTax: expectedTax}, true),
+			taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false),
+			orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, errors.New("boom")),
+		)
+
+		_, err := uc.Create(context.Background(), input)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestCreateForCustomer(t *testing.T) {
+	ts := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	tax := entity.JurisdictionTax{CompositeRate: 0.08, Breakdown: entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04}, Code: "0001"}
+	customer := entity.Customer{Id: 3, ExternalRef: "acme", Latitude: ptr(40.7), Longitude: ptr(-74.0)}
+
+	t.Run("stored location", func(t *testing.T) {
+		uc, taxRepo, orderRepo, exemptionRepo, _ := newTestUseCase(t)
+		customerRepo := repomocks.NewMockCustomerRepo(gomock.NewController(t))
+		uc.customerRepo = customerRepo
+
+		customerRepo.EXPECT().GetByExternalRef(gomock.Any(), "acme").Return(customer, nil)
+		exemptionRepo.EXPECT().GetByCustomerRef(gomock.Any(), "acme").Return(nil, nil)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "", ts).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil)
+
+		out, err := uc.Create(context.Background(), dto.Order{Subtotal: 100, Timestamp: ts, CustomerRef: "acme"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.CustomerId == nil || *out.CustomerId != 3 || out.Latitude != 40.7 || out.Status != entity.OrderStatusCompleted {
+			t.Errorf("unexpected order %+v", out)
+		}
+	})
+
+	t.Run("own location", func(t *testing.T) {
+		p := linkCustomer(dto.Order{Latitude: 35, Longitude: -80}, &customer)
+		if p.Latitude != 35 || p.CustomerRef != "acme" || *p.CustomerId != 3 {
+			t.Errorf("unexpected order request %+v", p)
+		}
+	})
+
+	t.Run("mismatched reference", func(t *testing.T) {
+		uc, _, _, _, _ := newTestUseCase(t)
+		customerRepo := repomocks.NewMockCustomerRepo(gomock.NewController(t))
+		uc.customerRepo = customerRepo
+
+		customerRepo.EXPECT().GetById(gomock.Any(), 3).Return(customer, nil)
+
+		_, err := uc.Create(context.Background(), dto.Order{Subtotal: 100, Timestamp: ts, CustomerId: ptr(3), CustomerRef: "other"})
+		if !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("unknown customer id", func(t *testing.T) {
+		uc, _, _, _, _ := newTestUseCase(t)
+		customerRepo := repomocks.NewMockCustomerRepo(gomock.NewController(t))
+		uc.customerRepo = customerRepo
+
+		customerRepo.EXPECT().GetById(gomock.Any(), 4).Return(entity.Customer{}, entity.ErrCustomerNotFound)
+
+		_, err := uc.Create(context.Background(), dto.Order{Subtotal: 100, Timestamp: ts, CustomerId: ptr(4)})
+		if !errors.Is(err, entity.ErrCustomerNotFound) {
+			t.Fatalf("expected ErrCustomerNotFound, got %v", err)
+		}
+	})
+}
+
+func TestExplain(t *testing.T) {
+	uc, taxRepo, _, _, _ := newTestUseCase(t)
+
+	ts := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	input := dto.Order{Latitude: 40.7, Longitude: -74, Subtotal: 50, Category: "clothing", Timestamp: ts}
+	tax := entity.JurisdictionTax{
+		CompositeRate: 0.08,
+		Breakdown:     entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04},
+		Code:          "0001",
+		Taxability: map[string]entity.TaxabilityRule{
+			"clothing": {Treatment: entity.TaxTreatmentTaxable, Threshold: 110, BelowThreshold: entity.TaxTreatmentLocalOnly},
+		},
+	}
+	explanation := entity.TaxExplanation{
+		Steps: []entity.ComputationStep{{Kind: entity.ComputationStepJurisdiction, Name: "A", Breakdown: tax.Breakdown, CompositeRate: tax.CompositeRate}},
+	}
+
+	taxRepo.EXPECT().ExplainTaxByLocation(gomock.Any(), input.Latitude, input.Longitude).Return(&entity.LocationTax{JurisdictionTax: tax}, explanation, true)
+	taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "clothing", ts).Return(nil, false)
+
+	out, err := uc.Explain(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Steps) != 2 || out.Steps[1].Kind != entity.ComputationStepTaxability || out.Steps[1].Name != "clothing: local_only" {
+		t.Fatalf("unexpected steps %+v", out.Steps)
+	}
+	if out.Subtotal != 50 || math.Abs(out.CompositeRate-0.04) > 1e-9 || math.Abs(out.TaxAmount-2) > 1e-9 {
+		t.Errorf("unexpected computation %+v", out)
+	}
+}
+
+func TestCreateConverted(t *testing.T) {
+	ts := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	tax := entity.JurisdictionTax{CompositeRate: 0.08, Breakdown: entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04}, Code: "0001"}
+	rate := entity.ExchangeRate{Currency: "EUR", Date: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), Rate: 1.1}
+
+	t.Run("converted", func(t *testing.T) {
+		uc, taxRepo, orderRepo, _, _ := newTestUseCase(t)
+		exchangeRepo := repomocks.NewMockActiveExchangeRateRepo(gomock.NewController(t))
+		uc.exchangeRepo = exchangeRepo
+
+		exchangeRepo.EXPECT().GetRate(gomock.Any(), "EUR", ts).Return(rate, true)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "", ts).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil)
+
+		out, err := uc.Create(context.Background(), dto.Order{Latitude: 40.7, Longitude: -74, Subtotal: 100, Shipping: 10, Timestamp: ts, Currency: "eur"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Currency != "EUR" || out.ExchangeRate != 1.1 || math.Abs(out.Subtotal-110) > 1e-9 || math.Abs(out.TaxAmount-9.68) > 1e-9 {
+			t.Errorf("unexpected converted order %+v", out)
+		}
+		want := entity.OrderAmounts{Subtotal: 100, Shipping: 10, TotalAmount: 118.8, TaxAmount: 8.8}
+		if out.Original == nil || *out.Original != want {
+			t.Errorf("expected original amounts %+v, got %+v", want, out.Original)
+		}
+
+		p := newOrderRequest(out)
+		if p.Currency != "EUR" || p.Subtotal != 100 || p.Shipping != 10 {
+			t.Errorf("expected original amounts in rebuilt request, got %+v", p)
+		}
+	})
+
+	t.Run("items converted like the subtotal", func(t *testing.T) {
+		uc, taxRepo, orderRepo, _, _ := newTestUseCase(t)
+		exchangeRepo := repomocks.NewMockActiveExchangeRateRepo(gomock.NewController(t))
+		uc.exchangeRepo = exchangeRepo
+
+		exchangeRepo.EXPECT().GetRate(gomock.Any(), "EUR", ts).Return(rate, true).Times(2)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(&entity.LocationTax{JurisdictionTax: tax}, true).Times(2)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "", ts).Return(nil, false).Times(2)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		// 9.44 converts to 10.384, and so do the unit price of 9.99 and the
+		// discount of 0.55 unless they are rounded to cents like the subtotal
+		plain, err := uc.Create(context.Background(), dto.Order{Latitude: 40.7, Longitude: -74, Subtotal: 9.44, Timestamp: ts, Currency: "EUR"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		itemized, err := uc.Create(context.Background(), dto.Order{
+			Latitude: 40.7, Longitude: -74, Timestamp: ts, Currency: "EUR",
+			Items: []dto.OrderItem{{SKU: "MUG", Quantity: 1, UnitPrice: 9.99, Discount: 0.55}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if plain.Subtotal != 10.38 || itemized.Subtotal != plain.Subtotal || itemized.TaxAmount != plain.TaxAmount {
+			t.Errorf("expected the same converted subtotal and tax, got %v, %v and %v, %v", plain.Subtotal, plain.TaxAmount, itemized.Subtotal, itemized.TaxAmount)
+		}
+		if item := itemized.Items[0]; item.UnitPrice != 10.99 || item.Discount != 0.61 {
+			t.Errorf("expected item amounts rounded to cents, got %+v", item)
+		}
+	})
+
+	t.Run("reporting currency", func(t *testing.T) {
+		uc, taxRepo, orderRepo, _, _ := newTestUseCase(t)
+
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), "0001", "", ts).Return(nil, false)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil)
+
+		out, err := uc.Create(context.Background(), dto.Order{Latitude: 40.7, Longitude: -74, Subtotal: 100, Timestamp: ts})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Currency != entity.ReportingCurrency || out.ExchangeRate != 1 || out.Original != nil {
+			t.Errorf("unexpected order %+v", out)
+		}
+	})
+
+	t.Run("no rate", func(t *testing.T) {
+		uc, _, _, _, _ := newTestUseCase(t)
+		exchangeRepo := repomocks.NewMockActiveExchangeRateRepo(gomock.NewController(t))
+		uc.exchangeRepo = exchangeRepo
+
+		exchangeRepo.EXPECT().GetRate(gomock.Any(), "JPY", ts).Return(entity.ExchangeRate{}, false)
+
+		_, err := uc.Create(context.Background(), dto.Order{Latitude: 40.7, Longitude: -74, Subtotal: 100, Timestamp: ts, Currency: "JPY"})
+		if !errors.Is(err, entity.ErrExchangeRateNotFound) {
+			t.Fatalf("expected ErrExchangeRateNotFound, got %v", err)
+		}
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	uc, taxRepo, orderRepo, _, _ := newTestUseCase(t)
+	created := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	order := entity.Order{
+		Id:              5,
+		Latitude:        40.7,
+		Longitude:       -74,
+		Subtotal:        100,
+		TotalAmount:     108,
+		TaxAmount:       8,
+		Components:      []entity.OrderComponentTax{{Component: entity.OrderComponentSubtotal, Amount: 100, TaxableAmount: 100, TaxRate: 0.08, TaxAmount: 8}},
+		GeocodingMethod: entity.GeocodingMethodCoordinates,
+		Status:          entity.OrderStatusCompleted,
+		Version:         2,
+		CreatedAt:       created,
+	}
+	tax := entity.JurisdictionTax{CompositeRate: 0.08, Breakdown: entity.JurisdictionTaxBreakdown{State: 0.04, County: 0.04}}
+
+	t.Run("subtotal", func(t *testing.T) {
+		orderRepo.EXPECT().GetById(gomock.Any(), 5).Return(order, nil)
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 40.7, -74.0).Return(&entity.LocationTax{JurisdictionTax: tax}, true)
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), created).Return(nil, false)
+		orderRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o entity.Order, e entity.OrderEvent) error {
+			if o.Id != 5 || o.Version != 2 || o.Subtotal != 200 || !o.CreatedAt.Equal(created) {
+				t.Errorf("unexpected updated order %+v", o)
+			}
+			if e.Type != entity.OrderEventUpdated || e.OrderId != 5 || e.Before.Subtotal != 100 || e.After.Subtotal != 200 || e.After.Version != 3 {
+				t.Errorf("unexpected event %+v", e)
+			}
+			return nil
+		})
+
+		out, err := uc.Update(context.Background(), 5, 2, dto.OrderUpdate{Subtotal: ptr(200.0)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Version != 3 || math.Abs(out.TaxAmount-16) > 1e-9 || out.UpdatedAt.Equal(created) {
+			t.Errorf("unexpected order %+v", out)
+		}
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		orderRepo.EXPECT().GetById(gomock.Any(), 5).Return(order, nil)
+
+		if _, err := uc.Update(context.Background(), 5, 1, dto.OrderUpdate{}); !errors.Is(err, entity.ErrOrderVersionConflict) {
+			t.Fatalf("expected ErrOrderVersionConflict, got %v", err)
+		}
+	})
+
+	t.Run("subtotal of order with items", func(t *testing.T) {
+		withItems := order
+		withItems.Items = []entity.OrderItem{{Line: 1, Quantity: 1, UnitPrice: 100, Amount: 100}}
+		orderRepo.EXPECT().GetById(gomock.Any(), 5).Return(withItems, nil)
+
+		if _, err := uc.Update(context.Background(), 5, 0, dto.OrderUpdate{Subtotal: ptr(50.0)}); !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("voided", func(t *testing.T) {
+		voided := order
+		voided.Status = entity.OrderStatusVoided
+		orderRepo.EXPECT().GetById(gomock.Any(), 5).Return(voided, nil)
+
+		if _, err := uc.Update(context.Background(), 5, 0, dto.OrderUpdate{}); !errors.Is(err, entity.ErrOrderNotEditable) {
+			t.Fatalf("expected ErrOrderNotEditable, got %v", err)
+		}
+	})
+}
+
+func TestVoid(t *testing.T) {
+	uc, _, orderRepo, _, _ := newTestUseCase(t)
+	order := entity.Order{Id: 6, Status: entity.OrderStatusOutOfScope, Version: 1}
+
+	orderRepo.EXPECT().GetById(gomock.Any(), 6).Return(order, nil)
+	orderRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o entity.Order, _ entity.OrderEvent) error {
+		if o.Status != entity.OrderStatusVoided || o.Version != 1 || o.UpdatedAt.IsZero() {
+			t.Errorf("unexpected voided order %+v", o)
+		}
+		want := []entity.StatusTransition{{From: entity.OrderStatusOutOfScope, To: entity.OrderStatusVoided, At: o.UpdatedAt}}
+		if !slices.Equal(o.StatusTransitions, want) {
+			t.Errorf("unexpected status transitions %+v", o.StatusTransitions)
+		}
+		return nil
+	})
+
+	out, err := uc.Void(context.Background(), 6, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != entity.OrderStatusVoided || out.Version != 2 {
+		t.Errorf("unexpected order %+v", out)
+	}
+
+	refunded := order
+	refunded.Status = entity.OrderStatusRefunded
+	orderRepo.EXPECT().GetById(gomock.Any(), 6).Return(refunded, nil)
+	if _, err := uc.Void(context.Background(), 6, 0); !errors.Is(err, entity.ErrOrderNotEditable) {
+		t.Fatalf("expected ErrOrderNotEditable, got %v", err)
+	}
+}
+
+func Test_newOrderRequest(t *testing.T) {
+	order := entity.Order{
+		Latitude:        40.75,
+		Longitude:       -73.99,
+		Zip:             "10001",
+		GeocodingMethod: entity.GeocodingMethodZipCentroid,
+		TaxInclusive:    true,
+		Components: []entity.OrderComponentTax{
+			{Component: entity.OrderComponentSubtotal, Amount: 100, TaxAmount: 8},
+			{Component: entity.OrderComponentShipping, Amount: 9.26, TaxAmount: 0.74},
+			{Component: entity.OrderComponentDiscount, Amount: -10, TaxAmount: -0.8},
+		},
+	}
+
+	p := newOrderRequest(order)
+	if p.Latitude != 0 || p.Longitude != 0 || p.Zip != "10001" {
+		t.Errorf("expected order to be geocoded again, got %+v", p)
+	}
+	if math.Abs(p.Subtotal-108) > 1e-9 || math.Abs(p.Shipping-10) > 1e-9 || math.Abs(p.Discount-10.8) > 1e-9 || !p.TaxInclusive {
+		t.Errorf("expected gross amounts, got %+v", p)
+	}
+}
+
+func Test_newOrderLabels(t *testing.T) {
+	metadata, tags := newOrderLabels(nil, []string{" promo", "", "q3", "promo"})
+	if metadata == nil || len(metadata) != 0 {
+		t.Errorf("expected empty metadata, got %v", metadata)
+	}
+	if len(tags) != 2 || tags[0] != "promo" || tags[1] != "q3" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+
+	if _, tags := newOrderLabels(nil, nil); tags == nil {
+		t.Error("expected empty tags")
+	}
+}
+
+func TestRefund(t *testing.T) {
+	uc, _, orderRepo, _, _ := newTestUseCase(t)
+	order := entity.Order{Id: 7, TotalAmount: 108, TaxAmount: 8, Status: entity.OrderStatusCompleted, Version: 4}
+
+	t.Run("partial", func(t *testing.T) {
+		orderRepo.EXPECT().GetById(gomock.Any(), 7).Return(order, nil)
+		orderRepo.EXPECT().CreateRefund(gomock.Any(), gomock.Any(), 4, entity.OrderStatusPartiallyRefunded, gomock.Any()).
+			DoAndReturn(func(_ context.Context, refund entity.OrderRefund, _ int, _ entity.OrderStatus, e entity.OrderEvent) (int, error) {
+				if refund.OrderId != 7 || refund.Amount != 25 || refund.TaxAmount != 2 || refund.Reason != "damaged" {
+					t.Errorf("unexpected refund %+v", refund)
+				}
+				if e.Type != entity.OrderEventRefunded || e.After.RefundedAmount != 25 || len(e.After.Refunds) != 1 || e.After.Version != 5 {
+					t.Errorf("unexpected event %+v", e)
+				}
+				return 3, nil
+			})
+
+		refund, err := uc.Refund(context.Background(), 7, dto.OrderRefund{Amount: 25, Reason: "damaged"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if refund.Id != 3 || refund.CreatedAt.IsZero() {
+			t.Errorf("unexpected refund %+v", refund)
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		orderRepo.EXPECT().GetById(gomock.Any(), 7).Return(order, nil)
+		orderRepo.EXPECT().CreateRefund(gomock.Any(), gomock.Any(), gomock.Any(), entity.OrderStatusRefunded, gomock.Any()).Return(4, nil)
+
+		refund, err := uc.Refund(context.Background(), 7, dto.OrderRefund{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if refund.Amount != 100 || refund.TaxAmount != 8 {
+			t.Errorf("unexpected refund %+v", refund)
+		}
+	})
+
+	t.Run("exceeds", func(t *testing.T) {
+		orderRepo.EXPECT().GetById(gomock.Any(), 7).Return(order, nil)
+
+		if _, err := uc.Refund(context.Background(), 7, dto.OrderRefund{Amount: 150}); !errors.Is(err, entity.ErrRefundExceedsOrder) {
+			t.Fatalf("expected ErrRefundExceedsOrder, got %v", err)
+		}
+	})
+
+	t.Run("changed meanwhile", func(t *testing.T) {
+		orderRepo.EXPECT().GetById(gomock.Any(), 7).Return(order, nil)
+		orderRepo.EXPECT().CreateRefund(gomock.Any(), gomock.Any(), 4, entity.OrderStatusRefunded, gomock.Any()).Return(0, entity.ErrOrderVersionConflict)
+
+		if _, err := uc.Refund(context.Background(), 7, dto.OrderRefund{}); !errors.Is(err, entity.ErrOrderVersionConflict) {
+			t.Fatalf("expected ErrOrderVersionConflict, got %v", err)
+		}
+	})
+
+	t.Run("voided", func(t *testing.T) {
+		voided := order
+		voided.Status = entity.OrderStatusVoided
+		orderRepo.EXPECT().GetById(gomock.Any(), 7).Return(voided, nil)
+
+		if _, err := uc.Refund(context.Background(), 7, dto.OrderRefund{}); !errors.Is(err, entity.ErrOrderNotRefundable) {
+			t.Fatalf("expected ErrOrderNotRefundable, got %v", err)
+		}
+	})
+
+	t.Run("out of scope", func(t *testing.T) {
+		outOfScope := entity.Order{Id: 7, TotalAmount: 100, Status: entity.OrderStatusOutOfScope, Version: 4}
+		orderRepo.EXPECT().GetById(gomock.Any(), 7).Return(outOfScope, nil)
+
+		if _, err := uc.Refund(context.Background(), 7, dto.OrderRefund{Amount: 10}); !errors.Is(err, entity.ErrOrderNotRefundable) {
+			t.Fatalf("expected ErrOrderNotRefundable, got %v", err)
+		}
+	})
+
+	t.Run("lines at their rates", func(t *testing.T) {
+		itemized := order
+		itemized.TotalAmount, itemized.TaxAmount = 117, 7
+		itemized.Components = []entity.OrderComponentTax{
+			{Component: entity.OrderComponentSubtotal, Amount: 100, TaxableAmount: 60, TaxRate: 0.06, TaxAmount: 6},
+			{Component: entity.OrderComponentShipping, Amount: 10, TaxableAmount: 10, TaxRate: 0.1, TaxAmount: 1},
+		}
+		itemized.Items = []entity.OrderItem{{Line: 1, Amount: 60, TaxRate: 0.1, TaxAmount: 6}, {Line: 2, Amount: 40}}
+		orderRepo.EXPECT().GetById(gomock.Any(), 7).Return(itemized, nil)
+		orderRepo.EXPECT().CreateRefund(gomock.Any(), gomock.Any(), 4, entity.OrderStatusPartiallyRefunded, gomock.Any()).Return(5, nil)
+
+		refund, err := uc.Refund(context.Background(), 7, dto.OrderRefund{Lines: []dto.OrderRefundLine{
+			{Component: "subtotal", Line: 2},
+			{Component: "shipping", Amount: 5},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// the exempt item reverses no tax and shipping its own 10%
+		if refund.Amount != 45 || refund.TaxAmount != 0.5 || len(refund.Lines) != 2 {
+			t.Errorf("unexpected refund %+v", refund)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		orderRepo.EXPECT().GetById(gomock.Any(), 8).Return(entity.Order{}, entity.ErrOrderNotFound)
+
+		if _, err := uc.Refund(context.Background(), 8, dto.OrderRefund{}); !errors.Is(err, entity.ErrOrderNotFound) {
+			t.Fatalf("expected ErrOrderNotFound, got %v", err)
+		}
+	})
+}
+
+func TestHistory(t *testing.T) {
+	uc, _, orderRepo, _, _, eventRepo := newTestUseCaseWithEvents(t)
+	actor := entity.Actor{Key: "0a1b2c3d4e5f", User: "alice"}
+	ctx := entity.ContextWithActor(context.Background(), actor)
+
+	t.Run("void is recorded", func(t *testing.T) {
+		order := entity.Order{Id: 9, Status: entity.OrderStatusCompleted, Version: 1}
+		orderRepo.EXPECT().GetById(gomock.Any(), 9).Return(order, nil)
+		orderRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ entity.Order, e entity.OrderEvent) error {
+			if e.OrderId != 9 || e.Type != entity.OrderEventVoided || e.Actor != actor || e.Source != entity.OrderEventSourceApi {
+				t.Errorf("unexpected event %+v", e)
+			}
+			if e.Before.Status != entity.OrderStatusCompleted || e.After.Status != entity.OrderStatusVoided || e.After.Version != 2 {
+				t.Errorf("unexpected snapshots %+v, %+v", e.Before, e.After)
+			}
+			return nil
+		})
+
+		if _, err := uc.Void(ctx, 9, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("failed recording fails the change", func(t *testing.T) {
+		order := entity.Order{Id: 9, TotalAmount: 108, TaxAmount: 8, Status: entity.OrderStatusCompleted}
+		orderRepo.EXPECT().GetById(gomock.Any(), 9).Return(order, nil)
+		orderRepo.EXPECT().CreateRefund(gomock.Any(), gomock.Any(), gomock.Any(), entity.OrderStatusRefunded, gomock.Any()).Return(0, errors.New("db down"))
+
+		if _, err := uc.Refund(ctx, 9, dto.OrderRefund{}); err == nil {
+			t.Fatal("expected the refund to fail")
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		eventRepo.EXPECT().GetByOrderId(gomock.Any(), 9).Return([]entity.OrderEvent{{Id: 1, OrderId: 9}}, nil)
+
+		events, err := uc.History(ctx, 9)
+		if err != nil || len(events) != 1 {
+			t.Fatalf("unexpected history %+v, %v", events, err)
+		}
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		eventRepo.EXPECT().GetByOrderId(gomock.Any(), 10).Return([]entity.OrderEvent{}, nil)
+		orderRepo.EXPECT().GetById(gomock.Any(), 10).Return(entity.Order{}, entity.ErrOrderNotFound)
+
+		if _, err := uc.History(ctx, 10); !errors.Is(err, entity.ErrOrderNotFound) {
+			t.Fatalf("expected ErrOrderNotFound, got %v", err)
+		}
+	})
+}
+
+func TestPassthroughMethods(t *testing.T) {
+	uc, _, orderRepo, _, _ := newTestUseCase(t)
+
+	orderRepo.EXPECT().GetById(gomock.Any(), 42).Return(entity.Order{Id: 42}, nil)
+	if _, err := uc.GetById(context.Background(), 42); err != nil {
+		t.Fatal(err)
+	}
+
+	orderRepo.EXPECT().GetAll(gomock.Any(), dto.OrderFilters{Limit: 1, Offset: 0}).Return(entity.OrderList{}, nil)
+	if _, err := uc.GetAll(context.Background(), dto.OrderFilters{Limit: 1, Offset: 0}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	uc, _, orderRepo, _, _ := newTestUseCase(t)
+	actor := entity.Actor{Key: "0a1b2c3d4e5f", User: "alice"}
+	ctx := entity.ContextWithActor(context.Background(), actor)
+
+	t.Run("scope required", func(t *testing.T) {
+		_, err := uc.Delete(ctx, dto.OrderFilters{Limit: 10, SortBy: "id"})
+		if !errors.Is(err, entity.ErrDeletionScopeRequired) {
+			t.Fatalf("expected ErrDeletionScopeRequired, got %v", err)
+		}
+	})
+
+	t.Run("filter matching every order", func(t *testing.T) {
+		zero := 0.0
+		_, err := uc.Delete(ctx, dto.OrderFilters{TotalAmountMin: &zero, Statuses: []string{"completed", "out_of_scope"}})
+		if !errors.Is(err, entity.ErrDeletionScopeRequired) {
+			t.Fatalf("expected ErrDeletionScopeRequired, got %v", err)
+		}
+	})
+
+	t.Run("deleted by import", func(t *testing.T) {
+		filter := dto.OrderFilters{ImportId: "abc"}
+		orderRepo.EXPECT().SoftDelete(gomock.Any(), filter, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ dto.OrderFilters, _ time.Time, e entity.OrderEvent) ([]int, error) {
+				if e.Type != entity.OrderEventDeleted || e.Actor != actor || e.Before != nil || e.After != nil {
+					t.Errorf("unexpected event %+v", e)
+				}
+				return []int{3, 4}, nil
+			})
+
+		before := time.Now()
+		deletion, err := uc.Delete(ctx, filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deletion.Deleted != 2 || deletion.PurgeAt.Before(before.Add(time.Hour)) {
+			t.Errorf("unexpected deletion %+v", deletion)
+		}
+	})
+
+	t.Run("nothing matched", func(t *testing.T) {
+		orderRepo.EXPECT().SoftDelete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		deletion, err := uc.Delete(ctx, dto.OrderFilters{Ids: []int{99}})
+		if err != nil || deletion.Deleted != 0 {
+			t.Fatalf("unexpected deletion %+v, %v", deletion, err)
+		}
+	})
+
+	t.Run("restore requires scope", func(t *testing.T) {
+		if _, err := uc.Restore(ctx, dto.OrderFilters{Limit: 10}); !errors.Is(err, entity.ErrDeletionScopeRequired) {
+			t.Fatalf("expected ErrDeletionScopeRequired, got %v", err)
+		}
+	})
+
+	t.Run("restored", func(t *testing.T) {
+		orderRepo.EXPECT().Restore(gomock.Any(), dto.OrderFilters{ImportId: "imp-1"}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ dto.OrderFilters, e entity.OrderEvent) ([]int, error) {
+				if e.Type != entity.OrderEventRestored {
+					t.Errorf("unexpected event %+v", e)
+				}
+				return []int{3}, nil
+			})
+
+		restoration, err := uc.Restore(ctx, dto.OrderFilters{ImportId: "imp-1"})
+		if err != nil || restoration.Restored != 1 {
+			t.Fatalf("unexpected restoration %+v, %v", restoration, err)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		before := time.Now().Add(-time.Hour)
+		orderRepo.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, cutoff time.Time) (int, error) {
+			if cutoff.Before(before) || cutoff.After(time.Now().Add(-time.Hour)) {
+				t.Errorf("unexpected cutoff %v", cutoff)
+			}
+			return 5, nil
+		})
+
+		if purged, err := uc.PurgeDeleted(ctx); err != nil || purged != 5 {
+			t.Fatalf("unexpected purge %d, %v", purged, err)
+		}
+	})
+}
+
+func TestDeleteAll(t *testing.T) {
+	uc, _, orderRepo, _, _ := newTestUseCase(t)
+	ctx := context.Background()
+
+	t.Run("without confirmation", func(t *testing.T) {
+		if err := uc.DeleteAll(ctx, ""); !errors.Is(err, entity.ErrInvalidConfirmationToken) {
+			t.Fatalf("expected ErrInvalidConfirmationToken, got %v", err)
+		}
+	})
+
+	t.Run("stores hashed token", func(t *testing.T) {
+		var stored string
+		orderRepo.EXPECT().CreateWipeConfirmation(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, tokenHash string, expiresAt time.Time) error {
+				if until := time.Until(expiresAt); until <= 0 || until > wipeConfirmationTTL {
+					t.Errorf("unexpected expiry %v", expiresAt)
+				}
+				stored = tokenHash
+				return nil
+			})
+
+		confirmation, err := uc.RequestWipe(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stored == confirmation.Token || stored != wipeTokenHash(confirmation.Token) {
+			t.Errorf("stored %q, want the hash of the token", stored)
+		}
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		orderRepo.EXPECT().DeleteAll(gomock.Any(), wipeTokenHash("token"), gomock.Any()).Return(nil)
+		if err := uc.DeleteAll(ctx, "token"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejected by storage", func(t *testing.T) {
+		// superseded, expired and used tokens are rejected by the storage
+		orderRepo.EXPECT().DeleteAll(gomock.Any(), wipeTokenHash("old"), gomock.Any()).Return(entity.ErrInvalidConfirmationToken)
+		if err := uc.DeleteAll(ctx, "old"); !errors.Is(err, entity.ErrInvalidConfirmationToken) {
+			t.Fatalf("expected ErrInvalidConfirmationToken, got %v", err)
+		}
+	})
+}
+
+func TestDeleteAllKeepsHistory(t *testing.T) {
+	uc, _, orderRepo, _, _, eventRepo := newTestUseCaseWithEvents(t)
+	ctx := context.Background()
+
+	// the wipe leaves the history alone, and the history of a wiped order
+	// is still served, since the ids of wiped orders are never reused
+	orderRepo.EXPECT().DeleteAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	if err := uc.DeleteAll(ctx, "token"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := []entity.OrderEvent{{OrderId: 5, Type: entity.OrderEventCreated}, {OrderId: 5, Type: entity.OrderEventVoided}}
+	eventRepo.EXPECT().GetByOrderId(gomock.Any(), 5).Return(events, nil)
+
+	got, err := uc.History(ctx, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[1].Type != entity.OrderEventVoided {
+		t.Errorf("unexpected history %+v", got)
+	}
+}
+
+func Test_mapCSVToEntity(t *testing.T) {
+	uc, _, _, _, _ := newTestUseCase(t)
+
+	t.Run("invalid columns", func(t *testing.T) {
+		_, err := uc.mapCSVToEntity([]string{"a", "b", "c"})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("valid row", func(t *testing.T) {
+		row := []string{"1", "10.1", "20.2", "2023-01-01 00:00:00.000000000", "15.5"}
+		d, err := uc.mapCSVToEntity(row)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Latitude != 20.2 || d.Longitude != 10.1 || d.Subtotal != 15.5 {
+			t.Error("parsed values mismatch")
+		}
+	})
+
+	t.Run("valid row with category", func(t *testing.T) {
+		row := []string{"1", "10.1", "20.2", "2023-01-01 00:00:00.000000000", "15.5", " grocery "}
+		d, err := uc.mapCSVToEntity(row)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Category != "grocery" {
+			t.Errorf("wrong category %q", d.Category)
+		}
+	})
+
+	t.Run("valid row with customer reference", func(t *testing.T) {
+		row := []string{"1", "10.1", "20.2", "2023-01-01 00:00:00.000000000", "15.5", "", "acme"}
+		d, err := uc.mapCSVToEntity(row)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Category != "" || d.CustomerRef != "acme" {
+			t.Errorf("wrong optional columns %q %q", d.Category, d.CustomerRef)
+		}
+	})
+
+	t.Run("valid row with currency", func(t *testing.T) {
+		row := []string{"1", "10.1", "20.2", "2023-01-01 00:00:00.000000000", "15.5", "", "", "", "", "", "", "", " EUR "}
+		d, err := uc.mapCSVToEntity(row)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Currency != "EUR" {
+			t.Errorf("wrong currency %q", d.Currency)
+		}
+	})
+
+	t.Run("valid row with metadata and tags", func(t *testing.T) {
+		row := []string{"1", "10.1", "20.2", "2023-01-01 00:00:00.000000000", "15.5", "", "", "", "", "", "", "", "", `{"channel":"web"}`, `["promo"]`}
+		d, err := uc.mapCSVToEntity(row)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Metadata["channel"] != "web" || len(d.Tags) != 1 || d.Tags[0] != "promo" {
+			t.Errorf("wrong labels %v %v", d.Metadata, d.Tags)
+		}
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		row := []string{"1", "10.1", "20.2", "2023-01-01 00:00:00.000000000", "15.5", "", "", "", "", "", "", "", "", `["web"]`}
+		if _, err := uc.mapCSVToEntity(row); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("address without coordinates", func(t *testing.T) {
+		rec := []string{"1", "", "", "2025-01-01 10:00:00", "10.5", "", "", "1 Main St, Albany, NY 12207"}
+		o, err := uc.mapCSVToEntity(rec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if o.Address != "1 Main St, Albany, NY 12207" || o.Latitude != 0 || o.Longitude != 0 {
+			t.Errorf("unexpected order %+v", o)
+		}
+	})
+
+	t.Run("shipping handling and discount", func(t *testing.T) {
+		rec := []string{"1", "-74", "40.7", "2025-01-01 10:00:00", "100", "", "", "", "9.99", "", "5"}
+		o, err := uc.mapCSVToEntity(rec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if o.Shipping != 9.99 || o.Handling != 0 || o.Discount != 5 {
+			t.Errorf("unexpected amounts %+v", o)
+		}
+	})
+
+	t.Run("items", func(t *testing.T) {
+		rec := []string{"1", "-74", "40.7", "2025-01-01 10:00:00", "", "", "", "", "", "", "", `[{"sku":"A1","quantity":2,"unit_price":9.5,"category":"clothing"}]`}
+		o, err := uc.mapCSVToEntity(rec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(o.Items) != 1 || o.Items[0].SKU != "A1" || o.Items[0].Quantity != 2 || o.Items[0].UnitPrice != 9.5 {
+			t.Errorf("unexpected items %+v", o.Items)
+		}
+	})
+
+	t.Run("invalid item", func(t *testing.T) {
+		rec := []string{"1", "-74", "40.7", "2025-01-01 10:00:00", "", "", "", "", "", "", "", `[{"sku":"A1","quantity":0,"unit_price":9.5}]`}
+		if _, err := uc.mapCSVToEntity(rec); err == nil {
+			t.Fatal("expected error for item without quantity")
+		}
+	})
+
+	t.Run("negative discount", func(t *testing.T) {
+		rec := []string{"1", "-74", "40.7", "2025-01-01 10:00:00", "100", "", "", "", "", "", "-5"}
+		if _, err := uc.mapCSVToEntity(rec); err == nil {
+			t.Fatal("expected error for negative discount")
+		}
+	})
+
+	t.Run("invalid longitude", func(t *testing.T) {
+		row := []string{"1", "bad", "20.2", "2023-01-01 00:00:00.000000000", "15.5"}
+		if _, err := uc.mapCSVToEntity(row); err == nil {
+			t.Fatal("expected parse error for longitude")
+		}
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		row := []string{"1", "10.1", "20.2", "bad-time", "15.5"}
+		if _, err := uc.mapCSVToEntity(row); err == nil {
+			t.Fatal("expected parse error for timestamp")
+		}
+	})
+}
+
+func TestAsyncBatchCreate(t *testing.T) {
+	uc, taxRepo, orderRepo, _, _ := newTestUseCase(t)
+
+	// create CSV with two records; first returns tax, second missing
+	csvData := strings.Join([]string{
+		"1,30.0,50.0,2023-01-01 00:00:00.000000000,10.0",
+		"2,40.0,60.0,2023-01-02 00:00:00.000000000,20.0",
+	}, "\n")
+	src := io.NopCloser(strings.NewReader(csvData))
+	reader := csv.NewReader(src)
+
+	// expectations: every batch (batch size == 1) is inserted as pending,
+	// then its tax is resolved and the order updated
+	gomock.InOrder(
+		orderRepo.EXPECT().BatchCreate(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(ctx interface{}, orders interface{}, event interface{}) {
+			o := orders.([]entity.Order)
+			if len(o) != 1 {
+				t.Errorf("expected batch size 1, got %d", len(o))
+			}
+			if !o[0].TaxInclusive || o[0].Status != entity.OrderStatusPending || o[0].ImportId != "imp-1" {
+				t.Errorf("expected pending tax inclusive order from import, got %+v", o[0])
+			}
+			if e := event.(entity.OrderEvent); e.Type != entity.OrderEventImported || e.Source != entity.OrderEventSourceImport || e.SourceId != "imp-1" {
+				t.Errorf("unexpected event %+v", e)
+			}
+			o[0].Id = 1
+		}),
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 50.0, 30.0).
+			Return(&entity.LocationTax{JurisdictionTax: entity.JurisdictionTax{CompositeRate: 0.1, Names: []string{"A"}, Code: "A"}}, true),
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false),
+		orderRepo.EXPECT().BatchUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, o []entity.Order, e []entity.OrderEvent) {
+			if len(o) != 1 || o[0].Id != 1 || o[0].Version != 1 || o[0].Status != entity.OrderStatusCompleted || o[0].ImportId != "imp-1" {
+				t.Errorf("unexpected resolved orders %+v", o)
+			}
+			if len(o[0].StatusTransitions) != 1 || o[0].StatusTransitions[0].From != entity.OrderStatusPending {
+				t.Errorf("unexpected status transitions %+v", o[0].StatusTransitions)
+			}
+			if len(e) != 1 || e[0].OrderId != 1 || e[0].Type != entity.OrderEventResolved || e[0].SourceId != "imp-1" ||
+				e[0].Before.Status != entity.OrderStatusPending || e[0].After.Version != 2 {
+				t.Errorf("unexpected events %+v", e)
+			}
+		}),
+		orderRepo.EXPECT().BatchCreate(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(ctx interface{}, orders interface{}, event interface{}) {
+			orders.([]entity.Order)[0].Id = 2
+		}),
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 60.0, 40.0).
+			Return(nil, false),
+		orderRepo.EXPECT().BatchUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, o []entity.Order, _ []entity.OrderEvent) {
+			if o[0].Id != 2 || o[0].Status != entity.OrderStatusOutOfScope {
+				t.Errorf("expected out_of_scope status")
+			}
+		}),
+	)
+
+	uc.AsyncBatchCreate(reader, src, dto.OrderImport{Id: "imp-1", TaxInclusive: true})
+}
+
+func TestImportBatch(t *testing.T) {
+	uc, taxRepo, orderRepo, exemptionRepo, _ := newTestUseCase(t)
+	ts := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+
+	// the certificates of the first order fail to load, the second one resolves
+	orders := []entity.Order{
+		{Latitude: 1, Longitude: 2, Subtotal: 10, TotalAmount: 10, CustomerRef: "acme", CreatedAt: ts, Status: entity.OrderStatusPending, Version: 1, Currency: entity.ReportingCurrency, ExchangeRate: 1},
+		{Latitude: 3, Longitude: 4, Subtotal: 20, TotalAmount: 20, CreatedAt: ts, Status: entity.OrderStatusPending, Version: 1, Currency: entity.ReportingCurrency, ExchangeRate: 1},
+	}
+
+	gomock.InOrder(
+		orderRepo.EXPECT().BatchCreate(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, o []entity.Order, _ entity.OrderEvent) {
+			o[0].Id, o[1].Id = 1, 2
+		}),
+		exemptionRepo.EXPECT().GetByCustomerRef(gomock.Any(), "acme").Return(nil, errors.New("boom")),
+		taxRepo.EXPECT().GetTaxByLocation(gomock.Any(), 3.0, 4.0).
+			Return(&entity.LocationTax{JurisdictionTax: entity.JurisdictionTax{CompositeRate: 0.1, Code: "A"}}, true),
+		taxRepo.EXPECT().GetOverride(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false),
+		orderRepo.EXPECT().BatchUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, o []entity.Order, e []entity.OrderEvent) {
+			if len(o) != 2 || len(e) != 2 {
+				t.Fatalf("expected both orders to be stored, got %+v", o)
+			}
+			if o[0].Id != 1 || o[0].Status != entity.OrderStatusFailedTaxResolution || len(o[0].StatusTransitions) != 1 {
+				t.Errorf("expected the first order to fail tax resolution, got %+v", o[0])
+			}
+			if o[1].Id != 2 || o[1].Status != entity.OrderStatusCompleted {
+				t.Errorf("expected the second order to be completed, got %+v", o[1])
+			}
+			if e[0].Type != entity.OrderEventResolved || e[0].After.Status != entity.OrderStatusFailedTaxResolution || e[0].After.Version != 2 {
+				t.Errorf("unexpected event %+v", e[0])
+			}
+		}),
+	)
+
+	if err := uc.importBatch(context.Background(), orders, dto.OrderImport{Id: "imp-1"}, make(map[string][]entity.ExemptionCertificate)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
 
-	uc.AsyncBatchCreate(reader, src)
+func ptr[T any](v T) *T {
+	return &v
 }
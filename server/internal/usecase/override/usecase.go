@@ -0,0 +1,105 @@
+package override
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+
+	"github.com/rs/zerolog"
+)
+
+// UseCase implements business logic for temporary tax overrides.
+// Overrides come from the tax config and from the admin API;
+// both are merged and handed to the tax engine, with API overrides
+// taking precedence over config ones. Every override applies to the
+// jurisdictions of its own state.
+type UseCase struct {
+	activeRepo      repo.ActiveTaxOverrideRepo
+	overrideRepo    repo.TaxOverrideRepo
+	configOverrides []entity.TaxOverride
+	logger          zerolog.Logger
+}
+
+func New(
+	activeRepo repo.ActiveTaxOverrideRepo,
+	overrideRepo repo.TaxOverrideRepo,
+	configOverrides []entity.TaxOverride,
+	logger zerolog.Logger,
+) *UseCase {
+	l := logger.With().Str("usecase", "override").Logger()
+	return &UseCase{
+		activeRepo:      activeRepo,
+		overrideRepo:    overrideRepo,
+		configOverrides: configOverrides,
+		logger:          l,
+	}
+}
+
+// Load reads the stored overrides and makes them, together with
+// the config overrides, the ones evaluated by the tax engine.
+func (uc *UseCase) Load(ctx context.Context) error {
+	stored, err := uc.overrideRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tax overrides: %w", err)
+	}
+
+	overrides := make([]entity.TaxOverride, 0, len(stored)+len(uc.configOverrides))
+	overrides = append(overrides, stored...)
+	overrides = append(overrides, uc.configOverrides...)
+
+	uc.activeRepo.ReplaceOverrides(ctx, overrides)
+	uc.logger.Info().Int("stored", len(stored)).Int("config", len(uc.configOverrides)).Msg("loaded tax overrides")
+
+	return nil
+}
+
+// Create validates and stores an override and reloads the active ones.
+// An override of a state that is not configured is a bad request.
+func (uc *UseCase) Create(ctx context.Context, overrideDto dto.TaxOverride) (entity.TaxOverride, error) {
+	override := entity.TaxOverride{
+		Name:          overrideDto.Name,
+		State:         strings.ToUpper(strings.TrimSpace(overrideDto.State)),
+		Jurisdictions: overrideDto.Jurisdictions,
+		Category:      overrideDto.Category,
+		Components:    overrideDto.Components,
+		Rate:          overrideDto.Rate,
+		StartsAt:      overrideDto.StartsAt,
+		EndsAt:        overrideDto.EndsAt,
+		Source:        entity.TaxOverrideSourceApi,
+	}
+	if override.Components == nil {
+		override.Components = []entity.TaxLayerLevel{}
+	}
+	if !override.Validate() || !uc.activeRepo.HasState(ctx, override.State) {
+		return entity.TaxOverride{}, entity.ErrBadRequest
+	}
+
+	created, err := uc.overrideRepo.Create(ctx, override)
+	if err != nil {
+		return entity.TaxOverride{}, fmt.Errorf("failed to create tax override: %w", err)
+	}
+
+	if err := uc.Load(ctx); err != nil {
+		return entity.TaxOverride{}, err
+	}
+	return created, nil
+}
+
+// GetAll returns the overrides currently evaluated by the tax engine
+// in the order of their precedence.
+func (uc *UseCase) GetAll(ctx context.Context) []entity.TaxOverride {
+	return uc.activeRepo.GetActiveOverrides(ctx)
+}
+
+// Delete removes a stored override and reloads the active ones.
+// Config overrides cannot be deleted through the API.
+func (uc *UseCase) Delete(ctx context.Context, id int) error {
+	if err := uc.overrideRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete tax override: %w", err)
+	}
+	return uc.Load(ctx)
+}
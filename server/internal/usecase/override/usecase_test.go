@@ -0,0 +1,111 @@
+package override
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ryl1k/INT20H-test-task-server/internal/entity"
+	"github.com/ryl1k/INT20H-test-task-server/internal/repo/dto"
+	repomocks "github.com/ryl1k/INT20H-test-task-server/internal/repo/mocks"
+
+	"github.com/rs/zerolog"
+)
+
+var configOverride = entity.TaxOverride{Name: "config", Source: entity.TaxOverrideSourceConfig}
+
+func newTestUseCase(t *testing.T) (*UseCase, *repomocks.MockActiveTaxOverrideRepo, *repomocks.MockTaxOverrideRepo) {
+	ctrl := gomock.NewController(t)
+	activeRepo := repomocks.NewMockActiveTaxOverrideRepo(ctrl)
+	overrideRepo := repomocks.NewMockTaxOverrideRepo(ctrl)
+	return New(activeRepo, overrideRepo, []entity.TaxOverride{configOverride}, zerolog.Nop()), activeRepo, overrideRepo
+}
+
+func TestLoad(t *testing.T) {
+	uc, activeRepo, overrideRepo := newTestUseCase(t)
+
+	stored := entity.TaxOverride{Id: 1, Name: "stored", Source: entity.TaxOverrideSourceApi}
+	overrideRepo.EXPECT().GetAll(gomock.Any()).Return([]entity.TaxOverride{stored}, nil)
+	activeRepo.EXPECT().ReplaceOverrides(gomock.Any(), []entity.TaxOverride{stored, configOverride})
+
+	if err := uc.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	start := time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)
+
+	t.Run("created", func(t *testing.T) {
+		uc, activeRepo, overrideRepo := newTestUseCase(t)
+
+		gomock.InOrder(
+			activeRepo.EXPECT().HasState(gomock.Any(), "NJ").Return(true),
+			overrideRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, o entity.TaxOverride) (entity.TaxOverride, error) {
+					o.Id = 2
+					return o, nil
+				}),
+			overrideRepo.EXPECT().GetAll(gomock.Any()).Return(nil, nil),
+			activeRepo.EXPECT().ReplaceOverrides(gomock.Any(), gomock.Len(1)),
+		)
+
+		o, err := uc.Create(context.Background(), dto.TaxOverride{
+			Name:          "holiday",
+			State:         " nj",
+			Jurisdictions: []string{entity.AllJurisdictions},
+			StartsAt:      start,
+			EndsAt:        start.AddDate(0, 0, 3),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if o.Id != 2 || o.State != "NJ" || o.Source != entity.TaxOverrideSourceApi {
+			t.Errorf("unexpected override %+v", o)
+		}
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		uc, activeRepo, _ := newTestUseCase(t)
+
+		activeRepo.EXPECT().HasState(gomock.Any(), "CA").Return(false)
+
+		_, err := uc.Create(context.Background(), dto.TaxOverride{
+			Name:          "holiday",
+			State:         "CA",
+			Jurisdictions: []string{entity.AllJurisdictions},
+			StartsAt:      start,
+			EndsAt:        start.AddDate(0, 0, 3),
+		})
+		if !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		uc, _, _ := newTestUseCase(t)
+
+		_, err := uc.Create(context.Background(), dto.TaxOverride{
+			Name:          "holiday",
+			Jurisdictions: []string{entity.AllJurisdictions},
+			StartsAt:      start,
+			EndsAt:        start,
+		})
+		if !errors.Is(err, entity.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	uc, _, overrideRepo := newTestUseCase(t)
+
+	overrideRepo.EXPECT().Delete(gomock.Any(), 7).Return(entity.ErrTaxOverrideNotFound)
+
+	if err := uc.Delete(context.Background(), 7); !errors.Is(err, entity.ErrTaxOverrideNotFound) {
+		t.Fatalf("expected ErrTaxOverrideNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE boundary_sets;
+DROP TYPE boundary_set_status;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TYPE "boundary_set_status" AS ENUM('staged','active','superseded');
+
+CREATE TABLE "boundary_sets" (
+    "id" BIGSERIAL PRIMARY KEY,
+
+    "status" boundary_set_status NOT NULL DEFAULT 'staged',
+    "feature_count" INTEGER NOT NULL,
+
+    "geojson" JSONB NOT NULL,
+    "report" JSONB NOT NULL,
+    "diff" JSONB NOT NULL,
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "activated_at" TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_boundary_sets_single_active ON boundary_sets (status) WHERE status = 'active';
\ No newline at end of file
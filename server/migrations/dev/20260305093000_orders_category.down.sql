@@ -0,0 +1,3 @@
+DROP INDEX idx_orders_category;
+
+ALTER TABLE orders DROP COLUMN "category";
\ No newline at end of file
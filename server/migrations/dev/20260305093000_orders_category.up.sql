@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN "category" VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_orders_category ON orders (category);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP INDEX idx_orders_customer_ref;
+
+ALTER TABLE orders DROP COLUMN "exemption_certificate";
+ALTER TABLE orders DROP COLUMN "customer_ref";
+
+DROP TABLE exemption_certificates;
+DROP TABLE customers;
\ No newline at end of file
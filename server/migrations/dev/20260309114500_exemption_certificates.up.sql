@@ -0,0 +1,34 @@
+CREATE TABLE "customers" (
+    "id" BIGSERIAL PRIMARY KEY,
+
+    "external_ref" VARCHAR(128) NOT NULL UNIQUE,
+    "name" VARCHAR(255) NOT NULL DEFAULT '',
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE "exemption_certificates" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "customer_id" BIGINT NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+
+    "number" VARCHAR(64) NOT NULL,
+    "type" VARCHAR(32) NOT NULL,
+
+    "jurisdictions" JSONB NOT NULL DEFAULT '[]',
+    "components" JSONB NOT NULL DEFAULT '[]',
+
+    "valid_from" TIMESTAMPTZ NOT NULL,
+    "valid_to" TIMESTAMPTZ,
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE (customer_id, number)
+);
+
+CREATE INDEX idx_exemption_certificates_customer_id ON exemption_certificates (customer_id);
+
+ALTER TABLE orders ADD COLUMN "customer_ref" VARCHAR(128) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN "exemption_certificate" VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_orders_customer_ref ON orders (customer_ref);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX idx_orders_tax_override;
+
+ALTER TABLE orders DROP COLUMN "tax_override";
+
+DROP TABLE tax_overrides;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE "tax_overrides" (
+    "id" BIGSERIAL PRIMARY KEY,
+
+    "name" VARCHAR(128) NOT NULL UNIQUE,
+    "jurisdictions" JSONB NOT NULL DEFAULT '[]',
+    "category" VARCHAR(64) NOT NULL DEFAULT '',
+    "components" JSONB NOT NULL DEFAULT '[]',
+    "rate" NUMERIC(36, 18),
+
+    "starts_at" TIMESTAMPTZ NOT NULL,
+    "ends_at" TIMESTAMPTZ NOT NULL,
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CHECK (ends_at > starts_at)
+);
+
+ALTER TABLE orders ADD COLUMN "tax_override" VARCHAR(128) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_orders_tax_override ON orders (tax_override);
\ No newline at end of file
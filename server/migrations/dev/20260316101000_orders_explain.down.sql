@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN "explain";
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN "explain" JSONB;
\ No newline at end of file
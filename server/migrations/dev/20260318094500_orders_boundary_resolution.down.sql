@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_orders_boundary_resolved;
+
+ALTER TABLE orders DROP COLUMN "boundary_distance";
+ALTER TABLE orders DROP COLUMN "boundary_resolved";
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE orders ADD COLUMN "boundary_resolved" BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE orders ADD COLUMN "boundary_distance" NUMERIC(36, 18) NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_orders_boundary_resolved ON orders (boundary_resolved);
\ No newline at end of file
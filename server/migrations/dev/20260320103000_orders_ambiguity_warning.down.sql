@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_orders_warning;
+
+ALTER TABLE orders DROP COLUMN "ambiguous_jurisdictions";
+ALTER TABLE orders DROP COLUMN "warning";
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE orders ADD COLUMN "warning" VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN "ambiguous_jurisdictions" JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX idx_orders_warning ON orders (warning);
\ No newline at end of file
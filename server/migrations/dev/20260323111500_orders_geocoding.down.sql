@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_orders_geocoding_method;
+
+ALTER TABLE orders DROP COLUMN "geocoding_precision";
+ALTER TABLE orders DROP COLUMN "geocoding_method";
+ALTER TABLE orders DROP COLUMN "zip";
\ No newline at end of file
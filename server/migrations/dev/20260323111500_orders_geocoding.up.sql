@@ -0,0 +1,5 @@
+ALTER TABLE orders ADD COLUMN "zip" VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN "geocoding_method" VARCHAR(32) NOT NULL DEFAULT 'coordinates';
+ALTER TABLE orders ADD COLUMN "geocoding_precision" VARCHAR(16) NOT NULL DEFAULT 'exact';
+
+CREATE INDEX idx_orders_geocoding_method ON orders (geocoding_method);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_state;
+
+ALTER TABLE orders DROP COLUMN "state";
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE orders ADD COLUMN "state" VARCHAR(8) NOT NULL DEFAULT '';
+
+-- Orders taxed before multi-state support were all taxed in New York.
+UPDATE orders SET state = 'NY' WHERE status = 'completed';
+
+CREATE INDEX idx_orders_state ON orders (state);
\ No newline at end of file
@@ -0,0 +1,7 @@
+UPDATE orders SET total_amount = subtotal;
+
+ALTER TABLE orders DROP COLUMN "components";
+ALTER TABLE orders DROP COLUMN "discount";
+ALTER TABLE orders DROP COLUMN "handling";
+ALTER TABLE orders DROP COLUMN "shipping";
+ALTER TABLE orders DROP COLUMN "subtotal";
\ No newline at end of file
@@ -0,0 +1,17 @@
+ALTER TABLE orders ADD COLUMN "subtotal" NUMERIC(36, 18) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN "shipping" NUMERIC(36, 18) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN "handling" NUMERIC(36, 18) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN "discount" NUMERIC(36, 18) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN "components" JSONB NOT NULL DEFAULT '[]';
+
+-- total_amount used to hold the subtotal; it now holds the grand total including tax.
+UPDATE orders SET
+    subtotal = total_amount,
+    total_amount = total_amount + tax_amount,
+    components = jsonb_build_array(jsonb_build_object(
+        'component', 'subtotal',
+        'amount', total_amount,
+        'taxable_amount', CASE WHEN COALESCE(composite_tax_rate, 0) <> 0 THEN total_amount ELSE 0 END,
+        'tax_rate', COALESCE(composite_tax_rate, 0),
+        'tax_amount', tax_amount
+    ));
\ No newline at end of file
@@ -0,0 +1,23 @@
+CREATE TABLE "order_items" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "order_id" BIGINT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    "line" INT NOT NULL,
+
+    "sku" VARCHAR(64) NOT NULL DEFAULT '',
+    "description" VARCHAR(256) NOT NULL DEFAULT '',
+    "quantity" NUMERIC(36, 18) NOT NULL,
+    "unit_price" NUMERIC(36, 18) NOT NULL,
+    "category" VARCHAR(64) NOT NULL DEFAULT '',
+
+    "discount" NUMERIC(36, 18) NOT NULL DEFAULT 0,
+    "amount" NUMERIC(36, 18) NOT NULL,
+
+    "tax_override" VARCHAR(128) NOT NULL DEFAULT '',
+    "tax_rate" NUMERIC(36, 18) NOT NULL DEFAULT 0,
+    "tax_amount" NUMERIC(36, 18) NOT NULL DEFAULT 0,
+
+    UNIQUE (order_id, line)
+);
+
+CREATE INDEX idx_order_items_sku ON order_items (sku);
+CREATE INDEX idx_order_items_category ON order_items (category);
\ No newline at end of file
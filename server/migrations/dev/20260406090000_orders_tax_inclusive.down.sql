@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN "tax_inclusive";
\ No newline at end of file
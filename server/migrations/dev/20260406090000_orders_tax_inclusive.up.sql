@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN "tax_inclusive" BOOLEAN NOT NULL DEFAULT false;
\ No newline at end of file
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_order_refunds_order_id;
+DROP TABLE order_refunds;
+
+ALTER TABLE orders DROP COLUMN "net_tax_amount";
+ALTER TABLE orders DROP COLUMN "net_total_amount";
+ALTER TABLE orders DROP COLUMN "refunded_tax_amount";
+ALTER TABLE orders DROP COLUMN "refunded_amount";
+
+-- enum values cannot be dropped, so the type is recreated without them.
+UPDATE orders SET status = 'completed' WHERE status IN ('partially_refunded', 'refunded');
+ALTER TYPE order_status RENAME TO order_status_old;
+CREATE TYPE "order_status" AS ENUM('completed','out_of_scope');
+ALTER TABLE orders ALTER COLUMN status TYPE order_status USING status::text::order_status;
+DROP TYPE order_status_old;
\ No newline at end of file
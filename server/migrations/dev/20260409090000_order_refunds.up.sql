@@ -0,0 +1,20 @@
+ALTER TYPE order_status ADD VALUE 'partially_refunded';
+ALTER TYPE order_status ADD VALUE 'refunded';
+
+ALTER TABLE orders ADD COLUMN "refunded_amount" NUMERIC(36, 18) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN "refunded_tax_amount" NUMERIC(36, 18) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN "net_total_amount" NUMERIC(36, 18) GENERATED ALWAYS AS (total_amount - refunded_amount - refunded_tax_amount) STORED;
+ALTER TABLE orders ADD COLUMN "net_tax_amount" NUMERIC(36, 18) GENERATED ALWAYS AS (tax_amount - refunded_tax_amount) STORED;
+
+CREATE TABLE "order_refunds" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "order_id" BIGINT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+
+    "amount" NUMERIC(36, 18) NOT NULL,
+    "tax_amount" NUMERIC(36, 18) NOT NULL,
+    "reason" VARCHAR(256) NOT NULL DEFAULT '',
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_refunds_order_id ON order_refunds (order_id);
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE orders DROP COLUMN "version";
+
+-- enum values cannot be dropped, so the type is recreated without it.
+UPDATE orders SET status = 'completed' WHERE status = 'voided';
+ALTER TYPE order_status RENAME TO order_status_old;
+CREATE TYPE "order_status" AS ENUM('completed','out_of_scope','partially_refunded','refunded');
+ALTER TABLE orders ALTER COLUMN status TYPE order_status USING status::text::order_status;
+DROP TYPE order_status_old;
\ No newline at end of file
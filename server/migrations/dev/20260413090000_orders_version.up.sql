@@ -0,0 +1,3 @@
+ALTER TYPE order_status ADD VALUE 'voided';
+
+ALTER TABLE orders ADD COLUMN "version" INT NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP RULE IF EXISTS order_events_no_delete ON order_events;
+DROP RULE IF EXISTS order_events_no_update ON order_events;
+DROP INDEX IF EXISTS idx_order_events_source_id;
+DROP INDEX IF EXISTS idx_order_events_order_id;
+DROP TABLE order_events;
\ No newline at end of file
@@ -0,0 +1,23 @@
+CREATE TABLE "order_events" (
+    "id" BIGSERIAL PRIMARY KEY,
+    -- no foreign key, so the history outlives the order
+    "order_id" BIGINT NOT NULL,
+    "type" VARCHAR(32) NOT NULL,
+
+    "actor_key" VARCHAR(64) NOT NULL DEFAULT '',
+    "actor_user" VARCHAR(128) NOT NULL DEFAULT '',
+    "source" VARCHAR(32) NOT NULL,
+    "source_id" VARCHAR(64) NOT NULL DEFAULT '',
+
+    "before" JSONB,
+    "after" JSONB,
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_events_order_id ON order_events (order_id, id);
+CREATE INDEX idx_order_events_source_id ON order_events (source_id) WHERE source_id <> '';
+
+-- the history is append-only
+CREATE RULE order_events_no_update AS ON UPDATE TO order_events DO INSTEAD NOTHING;
+CREATE RULE order_events_no_delete AS ON DELETE TO order_events DO INSTEAD NOTHING;
\ No newline at end of file
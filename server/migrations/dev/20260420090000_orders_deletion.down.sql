@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_orders_import_id;
+DROP INDEX IF EXISTS idx_orders_deleted_at;
+
+ALTER TABLE orders DROP COLUMN "import_id";
+ALTER TABLE orders DROP COLUMN "deleted_at";
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE orders ADD COLUMN "deleted_at" TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN "import_id" VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_orders_deleted_at ON orders (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_orders_import_id ON orders (import_id) WHERE import_id <> '';
\ No newline at end of file
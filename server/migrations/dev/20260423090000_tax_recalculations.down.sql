@@ -0,0 +1,2 @@
+DROP TABLE tax_recalculations;
+DROP TYPE tax_recalculation_status;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TYPE "tax_recalculation_status" AS ENUM('previewed','applying','applied','failed');
+
+CREATE TABLE "tax_recalculations" (
+    "id" BIGSERIAL PRIMARY KEY,
+
+    "status" tax_recalculation_status NOT NULL DEFAULT 'previewed',
+    "rates_version" VARCHAR(64) NOT NULL,
+
+    "filter" JSONB NOT NULL,
+    "changes" JSONB NOT NULL,
+
+    "unchanged" INTEGER NOT NULL DEFAULT 0,
+    "skipped" INTEGER NOT NULL DEFAULT 0,
+    "applied" INTEGER NOT NULL DEFAULT 0,
+    "conflicts" INTEGER NOT NULL DEFAULT 0,
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "applied_at" TIMESTAMPTZ
+);
\ No newline at end of file
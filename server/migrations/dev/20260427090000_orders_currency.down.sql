@@ -0,0 +1,6 @@
+DROP TABLE exchange_rates;
+
+ALTER TABLE "orders"
+    DROP COLUMN "original_amounts",
+    DROP COLUMN "exchange_rate",
+    DROP COLUMN "currency";
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE "orders"
+    ADD COLUMN "currency" VARCHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN "exchange_rate" NUMERIC(36, 18) NOT NULL DEFAULT 1,
+    ADD COLUMN "original_amounts" JSONB;
+
+CREATE TABLE "exchange_rates" (
+    "currency" VARCHAR(3) NOT NULL,
+    "date" DATE NOT NULL,
+    "rate" NUMERIC(36, 18) NOT NULL CHECK ("rate" > 0),
+
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY ("currency", "date")
+);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX idx_orders_tags_gin;
+DROP INDEX idx_orders_metadata_gin;
+
+ALTER TABLE "orders"
+    DROP COLUMN "tags",
+    DROP COLUMN "metadata";
\ No newline at end of file
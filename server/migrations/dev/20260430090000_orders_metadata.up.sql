@@ -0,0 +1,6 @@
+ALTER TABLE "orders"
+    ADD COLUMN "metadata" JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN "tags" TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_orders_metadata_gin ON orders USING GIN (metadata jsonb_path_ops);
+CREATE INDEX idx_orders_tags_gin ON orders USING GIN (tags);
\ No newline at end of file
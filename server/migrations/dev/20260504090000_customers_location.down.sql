@@ -0,0 +1,9 @@
+DROP INDEX idx_orders_customer_id;
+
+ALTER TABLE "orders" DROP COLUMN "customer_id";
+
+ALTER TABLE "customers"
+    DROP COLUMN "longitude",
+    DROP COLUMN "latitude",
+    DROP COLUMN "zip",
+    DROP COLUMN "address";
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE "customers"
+    ADD COLUMN "address" VARCHAR(256) NOT NULL DEFAULT '',
+    ADD COLUMN "zip" VARCHAR(10) NOT NULL DEFAULT '',
+    ADD COLUMN "latitude" NUMERIC(36, 18),
+    ADD COLUMN "longitude" NUMERIC(36, 18);
+
+ALTER TABLE "orders" ADD COLUMN "customer_id" BIGINT REFERENCES customers (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_orders_customer_id ON orders (customer_id);
\ No newline at end of file
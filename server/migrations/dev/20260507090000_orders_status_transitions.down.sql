@@ -0,0 +1,9 @@
+ALTER TABLE orders DROP COLUMN "status_transitions";
+
+-- enum values cannot be dropped, so the type is recreated without them.
+UPDATE orders SET status = 'completed' WHERE status = 'pending';
+UPDATE orders SET status = 'out_of_scope' WHERE status = 'failed_tax_resolution';
+ALTER TYPE order_status RENAME TO order_status_old;
+CREATE TYPE "order_status" AS ENUM('completed','out_of_scope','partially_refunded','refunded','voided');
+ALTER TABLE orders ALTER COLUMN status TYPE order_status USING status::text::order_status;
+DROP TYPE order_status_old;
\ No newline at end of file
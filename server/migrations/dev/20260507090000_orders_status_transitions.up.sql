@@ -0,0 +1,9 @@
+ALTER TYPE order_status ADD VALUE 'pending';
+ALTER TYPE order_status ADD VALUE 'failed_tax_resolution';
+
+ALTER TABLE orders ADD COLUMN "status_transitions" JSONB NOT NULL DEFAULT '[]';
+
+-- existing orders resolved their tax when they were created.
+UPDATE orders SET status_transitions = jsonb_build_array(
+    jsonb_build_object('from', 'pending', 'to', status, 'at', created_at)
+);
\ No newline at end of file
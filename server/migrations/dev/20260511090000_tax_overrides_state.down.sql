@@ -0,0 +1 @@
+ALTER TABLE tax_overrides DROP COLUMN "state";
@@ -0,0 +1,2 @@
+-- An empty state means the primary state.
+ALTER TABLE tax_overrides ADD COLUMN "state" VARCHAR(8) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX idx_boundary_sets_single_active;
+CREATE UNIQUE INDEX idx_boundary_sets_single_active ON boundary_sets (status) WHERE status = 'active';
+
+ALTER TABLE boundary_sets DROP COLUMN "state";
@@ -0,0 +1,5 @@
+-- An empty state means the primary state.
+ALTER TABLE boundary_sets ADD COLUMN "state" VARCHAR(8) NOT NULL DEFAULT '';
+
+DROP INDEX idx_boundary_sets_single_active;
+CREATE UNIQUE INDEX idx_boundary_sets_single_active ON boundary_sets (state) WHERE status = 'active';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "wipe_confirmations";
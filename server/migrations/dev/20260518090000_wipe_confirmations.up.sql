@@ -0,0 +1,8 @@
+-- The pending confirmation of a wipe of all orders, shared by every
+-- instance of the server. A single row holds the latest token only.
+CREATE TABLE "wipe_confirmations" (
+    "id" BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK ("id"),
+    -- sha256 of the token, the token itself is never stored
+    "token_hash" VARCHAR(64) NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL
+);
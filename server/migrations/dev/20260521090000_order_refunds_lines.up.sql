@@ -0,0 +1,3 @@
+-- How every refund is allocated to the components and item lines of the
+-- order. Refunds recorded before are spread over the order as a whole.
+ALTER TABLE order_refunds ADD COLUMN "lines" JSONB NOT NULL DEFAULT '[]';
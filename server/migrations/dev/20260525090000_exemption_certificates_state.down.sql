@@ -0,0 +1 @@
+ALTER TABLE exemption_certificates DROP COLUMN "state";